@@ -0,0 +1,185 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+
+	"pvz-service/internal/config"
+	grpcserver "pvz-service/internal/grpc"
+	"pvz-service/internal/health"
+)
+
+// Lifecycle owns the HTTP, gRPC and metrics servers and the database pool.
+// Run serves until its context is cancelled or a server fails, then drains
+// the servers and closes the pool once nothing can use it any more.
+type Lifecycle struct {
+	httpApp         *fiber.App
+	grpcServer      *grpc.Server
+	grpcHealth      *grpchealth.Server
+	checker         *health.Checker
+	metricsServer   *http.Server
+	database        *sql.DB
+	httpAddr        string
+	grpcAddr        string
+	metricsAddr     string
+	shutdownTimeout time.Duration
+	shutdownDelay   time.Duration
+}
+
+func NewLifecycle(
+	cfg config.Config, httpApp *fiber.App, grpcServer *grpc.Server, grpcHealth *grpchealth.Server,
+	checker *health.Checker, database *sql.DB,
+) *Lifecycle {
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", promhttp.Handler())
+
+	return &Lifecycle{
+		httpApp:         httpApp,
+		grpcServer:      grpcServer,
+		grpcHealth:      grpcHealth,
+		checker:         checker,
+		metricsServer:   &http.Server{Handler: metricsMux, ReadHeaderTimeout: 5 * time.Second},
+		database:        database,
+		httpAddr:        "0.0.0.0:" + cfg.Port,
+		grpcAddr:        ":" + cfg.GRPCPort,
+		metricsAddr:     ":" + cfg.MetricsPort,
+		shutdownTimeout: cfg.ShutdownTimeout,
+		shutdownDelay:   cfg.ShutdownDelay,
+	}
+}
+
+func (l *Lifecycle) Run(ctx context.Context) error {
+	listeners, err := l.listen()
+	if err != nil {
+		l.database.Close()
+		return err
+	}
+
+	// Buffered so that servers failing after shutdown started never block.
+	serveErrs := make(chan error, 3)
+	go func() {
+		slog.Info("HTTP server listening", "addr", listeners[0].Addr().String())
+		serveErrs <- wrapServeErr("HTTP", l.httpApp.Listener(listeners[0]))
+	}()
+	go func() {
+		serveErrs <- wrapServeErr("gRPC", grpcserver.StartGRPCServer(l.grpcServer, listeners[1], l.grpcHealth))
+	}()
+	go func() {
+		slog.Info("Metrics server listening", "addr", listeners[2].Addr().String())
+		err := l.metricsServer.Serve(listeners[2])
+		if errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		}
+		serveErrs <- wrapServeErr("metrics", err)
+	}()
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		slog.Info("Shutting down")
+	case runErr = <-serveErrs:
+		slog.Error("Shutting down after server failure", "error", runErr)
+	}
+
+	return errors.Join(runErr, l.shutdown())
+}
+
+func (l *Lifecycle) listen() ([]net.Listener, error) {
+	var listeners []net.Listener
+	for _, addr := range []string{l.httpAddr, l.grpcAddr, l.metricsAddr} {
+		lis, err := net.Listen("tcp", addr)
+		if err != nil {
+			for _, opened := range listeners {
+				opened.Close()
+			}
+			return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+		}
+		listeners = append(listeners, lis)
+	}
+	return listeners, nil
+}
+
+// shutdown reports not ready, gives load balancers shutdownDelay to notice,
+// then stops accepting connections on all servers at once and waits up to
+// shutdownTimeout for in-flight requests before cutting them off. The database
+// is closed only after every server has stopped.
+func (l *Lifecycle) shutdown() error {
+	l.checker.Shutdown()
+	l.grpcHealth.Shutdown()
+	time.Sleep(l.shutdownDelay)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+	record := func(err error) {
+		if err != nil {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		}
+	}
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		record(wrapShutdownErr("HTTP", l.httpApp.ShutdownWithTimeout(l.shutdownTimeout)))
+	}()
+	go func() {
+		defer wg.Done()
+		l.stopGRPC()
+	}()
+	go func() {
+		defer wg.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), l.shutdownTimeout)
+		defer cancel()
+		record(wrapShutdownErr("metrics", l.metricsServer.Shutdown(ctx)))
+	}()
+	wg.Wait()
+
+	record(wrapShutdownErr("database", l.database.Close()))
+	return errors.Join(errs...)
+}
+
+// stopGRPC waits for unary calls to finish, but streams such as WatchPVZEvents
+// only end when the client leaves, so they are cut off after the timeout.
+func (l *Lifecycle) stopGRPC() {
+	stopped := make(chan struct{})
+	go func() {
+		l.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(l.shutdownTimeout):
+		slog.Warn("gRPC graceful stop timed out, closing remaining streams")
+		l.grpcServer.Stop()
+		<-stopped
+	}
+}
+
+func wrapServeErr(server string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s server: %w", server, err)
+}
+
+func wrapShutdownErr(component string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("shut down %s: %w", component, err)
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+
+	"pvz-service/internal/config"
+	"pvz-service/internal/health"
+)
+
+func freePort(t *testing.T) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer lis.Close()
+	return strconv.Itoa(lis.Addr().(*net.TCPAddr).Port)
+}
+
+func testLifecycleConfig(t *testing.T) config.Config {
+	return config.Config{
+		Port:            freePort(t),
+		GRPCPort:        freePort(t),
+		MetricsPort:     freePort(t),
+		ShutdownTimeout: 2 * time.Second,
+	}
+}
+
+func TestLifecycle_DrainsInFlightRequestsAndClosesDB(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	mock.ExpectClose()
+
+	started := make(chan struct{})
+	httpApp := fiber.New(fiber.Config{DisableStartupMessage: true})
+	httpApp.Get("/slow", func(c *fiber.Ctx) error {
+		close(started)
+		time.Sleep(300 * time.Millisecond)
+		return c.SendString("done")
+	})
+
+	cfg := testLifecycleConfig(t)
+	checker := health.NewChecker(time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- NewLifecycle(cfg, httpApp, grpc.NewServer(), grpchealth.NewServer(), checker, db).Run(ctx)
+	}()
+
+	status := make(chan int, 1)
+	go func() {
+		url := fmt.Sprintf("http://127.0.0.1:%s/slow", cfg.Port)
+		for i := 0; i < 50; i++ {
+			resp, err := http.Get(url)
+			if err == nil {
+				resp.Body.Close()
+				status <- resp.StatusCode
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		status <- 0
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("request did not reach the handler")
+	}
+	cancel()
+
+	assert.Equal(t, http.StatusOK, <-status)
+	assert.NoError(t, <-runErr)
+	assert.False(t, checker.Ready(context.Background()).OK())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLifecycle_PortInUse(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	mock.ExpectClose()
+
+	busy, err := net.Listen("tcp", ":0")
+	assert.NoError(t, err)
+	defer busy.Close()
+
+	cfg := testLifecycleConfig(t)
+	cfg.MetricsPort = strconv.Itoa(busy.Addr().(*net.TCPAddr).Port)
+
+	err = NewLifecycle(cfg, fiber.New(), grpc.NewServer(), grpchealth.NewServer(), health.NewChecker(time.Second), db).
+		Run(context.Background())
+	assert.ErrorContains(t, err, "failed to listen")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
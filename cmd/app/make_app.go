@@ -4,68 +4,170 @@ import (
 	"database/sql"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
+	"log/slog"
 	"pvz-service/internal/config"
+	"pvz-service/internal/events"
 	"pvz-service/internal/handler"
+	"pvz-service/internal/jwtkeys"
+	"pvz-service/internal/loginguard"
 	"pvz-service/internal/middleware"
+	"pvz-service/internal/notify"
+	"pvz-service/internal/passwordpolicy"
 	"pvz-service/internal/prometheus"
 	"pvz-service/internal/repository"
 	"pvz-service/internal/service"
 )
 
-func MakeApp(database *sql.DB, cfg config.Config) *fiber.App {
+func MakeApp(
+	database *sql.DB, cfg config.Config, broker *events.Broker, keys *jwtkeys.KeySet,
+	passwordPolicy *passwordpolicy.Policy, checker handler.ReadinessChecker, logger *slog.Logger,
+) *fiber.App {
 	// Initialize repositories
 	authRepo := repository.NewAuthRepository(database)
+	sessionRepo := repository.NewSessionRepository(database)
+	assignmentRepo := repository.NewAssignmentRepository(database)
+	userRepo := repository.NewUserRepository(database)
+	passwordResetRepo := repository.NewPasswordResetRepository(database)
+	apiKeyRepo := repository.NewAPIKeyRepository(database)
 	pvzRepo := repository.NewPVZRepository(database)
+	cityRepo := repository.NewCityRepository(database)
 	receptionRepo := repository.NewReceptionRepository(database)
 	productRepo := repository.NewProductRepository(database)
+	productTypeRepo := repository.NewProductTypeRepository(database)
+	txManager := repository.NewTxManager(database)
 
 	// Initialize service
-	authProcessor := service.NewAuthService(authRepo)
-	pvzProcessor := service.NewPVZService(pvzRepo)
-	receptionProcessor := service.NewReceptionService(receptionRepo)
-	productProcessor := service.NewProductService(productRepo, receptionRepo)
+	authProcessor := service.NewAuthService(
+		authRepo, sessionRepo, newLoginGuard(database, cfg), passwordPolicy, cfg.RefreshTokenTTL, cfg.AllowSignup)
+	pvzProcessor := service.NewPVZService(pvzRepo, cityRepo)
+	cityProcessor := service.NewCityService(cityRepo)
+	receptionProcessor := service.NewReceptionService(receptionRepo, productRepo, pvzRepo, txManager, broker)
+	productProcessor := service.NewProductService(txManager, productTypeRepo, broker)
+	productTypeProcessor := service.NewProductTypeService(productTypeRepo)
+	assignmentProcessor := service.NewAssignmentService(assignmentRepo, authRepo, pvzRepo)
+	userProcessor := service.NewUserService(userRepo, sessionRepo)
+	passwordProcessor := service.NewPasswordService(
+		authRepo, sessionRepo, passwordResetRepo, passwordPolicy, newNotifier(cfg), cfg.PasswordResetTTL)
+	apiKeyProcessor := service.NewAPIKeyService(apiKeyRepo)
 
 	// Initialize handler
-	authHandlers := handler.NewAuthHandlers(authProcessor, cfg.JWTSecret)
+	authHandlers := handler.NewAuthHandlers(authProcessor, keys)
 	pvzHandlers := handler.NewPVZHandlers(pvzProcessor)
-	receptionHandlers := handler.NewReceptionHandlers(receptionProcessor)
+	cityHandlers := handler.NewCityHandlers(cityProcessor)
+	receptionHandlers := handler.NewReceptionHandlers(receptionProcessor, middleware.CheckPVZAccess(assignmentProcessor))
 	productHandlers := handler.NewProductHandlers(productProcessor)
+	productTypeHandlers := handler.NewProductTypeHandlers(productTypeProcessor)
+	assignmentHandlers := handler.NewAssignmentHandlers(assignmentProcessor)
+	userHandlers := handler.NewUserHandlers(userProcessor)
+	passwordHandlers := handler.NewPasswordHandlers(passwordProcessor)
+	apiKeyHandlers := handler.NewAPIKeyHandlers(apiKeyProcessor)
+	healthHandlers := handler.NewHealthHandlers(checker)
 
 	app := fiber.New()
 
-	app.Use(cors.New())
-	app.Use(logger.New(logger.Config{
-		Format:     "${time} | ${status} | ${latency} | ${method} ${path}\n",
-		TimeFormat: "2006-01-02 15:04:05",
-	}))
+	if cfg.CORSAllowOrigins != "" {
+		app.Use(cors.New(cors.Config{AllowOrigins: cfg.CORSAllowOrigins}))
+	}
+	app.Use(middleware.RequestLogger(logger))
 	app.Use(prometheus.PrometheusMiddleware())
+	app.Use(middleware.ContextTimeout(cfg.DBTimeout))
 
-	// Health check
-	app.Get("/health", func(c *fiber.Ctx) error {
-		return c.SendString("OK")
-	})
+	// Health check; /health is kept for existing monitors: same check as /livez, plain "OK" body
+	app.Get("/health", healthHandlers.HealthHandler())
+	app.Get("/livez", healthHandlers.LivezHandler())
+	app.Get("/readyz", healthHandlers.ReadyzHandler())
 
 	// Public Routes
-	app.Post("/dummyLogin", authHandlers.DummyLoginHandler())
+	app.Get("/.well-known/jwks.json", authHandlers.JWKSHandler())
+	if cfg.DummyLogin {
+		app.Post("/dummyLogin", authHandlers.DummyLoginHandler())
+	}
 	app.Post("/register", authHandlers.RegisterHandler())
 	app.Post("/login", authHandlers.LoginHandler())
+	app.Post("/token/refresh", authHandlers.RefreshTokenHandler())
+	app.Post("/password/reset/request", passwordHandlers.RequestPasswordResetHandler())
+	app.Post("/password/reset", passwordHandlers.ResetPasswordHandler())
 
 	// Protected Routes
 	api := app.Group("/")
-	api.Use(middleware.AuthMiddleware(cfg.JWTSecret))
+	api.Use(middleware.AuthMiddleware(keys, authProcessor, apiKeyProcessor))
+
+	api.Post("/logout", authHandlers.LogoutHandler())
+	api.Post("/users/me/password", passwordHandlers.ChangePasswordHandler())
 
 	// Routes configuration with role checks
 	api.Post("/pvz", middleware.CheckRole("moderator"), pvzHandlers.CreatePVZHandler())
 	api.Get("/pvz", middleware.CheckRole("employee", "moderator"), pvzHandlers.GetPVZListHandler())
-	api.Post("/receptions", middleware.CheckRole("employee"), receptionHandlers.CreateReceptionHandler())
-	api.Post("/products", middleware.CheckRole("employee"), productHandlers.AddProductHandler())
+
+	// Employees operate only on the PVZs they are assigned to
+	pvzAccess := middleware.RequirePVZAccess(assignmentProcessor)
+	api.Post("/receptions", middleware.CheckRole("employee"), pvzAccess, receptionHandlers.CreateReceptionHandler())
+	api.Post("/products", middleware.CheckRole("employee"), pvzAccess, productHandlers.AddProductHandler())
 	api.Post(
 		"/pvz/:pvzId/close_last_reception",
-		middleware.CheckRole("employee"), receptionHandlers.CloseLastReceptionHandler())
+		middleware.CheckRole("employee"), pvzAccess, receptionHandlers.CloseLastReceptionHandler())
 	api.Post(
 		"/pvz/:pvzId/delete_last_product",
-		middleware.CheckRole("employee"), productHandlers.DeleteLastProductHandler())
+		middleware.CheckRole("employee"), pvzAccess, productHandlers.DeleteLastProductHandler())
+	api.Get(
+		"/pvz/:pvzId/receptions",
+		middleware.CheckRole("employee", "moderator"), pvzAccess, receptionHandlers.ListPVZReceptionsHandler())
+	api.Get(
+		"/receptions/:receptionId",
+		middleware.CheckRole("employee", "moderator"), receptionHandlers.GetReceptionHandler())
+	api.Get(
+		"/receptions/:receptionId/products",
+		middleware.CheckRole("employee", "moderator"), receptionHandlers.ListReceptionProductsHandler())
+
+	api.Get("/users/:userId/pvz", middleware.CheckRole("moderator"), assignmentHandlers.ListUserPVZsHandler())
+	api.Put("/users/:userId/pvz/:pvzId", middleware.CheckRole("moderator"), assignmentHandlers.AssignPVZHandler())
+	api.Delete(
+		"/users/:userId/pvz/:pvzId",
+		middleware.CheckRole("moderator"), assignmentHandlers.UnassignPVZHandler())
+
+	api.Get("/users", middleware.CheckRole("moderator", "admin"), userHandlers.ListUsersHandler())
+	api.Get("/users/:userId", middleware.CheckRole("moderator", "admin"), userHandlers.GetUserHandler())
+	api.Patch("/users/:userId", middleware.CheckRole("moderator", "admin"), userHandlers.UpdateUserHandler())
+	api.Delete("/users/:userId", middleware.CheckRole("admin"), userHandlers.DeleteUserHandler())
+
+	api.Get("/api-keys", middleware.CheckRole("moderator"), apiKeyHandlers.ListAPIKeysHandler())
+	api.Post("/api-keys", middleware.CheckRole("moderator"), apiKeyHandlers.CreateAPIKeyHandler())
+	api.Delete("/api-keys/:keyId", middleware.CheckRole("moderator"), apiKeyHandlers.RevokeAPIKeyHandler())
+
+	api.Get("/cities", middleware.CheckRole("moderator"), cityHandlers.ListCitiesHandler())
+	api.Post("/cities", middleware.CheckRole("moderator"), cityHandlers.CreateCityHandler())
+	api.Patch("/cities/:cityId", middleware.CheckRole("moderator"), cityHandlers.UpdateCityHandler())
+	api.Delete("/cities/:cityId", middleware.CheckRole("moderator"), cityHandlers.DeleteCityHandler())
+
+	api.Get("/product_types", middleware.CheckRole("moderator"), productTypeHandlers.ListProductTypesHandler())
+	api.Post("/product_types", middleware.CheckRole("moderator"), productTypeHandlers.CreateProductTypeHandler())
+	api.Patch(
+		"/product_types/:code",
+		middleware.CheckRole("moderator"), productTypeHandlers.UpdateProductTypeHandler())
+	api.Delete(
+		"/product_types/:code",
+		middleware.CheckRole("moderator"), productTypeHandlers.DeleteProductTypeHandler())
 
 	return app
 }
+
+func newLoginGuard(database *sql.DB, cfg config.Config) *loginguard.Guard {
+	var store loginguard.Store = repository.NewLoginAttemptRepository(database)
+	if cfg.LoginAttemptStore == config.LoginAttemptStoreMemory {
+		store = loginguard.NewMemoryStore()
+	}
+
+	return loginguard.NewGuard(store, loginguard.Config{
+		MaxAttempts:      cfg.LoginMaxAttempts,
+		MaxAttemptsPerIP: cfg.LoginMaxAttemptsPerIP,
+		Window:           cfg.LoginAttemptWindow,
+		Lockout:          cfg.LoginLockout,
+	})
+}
+
+func newNotifier(cfg config.Config) notify.Notifier {
+	if cfg.Notifier == config.NotifierFile {
+		return notify.NewFileNotifier(cfg.NotifierFile)
+	}
+	return notify.NewLogNotifier()
+}
@@ -0,0 +1,36 @@
+package app
+
+import (
+	"database/sql"
+	"io"
+
+	"pvz-service/cmd/cli"
+	"pvz-service/internal/config"
+	"pvz-service/internal/events"
+	"pvz-service/internal/passwordpolicy"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/service"
+)
+
+func MakeCLI(database *sql.DB, cfg config.Config, passwordPolicy *passwordpolicy.Policy, out io.Writer) *cli.CLI {
+	// Initialize repositories
+	authRepo := repository.NewAuthRepository(database)
+	sessionRepo := repository.NewSessionRepository(database)
+	userRepo := repository.NewUserRepository(database)
+	pvzRepo := repository.NewPVZRepository(database)
+	cityRepo := repository.NewCityRepository(database)
+	receptionRepo := repository.NewReceptionRepository(database)
+	productRepo := repository.NewProductRepository(database)
+	txManager := repository.NewTxManager(database)
+
+	// Initialize service; nobody subscribes to events inside a CLI run
+	authProcessor := service.NewAuthService(
+		authRepo, sessionRepo, nil, passwordPolicy, cfg.RefreshTokenTTL, cfg.AllowSignup)
+	userProcessor := service.NewUserService(userRepo, sessionRepo)
+	pvzProcessor := service.NewPVZService(pvzRepo, cityRepo)
+	cityProcessor := service.NewCityService(cityRepo)
+	receptionProcessor := service.NewReceptionService(
+		receptionRepo, productRepo, pvzRepo, txManager, events.NewBroker(0, 0))
+
+	return cli.New(authProcessor, userProcessor, pvzProcessor, cityProcessor, receptionProcessor, out)
+}
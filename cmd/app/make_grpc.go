@@ -0,0 +1,40 @@
+package app
+
+import (
+	"database/sql"
+	"log/slog"
+
+	"google.golang.org/grpc"
+
+	"pvz-service/internal/config"
+	"pvz-service/internal/events"
+	grpcserver "pvz-service/internal/grpc"
+	"pvz-service/internal/jwtkeys"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/service"
+)
+
+func MakeGRPCServer(
+	database *sql.DB, cfg config.Config, broker *events.Broker, keys *jwtkeys.KeySet, logger *slog.Logger,
+) *grpc.Server {
+	// Initialize repositories
+	authRepo := repository.NewAuthRepository(database)
+	sessionRepo := repository.NewSessionRepository(database)
+	assignmentRepo := repository.NewAssignmentRepository(database)
+	pvzRepo := repository.NewPVZRepository(database)
+	cityRepo := repository.NewCityRepository(database)
+	receptionRepo := repository.NewReceptionRepository(database)
+	productRepo := repository.NewProductRepository(database)
+	productTypeRepo := repository.NewProductTypeRepository(database)
+	txManager := repository.NewTxManager(database)
+
+	// Initialize service
+	authProcessor := service.NewAuthService(authRepo, sessionRepo, nil, nil, cfg.RefreshTokenTTL, cfg.AllowSignup)
+	pvzProcessor := service.NewPVZService(pvzRepo, cityRepo)
+	receptionProcessor := service.NewReceptionService(receptionRepo, productRepo, pvzRepo, txManager, broker)
+	productProcessor := service.NewProductService(txManager, productTypeRepo, broker)
+	assignmentProcessor := service.NewAssignmentService(assignmentRepo, authRepo, pvzRepo)
+
+	server := grpcserver.NewPVZServer(pvzProcessor, receptionProcessor, productProcessor, assignmentProcessor, broker)
+	return grpcserver.NewGRPCServer(server, cfg.DBTimeout, keys, authProcessor, assignmentProcessor, logger)
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"pvz-service/internal/config"
+	"pvz-service/internal/health"
+	"pvz-service/internal/migrate"
+	"pvz-service/migrations"
+)
+
+// MakeHealth builds the readiness checker behind /readyz and the
+// grpc.health.v1 server whose status it includes. Both start as not ready
+// for gRPC until StartGRPCServer reports serving.
+func MakeHealth(database *sql.DB, cfg config.Config) (*health.Checker, *grpchealth.Server, error) {
+	migrator, err := migrate.New(database, migrations.FS)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	grpcHealth := grpchealth.NewServer()
+	grpcHealth.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	checker := health.NewChecker(cfg.HealthCheckTimeout)
+	checker.Add("database", database.PingContext)
+	checker.Add("migrations", migrator.CheckApplied)
+	checker.Add("grpc", func(ctx context.Context) error {
+		resp, err := grpcHealth.Check(ctx, &healthpb.HealthCheckRequest{})
+		if err != nil {
+			return err
+		}
+		if resp.Status != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("gRPC server is %s", resp.Status)
+		}
+		return nil
+	})
+
+	return checker, grpcHealth, nil
+}
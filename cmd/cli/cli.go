@@ -0,0 +1,305 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/service"
+)
+
+const Usage = `usage:
+  user create -email E -password P [-role employee|moderator|admin]
+  user disable USER_ID
+  user set-role USER_ID ROLE
+  pvz create -city CITY
+  pvz list
+  reception force-close PVZ_ID
+  seed -pvz N -receptions M
+  export
+every command accepts -json`
+
+var errUsage = errors.New(Usage)
+
+// CLI runs operator commands on top of the services used by the API. It acts
+// with admin rights, so it must only be reachable from the service's own host.
+type CLI struct {
+	auth       service.AuthService
+	users      service.UserService
+	pvzs       service.PVZService
+	cities     service.CityService
+	receptions service.ReceptionService
+	out        io.Writer
+}
+
+func New(
+	auth service.AuthService, users service.UserService, pvzs service.PVZService, cities service.CityService,
+	receptions service.ReceptionService, out io.Writer,
+) *CLI {
+	return &CLI{auth: auth, users: users, pvzs: pvzs, cities: cities, receptions: receptions, out: out}
+}
+
+func (c *CLI) Run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	command := args[0]
+	if len(args) > 1 && (command == "user" || command == "pvz" || command == "reception") {
+		command += " " + args[1]
+		args = args[1:]
+	}
+
+	switch command {
+	case "user create":
+		return c.createUser(ctx, args[1:])
+	case "user disable":
+		return c.disableUser(ctx, args[1:])
+	case "user set-role":
+		return c.setUserRole(ctx, args[1:])
+	case "pvz create":
+		return c.createPVZ(ctx, args[1:])
+	case "pvz list":
+		return c.listPVZs(ctx, args[1:])
+	case "reception force-close":
+		return c.forceCloseReception(ctx, args[1:])
+	case "seed":
+		return c.seed(ctx, args[1:])
+	case "export":
+		return c.export(ctx, args[1:])
+	default:
+		return errUsage
+	}
+}
+
+// parse parses flags for a command and checks the number of positional
+// arguments left. The returned bool reports whether -json was set.
+func parse(name string, flags *flag.FlagSet, args []string, positional int) ([]string, bool, error) {
+	if flags == nil {
+		flags = flag.NewFlagSet(name, flag.ContinueOnError)
+	}
+	flags.SetOutput(io.Discard)
+	asJSON := flags.Bool("json", false, "print JSON")
+
+	// Flags may come after the positional arguments, e.g. "user disable ID -json".
+	var rest []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, false, fmt.Errorf("%s: %w", name, err)
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			break
+		}
+		rest = append(rest, args[0])
+		args = args[1:]
+	}
+
+	if len(rest) != positional {
+		return nil, false, errUsage
+	}
+	return rest, *asJSON, nil
+}
+
+func (c *CLI) print(asJSON bool, v any, text string) error {
+	if asJSON {
+		encoder := json.NewEncoder(c.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+	_, err := fmt.Fprintln(c.out, text)
+	return err
+}
+
+func userLine(user domain.User) string {
+	return fmt.Sprintf("%s\t%s\t%s\tdisabled=%t", user.ID, user.Email, user.Role, user.Disabled)
+}
+
+func pvzLine(pvz domain.PVZ) string {
+	return fmt.Sprintf("%s\t%s\t%s", pvz.ID, pvz.City, pvz.RegistrationDate.Format(time.RFC3339))
+}
+
+func (c *CLI) createUser(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("user create", flag.ContinueOnError)
+	email := flags.String("email", "", "user email")
+	password := flags.String("password", "", "user password")
+	role := flags.String("role", domain.RoleEmployee, "user role")
+
+	_, asJSON, err := parse("user create", flags, args, 0)
+	if err != nil {
+		return err
+	}
+
+	userID, err := c.auth.CreateUser(ctx, *email, *password, *role)
+	if err != nil {
+		return err
+	}
+	user, err := c.users.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return c.print(asJSON, user, userLine(user))
+}
+
+func (c *CLI) disableUser(ctx context.Context, args []string) error {
+	rest, asJSON, err := parse("user disable", nil, args, 1)
+	if err != nil {
+		return err
+	}
+
+	disabled := true
+	user, err := c.users.UpdateUser(ctx, "", domain.RoleAdmin, rest[0], nil, &disabled)
+	if err != nil {
+		return err
+	}
+	return c.print(asJSON, user, userLine(user))
+}
+
+func (c *CLI) setUserRole(ctx context.Context, args []string) error {
+	rest, asJSON, err := parse("user set-role", nil, args, 2)
+	if err != nil {
+		return err
+	}
+
+	user, err := c.users.UpdateUser(ctx, "", domain.RoleAdmin, rest[0], &rest[1], nil)
+	if err != nil {
+		return err
+	}
+	return c.print(asJSON, user, userLine(user))
+}
+
+func (c *CLI) createPVZ(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("pvz create", flag.ContinueOnError)
+	city := flags.String("city", "", "PVZ city")
+
+	_, asJSON, err := parse("pvz create", flags, args, 0)
+	if err != nil {
+		return err
+	}
+
+	pvz, err := c.pvzs.CreatePVZ(ctx, *city)
+	if err != nil {
+		return err
+	}
+	return c.print(asJSON, pvz, pvzLine(pvz))
+}
+
+func (c *CLI) listPVZs(ctx context.Context, args []string) error {
+	_, asJSON, err := parse("pvz list", nil, args, 0)
+	if err != nil {
+		return err
+	}
+
+	pvzs, err := c.pvzs.ListPVZs(ctx)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		return c.print(true, pvzs, "")
+	}
+	for _, pvz := range pvzs {
+		if err := c.print(false, nil, pvzLine(pvz)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forceCloseReception closes the open reception of a PVZ on behalf of its
+// employees, e.g. when a reception was left open by mistake.
+func (c *CLI) forceCloseReception(ctx context.Context, args []string) error {
+	rest, asJSON, err := parse("reception force-close", nil, args, 1)
+	if err != nil {
+		return err
+	}
+
+	reception, err := c.receptions.CloseLastReception(ctx, rest[0])
+	if err != nil {
+		return err
+	}
+	return c.print(asJSON, reception,
+		fmt.Sprintf("%s\t%s\t%s", reception.ID, reception.PvzId, reception.Status))
+}
+
+type seedResult struct {
+	PVZIDs     []string `json:"pvzIds"`
+	Receptions int      `json:"receptions"`
+}
+
+// seed creates PVZs spread over the active cities, each with closed receptions,
+// as a starting point for load tests.
+func (c *CLI) seed(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	pvzCount := flags.Int("pvz", 10, "number of PVZs to create")
+	receptionCount := flags.Int("receptions", 0, "number of closed receptions per PVZ")
+
+	_, asJSON, err := parse("seed", flags, args, 0)
+	if err != nil {
+		return err
+	}
+	if *pvzCount < 0 || *receptionCount < 0 {
+		return errors.New("seed: -pvz and -receptions must not be negative")
+	}
+
+	cities, err := c.cities.ListCities(ctx, false)
+	if err != nil {
+		return err
+	}
+	if len(cities) == 0 {
+		return errors.New("seed: no active cities")
+	}
+
+	result := seedResult{PVZIDs: make([]string, 0, *pvzCount)}
+	for i := 0; i < *pvzCount; i++ {
+		pvz, err := c.pvzs.CreatePVZ(ctx, cities[i%len(cities)].Name)
+		if err != nil {
+			return err
+		}
+		result.PVZIDs = append(result.PVZIDs, pvz.ID)
+
+		for j := 0; j < *receptionCount; j++ {
+			if _, err := c.receptions.CreateReception(ctx, pvz.ID); err != nil {
+				return err
+			}
+			if _, err := c.receptions.CloseLastReception(ctx, pvz.ID); err != nil {
+				return err
+			}
+			result.Receptions++
+		}
+	}
+
+	return c.print(asJSON, result,
+		fmt.Sprintf("created %d PVZs and %d receptions", len(result.PVZIDs), result.Receptions))
+}
+
+// export dumps every PVZ with its receptions and products. The dump is always
+// JSON, -json is accepted for symmetry with the other commands.
+func (c *CLI) export(ctx context.Context, args []string) error {
+	if _, _, err := parse("export", nil, args, 0); err != nil {
+		return err
+	}
+
+	// The cursor walk is keyed on (registration_date, id), so PVZs created during the
+	// export cannot shift pages and cause rows to be skipped or repeated.
+	items := make([]repository.PVZResponse, 0)
+	cursor := ""
+	for {
+		resp, err := c.pvzs.ListPVZsByCursor(ctx, repository.PVZFilter{}, cursor, service.MaxCursorPageLimit)
+		if err != nil {
+			return err
+		}
+		items = append(items, resp.Items...)
+		if resp.NextCursor == "" {
+			break
+		}
+		cursor = resp.NextCursor
+	}
+	return c.print(true, items, "")
+}
@@ -0,0 +1,224 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/service"
+)
+
+// The mocks embed the service interfaces so that only the methods the CLI
+// calls need to be implemented.
+
+type MockAuthService struct {
+	service.AuthService
+	mock.Mock
+}
+
+func (m *MockAuthService) CreateUser(ctx context.Context, email, password, role string) (string, error) {
+	args := m.Called(email, password, role)
+	return args.String(0), args.Error(1)
+}
+
+type MockUserService struct {
+	service.UserService
+	mock.Mock
+}
+
+func (m *MockUserService) GetUser(ctx context.Context, id string) (domain.User, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func (m *MockUserService) UpdateUser(
+	ctx context.Context, actorID, actorRole, id string, role *string, disabled *bool) (domain.User, error) {
+	args := m.Called(actorID, actorRole, id, role, disabled)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+type MockPVZService struct {
+	service.PVZService
+	mock.Mock
+}
+
+func (m *MockPVZService) CreatePVZ(ctx context.Context, city string) (domain.PVZ, error) {
+	args := m.Called(city)
+	return args.Get(0).(domain.PVZ), args.Error(1)
+}
+
+func (m *MockPVZService) ListPVZs(ctx context.Context) ([]domain.PVZ, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.PVZ), args.Error(1)
+}
+
+func (m *MockPVZService) ListPVZsByCursor(ctx context.Context, filter repository.PVZFilter,
+	cursor string, limit int) (repository.PVZCursorResponse, error) {
+	args := m.Called(cursor, limit)
+	return args.Get(0).(repository.PVZCursorResponse), args.Error(1)
+}
+
+type MockCityService struct {
+	service.CityService
+	mock.Mock
+}
+
+func (m *MockCityService) ListCities(ctx context.Context, includeInactive bool) ([]domain.City, error) {
+	args := m.Called(includeInactive)
+	return args.Get(0).([]domain.City), args.Error(1)
+}
+
+type MockReceptionService struct {
+	service.ReceptionService
+	mock.Mock
+}
+
+func (m *MockReceptionService) CreateReception(ctx context.Context, pvzID string) (domain.Reception, error) {
+	args := m.Called(pvzID)
+	return args.Get(0).(domain.Reception), args.Error(1)
+}
+
+func (m *MockReceptionService) CloseLastReception(ctx context.Context, pvzID string) (domain.Reception, error) {
+	args := m.Called(pvzID)
+	return args.Get(0).(domain.Reception), args.Error(1)
+}
+
+type testCLI struct {
+	*CLI
+	auth       *MockAuthService
+	users      *MockUserService
+	pvzs       *MockPVZService
+	cities     *MockCityService
+	receptions *MockReceptionService
+	out        *bytes.Buffer
+}
+
+func newTestCLI() testCLI {
+	c := testCLI{
+		auth:       new(MockAuthService),
+		users:      new(MockUserService),
+		pvzs:       new(MockPVZService),
+		cities:     new(MockCityService),
+		receptions: new(MockReceptionService),
+		out:        new(bytes.Buffer),
+	}
+	c.CLI = New(c.auth, c.users, c.pvzs, c.cities, c.receptions, c.out)
+	return c
+}
+
+func TestCLI_UnknownCommand(t *testing.T) {
+	c := newTestCLI()
+
+	assert.ErrorIs(t, c.Run(context.Background(), nil), errUsage)
+	assert.ErrorIs(t, c.Run(context.Background(), []string{"user", "delete", "id"}), errUsage)
+	assert.ErrorIs(t, c.Run(context.Background(), []string{"user", "disable"}), errUsage)
+}
+
+func TestCLI_UserCreate(t *testing.T) {
+	c := newTestCLI()
+	user := domain.User{ID: "user1", Email: "mod@example.com", Role: domain.RoleModerator}
+	c.auth.On("CreateUser", "mod@example.com", "secret123", domain.RoleModerator).Return("user1", nil)
+	c.users.On("GetUser", "user1").Return(user, nil)
+
+	err := c.Run(context.Background(),
+		[]string{"user", "create", "-email", "mod@example.com", "-password", "secret123", "-role", "moderator"})
+	assert.NoError(t, err)
+	assert.Equal(t, "user1\tmod@example.com\tmoderator\tdisabled=false\n", c.out.String())
+	c.auth.AssertExpectations(t)
+	c.users.AssertExpectations(t)
+}
+
+func TestCLI_UserDisable_JSON(t *testing.T) {
+	c := newTestCLI()
+	disabled := true
+	user := domain.User{ID: "user1", Email: "e@example.com", Role: domain.RoleEmployee, Disabled: true}
+	c.users.On("UpdateUser", "", domain.RoleAdmin, "user1", (*string)(nil), &disabled).Return(user, nil)
+
+	err := c.Run(context.Background(), []string{"user", "disable", "user1", "-json"})
+	assert.NoError(t, err)
+
+	var got domain.User
+	assert.NoError(t, json.Unmarshal(c.out.Bytes(), &got))
+	assert.Equal(t, user, got)
+	c.users.AssertExpectations(t)
+}
+
+func TestCLI_UserSetRole_Error(t *testing.T) {
+	c := newTestCLI()
+	role := domain.RoleAdmin
+	c.users.On("UpdateUser", "", domain.RoleAdmin, "user1", &role, (*bool)(nil)).
+		Return(domain.User{}, domain.ErrUserNotFound)
+
+	err := c.Run(context.Background(), []string{"user", "set-role", "user1", "admin"})
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	assert.Empty(t, c.out.String())
+}
+
+func TestCLI_PVZList_JSON(t *testing.T) {
+	c := newTestCLI()
+	pvzs := []domain.PVZ{{ID: "pvz1", City: "Москва"}, {ID: "pvz2", City: "Казань"}}
+	c.pvzs.On("ListPVZs").Return(pvzs, nil)
+
+	err := c.Run(context.Background(), []string{"pvz", "list", "-json"})
+	assert.NoError(t, err)
+
+	var got []domain.PVZ
+	assert.NoError(t, json.Unmarshal(c.out.Bytes(), &got))
+	assert.Equal(t, pvzs, got)
+}
+
+func TestCLI_ReceptionForceClose(t *testing.T) {
+	c := newTestCLI()
+	c.receptions.On("CloseLastReception", "pvz1").
+		Return(domain.Reception{ID: "rec1", PvzId: "pvz1", Status: "close"}, nil)
+
+	err := c.Run(context.Background(), []string{"reception", "force-close", "pvz1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "rec1\tpvz1\tclose\n", c.out.String())
+}
+
+func TestCLI_Seed(t *testing.T) {
+	c := newTestCLI()
+	c.cities.On("ListCities", false).Return([]domain.City{{Name: "Москва"}, {Name: "Казань"}}, nil)
+	c.pvzs.On("CreatePVZ", "Москва").Return(domain.PVZ{ID: "pvz1"}, nil).Once()
+	c.pvzs.On("CreatePVZ", "Казань").Return(domain.PVZ{ID: "pvz2"}, nil).Once()
+	c.pvzs.On("CreatePVZ", "Москва").Return(domain.PVZ{ID: "pvz3"}, nil).Once()
+	c.receptions.On("CreateReception", mock.Anything).Return(domain.Reception{}, nil)
+	c.receptions.On("CloseLastReception", mock.Anything).Return(domain.Reception{}, nil)
+
+	err := c.Run(context.Background(), []string{"seed", "-pvz", "3", "-receptions", "2", "-json"})
+	assert.NoError(t, err)
+
+	var got seedResult
+	assert.NoError(t, json.Unmarshal(c.out.Bytes(), &got))
+	assert.Equal(t, []string{"pvz1", "pvz2", "pvz3"}, got.PVZIDs)
+	assert.Equal(t, 6, got.Receptions)
+	c.receptions.AssertNumberOfCalls(t, "CreateReception", 6)
+	c.receptions.AssertNumberOfCalls(t, "CloseLastReception", 6)
+}
+
+func TestCLI_Export(t *testing.T) {
+	c := newTestCLI()
+	c.pvzs.On("ListPVZsByCursor", "", service.MaxCursorPageLimit).Return(repository.PVZCursorResponse{
+		Items:      []repository.PVZResponse{{PVZ: domain.PVZ{ID: "pvz1"}}},
+		NextCursor: "next",
+	}, nil)
+	c.pvzs.On("ListPVZsByCursor", "next", service.MaxCursorPageLimit).Return(repository.PVZCursorResponse{
+		Items: []repository.PVZResponse{{PVZ: domain.PVZ{ID: "pvz2"}}},
+	}, nil)
+
+	err := c.Run(context.Background(), []string{"export"})
+	assert.NoError(t, err)
+
+	var got []repository.PVZResponse
+	assert.NoError(t, json.Unmarshal(c.out.Bytes(), &got))
+	assert.Len(t, got, 2)
+	assert.Equal(t, "pvz2", got[1].PVZ.ID)
+	c.pvzs.AssertExpectations(t)
+}
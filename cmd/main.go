@@ -1,56 +1,112 @@
 package main
 
 import (
-	"database/sql"
-	"fmt"
+	"context"
 	"github.com/joho/godotenv"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
-	"log"
-	"net/http"
+	"log/slog"
+	"os"
+	"os/signal"
 	"pvz-service/cmd/app"
 	"pvz-service/internal/config"
 	"pvz-service/internal/db"
-	grpcserver "pvz-service/internal/grpc"
+	"pvz-service/internal/events"
+	"pvz-service/internal/jwtkeys"
+	"pvz-service/internal/logging"
+	"pvz-service/internal/migrate"
+	"pvz-service/internal/passwordpolicy"
+	"pvz-service/migrations"
+	"syscall"
 )
 
-func startMetricsServer() {
-	http.Handle("/metrics", promhttp.Handler())
-	go func() {
-		log.Println("Starting metrics server on :9000")
-		if err := http.ListenAndServe(":9000", nil); err != nil {
-			log.Printf("Metrics server error: %v", err)
-		}
-	}()
-}
+// Events kept for WatchPVZEvents resume and per-subscriber buffer size.
+const (
+	eventHistorySize = 1000
+	eventBufferSize  = 100
+)
+
+func main() {
+	envErr := godotenv.Load()
+
+	cfg := config.LoadConfig()
+	if err := cfg.Validate(); err != nil {
+		fatal("Invalid configuration", err)
+	}
 
-func startGRPCServerAsync(db *sql.DB, port string) {
-	go func() {
-		if err := grpcserver.StartGRPCServer(db, port); err != nil {
-			log.Fatalf("Failed to start gRPC server: %v", err)
+	// Logs go to stderr so that CLI output on stdout stays machine-readable.
+	logger := logging.New(os.Stderr, cfg.LogLevel)
+	slog.SetDefault(logger)
+	if envErr != nil {
+		logger.Warn("Error loading .env file", "error", envErr)
+	}
+	for _, warning := range cfg.Warnings {
+		logger.Warn("Invalid configuration value, using default",
+			"key", warning.Key, "value", warning.Value, "default", warning.Default)
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, os.Args[2:]); err != nil {
+			fatal("Migration failed", err)
 		}
-	}()
-}
+		return
+	}
 
-func main() {
-	err := godotenv.Load()
+	keys, err := jwtkeys.Load(cfg.JWTKeysDir, cfg.JWTActiveKeyID, cfg.JWTSecret)
 	if err != nil {
-		log.Println("Error loading .env file")
+		fatal("Failed to load JWT keys", err)
 	}
 
-	cfg := config.LoadConfig()
+	passwordPolicy, err := passwordpolicy.Load(cfg.PasswordMinLength, cfg.PasswordMinClasses, cfg.PasswordDenylistFile)
+	if err != nil {
+		fatal("Failed to load password policy", err)
+	}
 
 	database, err := db.InitializeDB(cfg.DbDSN)
 	if err != nil {
-		log.Fatal("Failed to initialize DB:", err)
+		fatal("Failed to initialize DB", err)
+	}
+
+	if len(os.Args) > 1 {
+		command := app.MakeCLI(database, cfg, passwordPolicy, os.Stdout)
+		err := command.Run(context.Background(), os.Args[1:])
+		database.Close()
+		if err != nil {
+			fatal("Command failed", err)
+		}
+		return
 	}
-	defer database.Close()
 
-	startGRPCServerAsync(database, "3000")
+	if cfg.MigrateOnStart {
+		migrator, err := migrate.New(database, migrations.FS)
+		if err == nil {
+			err = migrator.Up(context.Background())
+		}
+		if err != nil {
+			fatal("Failed to apply migrations", err)
+		}
+	}
 
-	application := app.MakeApp(database, cfg)
+	broker := events.NewBroker(eventHistorySize, eventBufferSize)
 
-	startMetricsServer()
+	checker, grpcHealth, err := app.MakeHealth(database, cfg)
+	if err != nil {
+		fatal("Failed to set up health checks", err)
+	}
+
+	lifecycle := app.NewLifecycle(cfg,
+		app.MakeApp(database, cfg, broker, keys, passwordPolicy, checker, logger),
+		app.MakeGRPCServer(database, cfg, broker, keys, logger),
+		grpcHealth, checker, database)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := lifecycle.Run(ctx); err != nil {
+		fatal("Server failed", err)
+	}
+	logger.Info("Server stopped")
+}
 
-	log.Printf("Server listening on port %s", cfg.Port)
-	log.Fatal(application.Listen(fmt.Sprintf("0.0.0.0:%s", cfg.Port)))
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"pvz-service/internal/config"
+	"pvz-service/internal/db"
+	"pvz-service/internal/migrate"
+	"pvz-service/migrations"
+)
+
+const migrateUsage = "usage: migrate up|down|status|to N"
+
+func runMigrate(cfg config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	database, err := db.InitializeDB(cfg.DbDSN)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	migrator, err := migrate.New(database, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch {
+	case args[0] == "up" && len(args) == 1:
+		err = migrator.Up(ctx)
+	case args[0] == "down" && len(args) == 1:
+		err = migrator.Down(ctx)
+	case args[0] == "to" && len(args) == 2:
+		version, parseErr := strconv.ParseInt(args[1], 10, 64)
+		if parseErr != nil || version < 0 {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		err = migrator.To(ctx, version)
+	case args[0] == "status" && len(args) == 1:
+	default:
+		return errors.New(migrateUsage)
+	}
+	if err != nil {
+		return err
+	}
+	return printMigrationStatus(ctx, migrator)
+}
+
+func printMigrationStatus(ctx context.Context, migrator *migrate.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		applied := "pending"
+		if status.AppliedAt != nil {
+			applied = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, applied)
+	}
+	return nil
+}
@@ -1,29 +1,188 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	EnvDev  = "dev"
+	EnvTest = "test"
+	EnvProd = "prod"
+
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+
+	LoginAttemptStoreMemory   = "memory"
+	LoginAttemptStorePostgres = "postgres"
+
+	NotifierLog  = "log"
+	NotifierFile = "file"
+
+	defaultJWTSecret  = "secret"
+	defaultDBPassword = "postgres"
+
+	minProdJWTSecretLength = 32
 )
 
 type Config struct {
-	DbDSN     string
-	JWTSecret string
-	Port      string
+	Env             string
+	DbDSN           string
+	DBTimeout       time.Duration
+	MigrateOnStart  bool
+	JWTSecret       string
+	JWTKeysDir      string
+	JWTActiveKeyID  string
+	RefreshTokenTTL time.Duration
+	AllowSignup     bool
+	Port            string
+	GRPCPort        string
+	MetricsPort     string
+	ShutdownTimeout time.Duration
+	ShutdownDelay   time.Duration
+
+	HealthCheckTimeout time.Duration
+
+	LogLevel         string
+	CORSAllowOrigins string
+	DummyLogin       bool
+
+	LoginAttemptStore     string
+	LoginMaxAttempts      int
+	LoginMaxAttemptsPerIP int
+	LoginAttemptWindow    time.Duration
+	LoginLockout          time.Duration
+
+	PasswordMinLength    int
+	PasswordMinClasses   int
+	PasswordDenylistFile string
+	PasswordResetTTL     time.Duration
+	Notifier             string
+	NotifierFile         string
+
+	// Warnings lists settings that could not be parsed and fell back to their
+	// defaults. LoadConfig runs before the logger exists, so main logs them.
+	Warnings []Warning
+
+	dbPassword string
+}
+
+// Warning is an environment variable whose value was ignored in favour of Default.
+type Warning struct {
+	Key     string
+	Value   string
+	Default string
 }
 
 func LoadConfig() Config {
+	env := getEnv("APP_ENV", EnvDev)
+	// Only dev gets a built-in JWT secret and prod gets no developer conveniences
+	// at all, Validate then refuses to start until they are configured explicitly.
+	jwtSecret, dbPassword, logLevel, corsOrigins := defaultJWTSecret, defaultDBPassword, LogLevelDebug, "*"
+	switch env {
+	case EnvTest:
+		jwtSecret, logLevel = "", LogLevelWarn
+	case EnvProd:
+		jwtSecret, dbPassword, logLevel, corsOrigins = "", "", LogLevelInfo, ""
+	}
+
 	dbHost := getEnv("DATABASE_HOST", "db")
 	dbPort := getEnv("DATABASE_PORT", "5432")
 	dbUser := getEnv("DATABASE_USER", "postgres")
-	dbPass := getEnv("DATABASE_PASSWORD", "postgres")
+	dbPass := getEnv("DATABASE_PASSWORD", dbPassword)
 	dbName := getEnv("DATABASE_NAME", "pvz")
 
-	return Config{
+	parser := &envParser{}
+	cfg := Config{
+		Env: env,
 		DbDSN: fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 			dbHost, dbPort, dbUser, dbPass, dbName),
-		JWTSecret: getEnv("JWT_SECRET", "secret"),
-		Port:      getEnv("SERVER_PORT", "8080"),
+		DBTimeout:       parser.duration("DATABASE_TIMEOUT", 5*time.Second),
+		MigrateOnStart:  parser.bool("MIGRATE_ON_START", false),
+		JWTSecret:       getEnv("JWT_SECRET", jwtSecret),
+		JWTKeysDir:      os.Getenv("JWT_KEYS_DIR"),
+		JWTActiveKeyID:  os.Getenv("JWT_ACTIVE_KEY_ID"),
+		RefreshTokenTTL: parser.duration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		AllowSignup:     parser.bool("ALLOW_SIGNUP", true),
+		Port:            getEnv("SERVER_PORT", "8080"),
+		GRPCPort:        getEnv("GRPC_PORT", "3000"),
+		MetricsPort:     getEnv("METRICS_PORT", "9000"),
+		ShutdownTimeout: parser.duration("SHUTDOWN_TIMEOUT", 15*time.Second),
+		ShutdownDelay:   parser.duration("SHUTDOWN_DELAY", 0),
+
+		HealthCheckTimeout: parser.duration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+
+		LogLevel:         getEnv("LOG_LEVEL", logLevel),
+		CORSAllowOrigins: getEnv("CORS_ALLOW_ORIGINS", corsOrigins),
+		DummyLogin:       parser.bool("DUMMY_LOGIN", env == EnvDev),
+
+		LoginAttemptStore:     getEnv("LOGIN_ATTEMPT_STORE", LoginAttemptStorePostgres),
+		LoginMaxAttempts:      parser.int("LOGIN_MAX_ATTEMPTS", 5),
+		LoginMaxAttemptsPerIP: parser.int("LOGIN_MAX_ATTEMPTS_PER_IP", 20),
+		LoginAttemptWindow:    parser.duration("LOGIN_ATTEMPT_WINDOW", 15*time.Minute),
+		LoginLockout:          parser.duration("LOGIN_LOCKOUT", 15*time.Minute),
+
+		PasswordMinLength:    parser.int("PASSWORD_MIN_LENGTH", 8),
+		PasswordMinClasses:   parser.int("PASSWORD_MIN_CLASSES", 2),
+		PasswordDenylistFile: os.Getenv("PASSWORD_DENYLIST_FILE"),
+		PasswordResetTTL:     parser.duration("PASSWORD_RESET_TTL", time.Hour),
+		Notifier:             getEnv("NOTIFIER", NotifierLog),
+		NotifierFile:         getEnv("NOTIFIER_FILE", "notifications.jsonl"),
+
+		dbPassword: dbPass,
 	}
+	cfg.Warnings = parser.warnings
+	return cfg
+}
+
+// Validate rejects unknown settings, the default JWT secret outside dev and, in
+// prod, the remaining settings that are only acceptable for local development.
+func (c Config) Validate() error {
+	if c.Env != EnvDev && c.Env != EnvTest && c.Env != EnvProd {
+		return fmt.Errorf("APP_ENV must be %q, %q or %q", EnvDev, EnvTest, EnvProd)
+	}
+	switch c.LogLevel {
+	case LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError:
+	default:
+		return fmt.Errorf("LOG_LEVEL must be one of %q, %q, %q, %q", LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError)
+	}
+	if c.LoginAttemptStore != LoginAttemptStoreMemory && c.LoginAttemptStore != LoginAttemptStorePostgres {
+		return fmt.Errorf("LOGIN_ATTEMPT_STORE must be %q or %q", LoginAttemptStoreMemory, LoginAttemptStorePostgres)
+	}
+	if c.Notifier != NotifierLog && c.Notifier != NotifierFile {
+		return fmt.Errorf("NOTIFIER must be %q or %q", NotifierLog, NotifierFile)
+	}
+	if c.Env != EnvDev && c.JWTKeysDir == "" && (c.JWTSecret == "" || c.JWTSecret == defaultJWTSecret) {
+		return errors.New("JWT_SECRET must be set to a non-default value outside dev mode, set it or JWT_KEYS_DIR")
+	}
+	if c.Env == EnvProd {
+		return c.validateProd()
+	}
+	return nil
+}
+
+func (c Config) validateProd() error {
+	if c.JWTKeysDir == "" && len(c.JWTSecret) < minProdJWTSecretLength {
+		return fmt.Errorf("JWT_SECRET must be at least %d bytes in prod", minProdJWTSecretLength)
+	}
+	if c.dbPassword == "" || c.dbPassword == defaultDBPassword {
+		return errors.New("DATABASE_PASSWORD must be set to a non-default value in prod")
+	}
+	if c.DummyLogin {
+		return errors.New("DUMMY_LOGIN must not be enabled in prod")
+	}
+	for _, origin := range strings.Split(c.CORSAllowOrigins, ",") {
+		if strings.TrimSpace(origin) == "*" {
+			return errors.New("CORS_ALLOW_ORIGINS must list explicit origins in prod")
+		}
+	}
+	return nil
 }
 
 func getEnv(key, defaultValue string) string {
@@ -33,3 +192,54 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+// envParser reads typed settings and records the ones it had to replace with defaults.
+type envParser struct {
+	warnings []Warning
+}
+
+func (p *envParser) warn(key, value string, defaultValue any) {
+	p.warnings = append(p.warnings, Warning{Key: key, Value: value, Default: fmt.Sprint(defaultValue)})
+}
+
+func (p *envParser) duration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		p.warn(key, value, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
+func (p *envParser) int(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		p.warn(key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+func (p *envParser) bool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		p.warn(key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
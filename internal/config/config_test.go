@@ -0,0 +1,139 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func validConfig(env string) Config {
+	return Config{
+		Env:               env,
+		JWTSecret:         defaultJWTSecret,
+		LogLevel:          LogLevelInfo,
+		CORSAllowOrigins:  "*",
+		DummyLogin:        true,
+		LoginAttemptStore: LoginAttemptStorePostgres,
+		Notifier:          NotifierLog,
+		dbPassword:        defaultDBPassword,
+	}
+}
+
+func validProdConfig() Config {
+	cfg := validConfig(EnvProd)
+	cfg.JWTSecret = strings.Repeat("s", minProdJWTSecretLength)
+	cfg.CORSAllowOrigins = "https://example.com"
+	cfg.DummyLogin = false
+	cfg.dbPassword = "db-password"
+	return cfg
+}
+
+func TestLoadConfig_Defaults(t *testing.T) {
+	for _, key := range []string{"JWT_SECRET", "DATABASE_PASSWORD", "LOG_LEVEL", "CORS_ALLOW_ORIGINS", "DUMMY_LOGIN"} {
+		t.Setenv(key, "")
+	}
+
+	t.Setenv("APP_ENV", EnvDev)
+	dev := LoadConfig()
+	assert.Equal(t, defaultJWTSecret, dev.JWTSecret)
+	assert.Equal(t, LogLevelDebug, dev.LogLevel)
+	assert.Equal(t, "*", dev.CORSAllowOrigins)
+	assert.True(t, dev.DummyLogin)
+	assert.NoError(t, dev.Validate())
+
+	t.Setenv("APP_ENV", EnvTest)
+	test := LoadConfig()
+	assert.Empty(t, test.JWTSecret)
+	assert.Equal(t, LogLevelWarn, test.LogLevel)
+	assert.False(t, test.DummyLogin)
+	assert.Error(t, test.Validate())
+
+	t.Setenv("DUMMY_LOGIN", "true")
+	assert.True(t, LoadConfig().DummyLogin)
+	t.Setenv("DUMMY_LOGIN", "")
+
+	t.Setenv("APP_ENV", EnvProd)
+	prod := LoadConfig()
+	assert.Empty(t, prod.JWTSecret)
+	assert.Empty(t, prod.CORSAllowOrigins)
+	assert.False(t, prod.DummyLogin)
+	assert.Error(t, prod.Validate())
+}
+
+func TestLoadConfig_InvalidValuesFallBackWithWarnings(t *testing.T) {
+	t.Setenv("LOGIN_MAX_ATTEMPTS", "many")
+	t.Setenv("DATABASE_TIMEOUT", "5")
+	t.Setenv("ALLOW_SIGNUP", "maybe")
+
+	cfg := LoadConfig()
+
+	assert.Equal(t, 5, cfg.LoginMaxAttempts)
+	assert.Equal(t, 5*time.Second, cfg.DBTimeout)
+	assert.True(t, cfg.AllowSignup)
+	assert.ElementsMatch(t, []Warning{
+		{Key: "LOGIN_MAX_ATTEMPTS", Value: "many", Default: "5"},
+		{Key: "DATABASE_TIMEOUT", Value: "5", Default: "5s"},
+		{Key: "ALLOW_SIGNUP", Value: "maybe", Default: "true"},
+	}, cfg.Warnings)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr string
+	}{
+		{name: "valid prod", modify: func(*Config) {}},
+		{name: "keys dir replaces secret", modify: func(c *Config) { c.JWTSecret, c.JWTKeysDir = "", "/keys" }},
+		{name: "unknown env", modify: func(c *Config) { c.Env = "staging" }, wantErr: "APP_ENV"},
+		{name: "unknown log level", modify: func(c *Config) { c.LogLevel = "trace" }, wantErr: "LOG_LEVEL"},
+		{name: "default secret", modify: func(c *Config) { c.JWTSecret = defaultJWTSecret }, wantErr: "JWT_SECRET"},
+		{name: "short secret", modify: func(c *Config) { c.JWTSecret = "short" }, wantErr: "JWT_SECRET"},
+		{
+			name:    "default db password",
+			modify:  func(c *Config) { c.dbPassword = defaultDBPassword },
+			wantErr: "DATABASE_PASSWORD",
+		},
+		{name: "dummy login", modify: func(c *Config) { c.DummyLogin = true }, wantErr: "DUMMY_LOGIN"},
+		{
+			name:    "wildcard cors",
+			modify:  func(c *Config) { c.CORSAllowOrigins = "https://example.com, *" },
+			wantErr: "CORS_ALLOW_ORIGINS",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validProdConfig()
+			tt.modify(&cfg)
+
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_DevAndTestAllowDefaults(t *testing.T) {
+	assert.NoError(t, validConfig(EnvDev).Validate())
+
+	test := validConfig(EnvTest)
+	test.JWTSecret = "test-secret"
+	assert.NoError(t, test.Validate())
+}
+
+func TestValidate_DefaultSecretOutsideDev(t *testing.T) {
+	err := validConfig(EnvTest).Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "JWT_SECRET")
+	}
+
+	test := validConfig(EnvTest)
+	test.JWTSecret, test.JWTKeysDir = defaultJWTSecret, "/keys"
+	assert.NoError(t, test.Validate())
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// APIKey authenticates a machine client with a fixed role. When PVZIDs is not
+// empty the key may act only on those PVZs.
+type APIKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Role       string     `json:"role"`
+	PVZIDs     []string   `json:"pvzIds"`
+	CreatedBy  *string    `json:"createdBy"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+}
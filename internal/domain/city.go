@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+type City struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	IsActive  bool      `json:"isActive"`
+	CreatedAt time.Time `json:"createdAt"`
+}
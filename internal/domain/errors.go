@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInternal = errors.New("internal error")
+
+	ErrCityNotFound      = errors.New("city not found")
+	ErrCityAlreadyExists = errors.New("city already exists")
+
+	ErrProductTypeNotFound      = errors.New("product type not found")
+	ErrProductTypeAlreadyExists = errors.New("product type already exists")
+	ErrInvalidProductAttributes = errors.New("invalid product attributes")
+
+	ErrPVZNotFound = errors.New("pvz not found")
+
+	ErrUserNotFound       = errors.New("user not found")
+	ErrEmailAlreadyExists = errors.New("email already exists")
+	ErrInvalidEmail       = errors.New("invalid email")
+	ErrUserDisabled       = errors.New("user is disabled")
+	ErrForbiddenUserEdit  = errors.New("not allowed to modify this user")
+	ErrSelfModification   = errors.New("cannot modify own account")
+	ErrUserNotEmployee    = errors.New("only employees can be assigned to a PVZ")
+	ErrAssignmentNotFound = errors.New("user is not assigned to this PVZ")
+
+	ErrWeakPassword           = errors.New("password does not meet the policy")
+	ErrInvalidCurrentPassword = errors.New("current password is incorrect")
+	ErrInvalidResetToken      = errors.New("invalid or expired reset token")
+
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrInvalidAPIKey  = errors.New("invalid api key")
+
+	ErrAccountLocked   = errors.New("account is temporarily locked")
+	ErrTooManyAttempts = errors.New("too many login attempts")
+
+	ErrOpenReceptionExists = errors.New("open reception already exists for this PVZ")
+	ErrReceptionNotFound   = errors.New("reception not found")
+	ErrNoOpenReception     = errors.New("no open reception for this PVZ")
+	ErrNoProductsToDelete  = errors.New("no products to delete in this reception")
+)
+
+// ErrorKind groups the errors of the reception workflow so the HTTP and gRPC
+// transports classify them the same way.
+type ErrorKind int
+
+const (
+	KindInvalid ErrorKind = iota
+	KindNotFound
+	KindAlreadyExists
+	KindFailedPrecondition
+	KindInternal
+)
+
+// KindOf classifies err; anything unrecognised is the caller's fault.
+func KindOf(err error) ErrorKind {
+	switch {
+	case errors.Is(err, ErrPVZNotFound), errors.Is(err, ErrReceptionNotFound), errors.Is(err, ErrCityNotFound):
+		return KindNotFound
+	case errors.Is(err, ErrOpenReceptionExists):
+		return KindAlreadyExists
+	case errors.Is(err, ErrNoOpenReception), errors.Is(err, ErrNoProductsToDelete):
+		return KindFailedPrecondition
+	case errors.Is(err, ErrInternal):
+		return KindInternal
+	}
+	return KindInvalid
+}
+
+// InternalError is a failure that is not the client's fault. Msg is all the
+// client gets to see; the cause is logged where the error is created. It
+// matches ErrInternal with errors.Is.
+type InternalError struct {
+	Msg string
+}
+
+func (e *InternalError) Error() string {
+	return e.Msg
+}
+
+func (e *InternalError) Is(target error) bool {
+	return target == ErrInternal
+}
+
+// LockoutError is returned while logins are blocked. It wraps ErrAccountLocked or
+// ErrTooManyAttempts.
+type LockoutError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *LockoutError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LockoutError) Unwrap() error {
+	return e.Err
+}
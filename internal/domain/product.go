@@ -3,8 +3,9 @@ package domain
 import "time"
 
 type Product struct {
-	ID          string    `json:"id"`
-	DateTime    time.Time `json:"dateTime"`
-	Type        string    `json:"type"`
-	ReceptionId string    `json:"receptionId"`
+	ID          string            `json:"id"`
+	DateTime    time.Time         `json:"dateTime"`
+	Type        string            `json:"type"`
+	ReceptionId string            `json:"receptionId"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
 }
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+type ProductType struct {
+	Code               string    `json:"code"`
+	DisplayName        string    `json:"displayName"`
+	RequiredAttributes []string  `json:"requiredAttributes"`
+	IsActive           bool      `json:"isActive"`
+	CreatedAt          time.Time `json:"createdAt"`
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"net/mail"
+	"strings"
+	"time"
+)
+
+const (
+	RoleEmployee  = "employee"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+type User struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	Disabled  bool      `json:"disabled"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func IsValidRole(role string) bool {
+	return role == RoleEmployee || role == RoleModerator || role == RoleAdmin
+}
+
+// NormalizeEmail trims and lowercases the email, which is the form stored in the
+// users table. Anything but a bare address is rejected with ErrInvalidEmail.
+func NormalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", ErrInvalidEmail
+	}
+	return email, nil
+}
@@ -0,0 +1,130 @@
+package events
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+type EventType string
+
+const (
+	ReceptionOpened EventType = "reception_opened"
+	ReceptionClosed EventType = "reception_closed"
+	ProductAdded    EventType = "product_added"
+	ProductDeleted  EventType = "product_deleted"
+)
+
+type Event struct {
+	ID          uint64
+	Type        EventType
+	PVZID       string
+	ReceptionID string
+	ProductID   string
+	OccurredAt  time.Time
+}
+
+type Publisher interface {
+	Publish(event Event)
+}
+
+// ErrHistoryExpired means the requested resume point is no longer in the broker's
+// history, so the subscriber has to reload state instead of replaying events.
+var ErrHistoryExpired = errors.New("event history no longer available")
+
+// Broker fans events out to in-process subscribers and keeps the most recent
+// events so that a reconnecting subscriber can resume after the last id it saw.
+type Broker struct {
+	mu          sync.Mutex
+	lastID      uint64
+	history     []Event
+	historySize int
+	bufferSize  int
+	subscribers map[*Subscription]struct{}
+}
+
+func NewBroker(historySize, bufferSize int) *Broker {
+	return &Broker{
+		historySize: historySize,
+		bufferSize:  bufferSize,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscription delivers events on C. C is closed when the subscription is closed
+// or when the subscriber falls more than the buffer size behind.
+type Subscription struct {
+	C      <-chan Event
+	ch     chan Event
+	broker *Broker
+}
+
+func (b *Broker) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event.ID = b.lastID
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	if b.historySize > 0 {
+		if len(b.history) == b.historySize {
+			b.history = append(b.history[:0], b.history[1:]...)
+		}
+		b.history = append(b.history, event)
+	}
+
+	for sub := range b.subscribers {
+		select {
+		case sub.ch <- event:
+		default:
+			b.unsubscribe(sub)
+		}
+	}
+}
+
+// Subscribe registers a subscriber. A zero afterID subscribes to new events only;
+// otherwise the events published after afterID are returned as a backlog, and the
+// subscription continues from there without gaps.
+func (b *Broker) Subscribe(afterID uint64) ([]Event, *Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var backlog []Event
+	if afterID > 0 {
+		if afterID > b.lastID {
+			return nil, nil, ErrHistoryExpired
+		}
+		if afterID < b.lastID {
+			if len(b.history) == 0 || b.history[0].ID > afterID+1 {
+				return nil, nil, ErrHistoryExpired
+			}
+			for _, event := range b.history {
+				if event.ID > afterID {
+					backlog = append(backlog, event)
+				}
+			}
+		}
+	}
+
+	ch := make(chan Event, b.bufferSize)
+	sub := &Subscription{C: ch, ch: ch, broker: b}
+	b.subscribers[sub] = struct{}{}
+	return backlog, sub, nil
+}
+
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.unsubscribe(s)
+}
+
+func (b *Broker) unsubscribe(sub *Subscription) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	close(sub.ch)
+}
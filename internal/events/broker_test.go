@@ -0,0 +1,81 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBroker_PublishSubscribe(t *testing.T) {
+	broker := NewBroker(10, 10)
+
+	backlog, sub, err := broker.Subscribe(0)
+	assert.NoError(t, err)
+	assert.Empty(t, backlog)
+	defer sub.Close()
+
+	broker.Publish(Event{Type: ReceptionOpened, PVZID: "pvz1", ReceptionID: "rec1"})
+	broker.Publish(Event{Type: ProductAdded, PVZID: "pvz1", ReceptionID: "rec1", ProductID: "prod1"})
+
+	first := <-sub.C
+	second := <-sub.C
+	assert.Equal(t, uint64(1), first.ID)
+	assert.Equal(t, ReceptionOpened, first.Type)
+	assert.False(t, first.OccurredAt.IsZero())
+	assert.Equal(t, uint64(2), second.ID)
+	assert.Equal(t, "prod1", second.ProductID)
+}
+
+func TestBroker_Resume(t *testing.T) {
+	broker := NewBroker(3, 10)
+	for i := 0; i < 5; i++ {
+		broker.Publish(Event{Type: ProductAdded})
+	}
+
+	t.Run("within history", func(t *testing.T) {
+		backlog, sub, err := broker.Subscribe(3)
+		assert.NoError(t, err)
+		defer sub.Close()
+
+		assert.Len(t, backlog, 2)
+		assert.Equal(t, uint64(4), backlog[0].ID)
+		assert.Equal(t, uint64(5), backlog[1].ID)
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		backlog, sub, err := broker.Subscribe(5)
+		assert.NoError(t, err)
+		defer sub.Close()
+
+		assert.Empty(t, backlog)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		_, _, err := broker.Subscribe(1)
+		assert.ErrorIs(t, err, ErrHistoryExpired)
+	})
+
+	t.Run("unknown id", func(t *testing.T) {
+		_, _, err := broker.Subscribe(42)
+		assert.ErrorIs(t, err, ErrHistoryExpired)
+	})
+}
+
+func TestBroker_SlowSubscriberIsDropped(t *testing.T) {
+	broker := NewBroker(0, 1)
+
+	_, sub, err := broker.Subscribe(0)
+	assert.NoError(t, err)
+
+	broker.Publish(Event{Type: ProductAdded})
+	broker.Publish(Event{Type: ProductDeleted})
+
+	event, ok := <-sub.C
+	assert.True(t, ok)
+	assert.Equal(t, ProductAdded, event.Type)
+
+	_, ok = <-sub.C
+	assert.False(t, ok)
+
+	sub.Close()
+}
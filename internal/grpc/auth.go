@@ -0,0 +1,210 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/jwtkeys"
+	"pvz-service/internal/logging"
+	pb "pvz-service/internal/proto"
+)
+
+// methodRoles mirrors the CheckRole configuration of the matching HTTP routes.
+// Methods missing from the map are denied.
+var methodRoles = map[string][]string{
+	pb.PVZService_GetPVZList_FullMethodName:         {"employee", "moderator"},
+	pb.PVZService_CreatePVZ_FullMethodName:          {"moderator"},
+	pb.PVZService_CreateReception_FullMethodName:    {"employee"},
+	pb.PVZService_CloseLastReception_FullMethodName: {"employee"},
+	pb.PVZService_GetReception_FullMethodName:       {"employee", "moderator"},
+	pb.PVZService_ListReceptions_FullMethodName:     {"employee", "moderator"},
+	pb.PVZService_AddProduct_FullMethodName:         {"employee"},
+	pb.PVZService_DeleteLastProduct_FullMethodName:  {"employee"},
+	pb.PVZService_WatchPVZEvents_FullMethodName:     {"employee", "moderator"},
+}
+
+// publicMethods skip authentication: health probes carry no token.
+var publicMethods = map[string]bool{
+	healthpb.Health_Check_FullMethodName: true,
+	healthpb.Health_Watch_FullMethodName: true,
+}
+
+// pvzScopedMethods are the methods that act on a single PVZ and require the caller
+// to be assigned to it, like the HTTP routes behind RequirePVZAccess. GetReception
+// is checked by the method itself once the reception's PVZ is known, and
+// WatchPVZEvents checks its pvz_ids or limits the stream to assigned PVZs.
+var pvzScopedMethods = map[string]bool{
+	pb.PVZService_CreateReception_FullMethodName:    true,
+	pb.PVZService_CloseLastReception_FullMethodName: true,
+	pb.PVZService_AddProduct_FullMethodName:         true,
+	pb.PVZService_DeleteLastProduct_FullMethodName:  true,
+	pb.PVZService_ListReceptions_FullMethodName:     true,
+}
+
+// TokenRevocationChecker also reports disabled users; their tokens are treated
+// as revoked.
+type TokenRevocationChecker interface {
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	IsUserDisabled(ctx context.Context, userID string) (bool, error)
+}
+
+type claimsKey struct{}
+
+// ClaimsFromContext returns the token claims stored by the auth interceptors.
+func ClaimsFromContext(ctx context.Context) (jwt.MapClaims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(jwt.MapClaims)
+	return claims, ok
+}
+
+func UnaryAuthInterceptor(keys *jwtkeys.KeySet, revocations TokenRevocationChecker) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if publicMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		ctx, err := authorize(ctx, keys, revocations, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func StreamAuthInterceptor(keys *jwtkeys.KeySet, revocations TokenRevocationChecker) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if publicMethods[info.FullMethod] {
+			return handler(srv, ss)
+		}
+		ctx, err := authorize(ss.Context(), keys, revocations, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+type PVZAccessChecker interface {
+	IsAssigned(ctx context.Context, userID, pvzID string) (bool, error)
+}
+
+// PVZAccessInterceptor must run after UnaryAuthInterceptor. Malformed PVZ ids are
+// left for the method to reject.
+func PVZAccessInterceptor(checker PVZAccessChecker) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		scoped, ok := req.(interface{ GetPvzId() string })
+		if !pvzScopedMethods[info.FullMethod] || !ok {
+			return handler(ctx, req)
+		}
+		if _, err := uuid.Parse(scoped.GetPvzId()); err != nil {
+			return handler(ctx, req)
+		}
+
+		if err := checkPVZAccess(ctx, checker, scoped.GetPvzId()); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// checkPVZAccess limits employees to their assigned PVZs; moderators are not
+// assigned and may access every PVZ.
+func checkPVZAccess(ctx context.Context, checker PVZAccessChecker, pvzID string) error {
+	claims, _ := ClaimsFromContext(ctx)
+	if role, _ := claims["role"].(string); role != domain.RoleEmployee {
+		return nil
+	}
+
+	userID, _ := claims["userId"].(string)
+	if _, err := uuid.Parse(userID); err != nil {
+		return status.Error(codes.PermissionDenied, "pvz is not assigned to the user")
+	}
+
+	assigned, err := checker.IsAssigned(ctx, userID, pvzID)
+	if err != nil {
+		return status.Error(codes.Internal, "failed to check pvz access")
+	}
+	if !assigned {
+		return status.Error(codes.PermissionDenied, "pvz is not assigned to the user")
+	}
+	return nil
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+func authorize(
+	ctx context.Context, keys *jwtkeys.KeySet, revocations TokenRevocationChecker, fullMethod string,
+) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 || values[0] == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing authorization metadata")
+	}
+
+	tokenString := strings.Replace(values[0], "Bearer ", "", 1)
+
+	claims := jwt.MapClaims{}
+	_, err := keys.Parse(tokenString, claims)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, status.Error(codes.Unauthenticated, "invalid token expiration")
+		}
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	if jti, ok := claims["jti"].(string); ok && revocations != nil {
+		revoked, err := revocations.IsTokenRevoked(ctx, jti)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to check token")
+		}
+		if revoked {
+			return nil, status.Error(codes.Unauthenticated, "token revoked")
+		}
+	}
+
+	if userID, ok := claims["userId"].(string); ok && revocations != nil {
+		if _, err := uuid.Parse(userID); err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		disabled, err := revocations.IsUserDisabled(ctx, userID)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to check token")
+		}
+		if disabled {
+			return nil, status.Error(codes.Unauthenticated, "user disabled")
+		}
+	}
+
+	role, ok := claims["role"].(string)
+	if !ok {
+		return nil, status.Error(codes.PermissionDenied, "invalid role in token")
+	}
+
+	for _, allowedRole := range methodRoles[fullMethod] {
+		if role == allowedRole {
+			logging.AddFields(ctx, "userId", claims["userId"], "role", role)
+			return context.WithValue(ctx, claimsKey{}, claims), nil
+		}
+	}
+
+	return nil, status.Error(codes.PermissionDenied, "insufficient role")
+}
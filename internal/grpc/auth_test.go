@@ -0,0 +1,235 @@
+package grpcserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"pvz-service/internal/jwtkeys"
+	pb "pvz-service/internal/proto"
+)
+
+const testSecret = "test-secret"
+
+type revokedTokens map[string]bool
+
+func (r revokedTokens) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	return r[jti], nil
+}
+
+func (r revokedTokens) IsUserDisabled(ctx context.Context, userID string) (bool, error) {
+	return r[userID], nil
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	assert.NoError(t, err)
+	return token
+}
+
+func withToken(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func TestUnaryAuthInterceptor(t *testing.T) {
+	const disabledUser = "00000000-0000-0000-0000-000000000001"
+	interceptor := UnaryAuthInterceptor(
+		jwtkeys.NewHMACKeySet(testSecret), revokedTokens{"revoked-jti": true, disabledUser: true})
+	info := &grpc.UnaryServerInfo{FullMethod: pb.PVZService_CreatePVZ_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		claims, ok := ClaimsFromContext(ctx)
+		assert.True(t, ok)
+		return claims["role"], nil
+	}
+	validClaims := func(role string) jwt.MapClaims {
+		return jwt.MapClaims{"role": role, "exp": time.Now().Add(time.Hour).Unix()}
+	}
+
+	t.Run("allowed role", func(t *testing.T) {
+		ctx := withToken(signToken(t, jwt.SigningMethodHS256, []byte(testSecret), validClaims("moderator")))
+
+		resp, err := interceptor(ctx, nil, info, handler)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "moderator", resp)
+	})
+
+	t.Run("missing metadata", func(t *testing.T) {
+		_, err := interceptor(context.Background(), nil, info, handler)
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("health check without token", func(t *testing.T) {
+		healthInfo := &grpc.UnaryServerInfo{FullMethod: healthpb.Health_Check_FullMethodName}
+		resp, err := interceptor(context.Background(), nil, healthInfo,
+			func(ctx context.Context, req interface{}) (interface{}, error) { return "serving", nil })
+
+		assert.NoError(t, err)
+		assert.Equal(t, "serving", resp)
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		ctx := withToken(signToken(t, jwt.SigningMethodHS256, []byte("other"), validClaims("moderator")))
+
+		_, err := interceptor(ctx, nil, info, handler)
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("wrong algorithm", func(t *testing.T) {
+		ctx := withToken(signToken(t, jwt.SigningMethodHS384, []byte(testSecret), validClaims("moderator")))
+
+		_, err := interceptor(ctx, nil, info, handler)
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		ctx := withToken(signToken(t, jwt.SigningMethodHS256, []byte(testSecret), jwt.MapClaims{
+			"role": "moderator", "exp": time.Now().Add(-time.Hour).Unix(),
+		}))
+
+		_, err := interceptor(ctx, nil, info, handler)
+
+		assert.EqualError(t, err, status.Error(codes.Unauthenticated, "invalid token expiration").Error())
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		claims := validClaims("moderator")
+		claims["jti"] = "revoked-jti"
+		ctx := withToken(signToken(t, jwt.SigningMethodHS256, []byte(testSecret), claims))
+
+		_, err := interceptor(ctx, nil, info, handler)
+
+		assert.EqualError(t, err, status.Error(codes.Unauthenticated, "token revoked").Error())
+	})
+
+	t.Run("disabled user", func(t *testing.T) {
+		claims := validClaims("moderator")
+		claims["userId"] = disabledUser
+		ctx := withToken(signToken(t, jwt.SigningMethodHS256, []byte(testSecret), claims))
+
+		_, err := interceptor(ctx, nil, info, handler)
+
+		assert.EqualError(t, err, status.Error(codes.Unauthenticated, "user disabled").Error())
+	})
+
+	t.Run("insufficient role", func(t *testing.T) {
+		ctx := withToken(signToken(t, jwt.SigningMethodHS256, []byte(testSecret), validClaims("employee")))
+
+		_, err := interceptor(ctx, nil, info, handler)
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("unknown method", func(t *testing.T) {
+		ctx := withToken(signToken(t, jwt.SigningMethodHS256, []byte(testSecret), validClaims("moderator")))
+
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/pvz.v1.PVZService/Unknown"}, handler)
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+type assignedPVZs map[string]bool
+
+func (a assignedPVZs) IsAssigned(ctx context.Context, userID, pvzID string) (bool, error) {
+	return a[userID+"/"+pvzID], nil
+}
+
+func TestPVZAccessInterceptor(t *testing.T) {
+	const (
+		userID      = "44444444-4444-4444-4444-444444444444"
+		assigned    = "11111111-1111-1111-1111-111111111111"
+		notAssigned = "22222222-2222-2222-2222-222222222222"
+	)
+	interceptor := PVZAccessInterceptor(assignedPVZs{userID + "/" + assigned: true})
+	scoped := &grpc.UnaryServerInfo{FullMethod: pb.PVZService_AddProduct_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	ctx := context.WithValue(context.Background(), claimsKey{}, jwt.MapClaims{"userId": userID, "role": "employee"})
+
+	t.Run("assigned", func(t *testing.T) {
+		resp, err := interceptor(ctx, &pb.AddProductRequest{PvzId: assigned}, scoped, handler)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", resp)
+	})
+
+	t.Run("not assigned", func(t *testing.T) {
+		_, err := interceptor(ctx, &pb.AddProductRequest{PvzId: notAssigned}, scoped, handler)
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("not scoped method", func(t *testing.T) {
+		info := &grpc.UnaryServerInfo{FullMethod: pb.PVZService_GetPVZList_FullMethodName}
+
+		_, err := interceptor(ctx, &pb.AddProductRequest{PvzId: notAssigned}, info, handler)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("reading receptions of another pvz", func(t *testing.T) {
+		info := &grpc.UnaryServerInfo{FullMethod: pb.PVZService_ListReceptions_FullMethodName}
+
+		_, err := interceptor(ctx, &pb.ListReceptionsRequest{PvzId: notAssigned}, info, handler)
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("moderator is not limited to assignments", func(t *testing.T) {
+		info := &grpc.UnaryServerInfo{FullMethod: pb.PVZService_ListReceptions_FullMethodName}
+		moderatorCtx := context.WithValue(context.Background(), claimsKey{},
+			jwt.MapClaims{"userId": userID, "role": "moderator"})
+
+		_, err := interceptor(moderatorCtx, &pb.ListReceptionsRequest{PvzId: notAssigned}, info, handler)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("malformed pvz id is left to the method", func(t *testing.T) {
+		_, err := interceptor(ctx, &pb.AddProductRequest{PvzId: "invalid"}, scoped, handler)
+
+		assert.NoError(t, err)
+	})
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamAuthInterceptor(t *testing.T) {
+	interceptor := StreamAuthInterceptor(jwtkeys.NewHMACKeySet(testSecret), nil)
+	info := &grpc.StreamServerInfo{FullMethod: pb.PVZService_GetPVZList_FullMethodName}
+	token := signToken(t, jwt.SigningMethodHS256, []byte(testSecret), jwt.MapClaims{"role": "employee"})
+
+	err := interceptor(nil, &fakeServerStream{ctx: withToken(token)}, info,
+		func(srv interface{}, stream grpc.ServerStream) error {
+			claims, ok := ClaimsFromContext(stream.Context())
+			assert.True(t, ok)
+			assert.Equal(t, "employee", claims["role"])
+			return nil
+		})
+	assert.NoError(t, err)
+
+	err = interceptor(nil, &fakeServerStream{ctx: context.Background()}, info,
+		func(srv interface{}, stream grpc.ServerStream) error { return nil })
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
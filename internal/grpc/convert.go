@@ -0,0 +1,81 @@
+package grpcserver
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"pvz-service/internal/domain"
+	pb "pvz-service/internal/proto"
+	"pvz-service/internal/repository"
+)
+
+// statusFromError maps service errors to gRPC codes the same way the HTTP
+// handlers map them to status codes.
+func statusFromError(err error) error {
+	switch domain.KindOf(err) {
+	case domain.KindNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case domain.KindAlreadyExists:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case domain.KindFailedPrecondition:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case domain.KindInternal:
+		return status.Error(codes.Internal, err.Error())
+	default:
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+}
+
+func toPBPVZ(pvz domain.PVZ) *pb.PVZ {
+	return &pb.PVZ{
+		Id:               pvz.ID,
+		RegistrationDate: timestamppb.New(pvz.RegistrationDate),
+		City:             pvz.City,
+	}
+}
+
+func toPBReception(reception domain.Reception) *pb.Reception {
+	result := &pb.Reception{
+		Id:       reception.ID,
+		DateTime: timestamppb.New(reception.DateTime),
+		PvzId:    reception.PvzId,
+		Status:   toPBReceptionStatus(reception.Status),
+	}
+	if reception.ClosedAt != nil {
+		result.ClosedAt = timestamppb.New(*reception.ClosedAt)
+	}
+	return result
+}
+
+func toPBProduct(product domain.Product) *pb.Product {
+	return &pb.Product{
+		Id:          product.ID,
+		DateTime:    timestamppb.New(product.DateTime),
+		Type:        product.Type,
+		ReceptionId: product.ReceptionId,
+		Attributes:  product.Attributes,
+	}
+}
+
+func toPBReceptionWithProducts(item repository.ReceptionResponse) *pb.ReceptionWithProducts {
+	products := make([]*pb.Product, 0, len(item.Products))
+	for _, product := range item.Products {
+		products = append(products, toPBProduct(product))
+	}
+	return &pb.ReceptionWithProducts{Reception: toPBReception(item.Reception), Products: products}
+}
+
+func toPBReceptionStatus(value string) pb.ReceptionStatus {
+	if value == "close" {
+		return pb.ReceptionStatus_RECEPTION_STATUS_CLOSED
+	}
+	return pb.ReceptionStatus_RECEPTION_STATUS_IN_PROGRESS
+}
+
+func fromPBReceptionStatus(value pb.ReceptionStatus) string {
+	if value == pb.ReceptionStatus_RECEPTION_STATUS_CLOSED {
+		return "close"
+	}
+	return "in_progress"
+}
@@ -0,0 +1,158 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/events"
+	pb "pvz-service/internal/proto"
+)
+
+func (s *PVZServer) WatchPVZEvents(
+	req *pb.WatchPVZEventsRequest, stream grpc.ServerStreamingServer[pb.PVZEvent]) error {
+	for _, id := range req.GetPvzIds() {
+		if _, err := uuid.Parse(id); err != nil {
+			return status.Error(codes.InvalidArgument, "invalid pvz_ids format")
+		}
+	}
+
+	ctx := stream.Context()
+	for _, id := range req.GetPvzIds() {
+		if err := checkPVZAccess(ctx, s.assignments, id); err != nil {
+			return err
+		}
+	}
+
+	backlog, sub, err := s.events.Subscribe(req.GetLastEventId())
+	if err != nil {
+		if errors.Is(err, events.ErrHistoryExpired) {
+			return status.Error(codes.OutOfRange, err.Error())
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+	defer sub.Close()
+
+	watcher := newEventWatcher(ctx, s, req)
+	for _, event := range backlog {
+		if err := watcher.send(ctx, stream, event); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case event, ok := <-sub.C:
+			if !ok {
+				return status.Error(codes.Unavailable, "event stream fell behind, resume with last_event_id")
+			}
+			if err := watcher.send(ctx, stream, event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// eventWatcher applies the request filters to one stream. PVZ cities are looked up
+// once per PVZ and cached for the lifetime of the stream. An employee who names no
+// pvz_ids only gets events of their assigned PVZs; assignments are also looked up
+// once per PVZ, so changes apply to new streams.
+type eventWatcher struct {
+	server     *PVZServer
+	pvzIDs     map[string]bool
+	cities     map[string]bool
+	cache      map[string]string
+	employeeID string
+	assigned   map[string]bool
+}
+
+func newEventWatcher(ctx context.Context, server *PVZServer, req *pb.WatchPVZEventsRequest) *eventWatcher {
+	watcher := &eventWatcher{server: server, cache: make(map[string]string)}
+	claims, _ := ClaimsFromContext(ctx)
+	if role, _ := claims["role"].(string); role == domain.RoleEmployee && len(req.GetPvzIds()) == 0 {
+		watcher.employeeID, _ = claims["userId"].(string)
+		watcher.assigned = make(map[string]bool)
+	}
+	if len(req.GetPvzIds()) > 0 {
+		watcher.pvzIDs = make(map[string]bool, len(req.GetPvzIds()))
+		for _, id := range req.GetPvzIds() {
+			watcher.pvzIDs[id] = true
+		}
+	}
+	if len(req.GetCities()) > 0 {
+		watcher.cities = make(map[string]bool, len(req.GetCities()))
+		for _, city := range req.GetCities() {
+			watcher.cities[city] = true
+		}
+	}
+	return watcher
+}
+
+func (w *eventWatcher) send(
+	ctx context.Context, stream grpc.ServerStreamingServer[pb.PVZEvent], event events.Event) error {
+	if w.pvzIDs != nil && !w.pvzIDs[event.PVZID] {
+		return nil
+	}
+
+	if w.assigned != nil {
+		assigned, ok := w.assigned[event.PVZID]
+		if !ok {
+			var err error
+			assigned, err = w.server.assignments.IsAssigned(ctx, w.employeeID, event.PVZID)
+			if err != nil {
+				return status.Error(codes.Internal, "failed to check pvz access")
+			}
+			w.assigned[event.PVZID] = assigned
+		}
+		if !assigned {
+			return nil
+		}
+	}
+
+	city, ok := w.cache[event.PVZID]
+	if !ok {
+		pvz, err := w.server.pvzService.GetPVZByID(ctx, event.PVZID)
+		if err != nil {
+			return status.Error(codes.Internal, "database error")
+		}
+		city = pvz.City
+		w.cache[event.PVZID] = city
+	}
+
+	if w.cities != nil && !w.cities[city] {
+		return nil
+	}
+
+	return stream.Send(&pb.PVZEvent{
+		Id:          event.ID,
+		Type:        toPBEventType(event.Type),
+		OccurredAt:  timestamppb.New(event.OccurredAt),
+		PvzId:       event.PVZID,
+		City:        city,
+		ReceptionId: event.ReceptionID,
+		ProductId:   event.ProductID,
+	})
+}
+
+func toPBEventType(value events.EventType) pb.PVZEventType {
+	switch value {
+	case events.ReceptionOpened:
+		return pb.PVZEventType_PVZ_EVENT_TYPE_RECEPTION_OPENED
+	case events.ReceptionClosed:
+		return pb.PVZEventType_PVZ_EVENT_TYPE_RECEPTION_CLOSED
+	case events.ProductAdded:
+		return pb.PVZEventType_PVZ_EVENT_TYPE_PRODUCT_ADDED
+	case events.ProductDeleted:
+		return pb.PVZEventType_PVZ_EVENT_TYPE_PRODUCT_DELETED
+	default:
+		return pb.PVZEventType_PVZ_EVENT_TYPE_UNSPECIFIED
+	}
+}
@@ -0,0 +1,151 @@
+package grpcserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/events"
+	pb "pvz-service/internal/proto"
+)
+
+type fakeEventStream struct {
+	fakeServerStream
+	sent chan *pb.PVZEvent
+}
+
+func (s *fakeEventStream) Send(event *pb.PVZEvent) error {
+	s.sent <- event
+	return nil
+}
+
+func newFakeEventStream(ctx context.Context) *fakeEventStream {
+	return &fakeEventStream{fakeServerStream: fakeServerStream{ctx: ctx}, sent: make(chan *pb.PVZEvent, 10)}
+}
+
+func TestPVZServer_WatchPVZEvents(t *testing.T) {
+	moscowPVZ := uuid.New().String()
+	kazanPVZ := uuid.New().String()
+
+	t.Run("resume with city filter", func(t *testing.T) {
+		server, pvzService, _, _ := newTestServer()
+		pvzService.On("GetPVZByID", moscowPVZ).Return(domain.PVZ{ID: moscowPVZ, City: "Москва"}, nil).Once()
+		pvzService.On("GetPVZByID", kazanPVZ).Return(domain.PVZ{ID: kazanPVZ, City: "Казань"}, nil).Once()
+
+		server.events.Publish(events.Event{Type: events.ReceptionOpened, PVZID: moscowPVZ, ReceptionID: "rec1"})
+		server.events.Publish(events.Event{Type: events.ProductAdded, PVZID: moscowPVZ, ProductID: "prod1"})
+		server.events.Publish(events.Event{Type: events.ReceptionOpened, PVZID: kazanPVZ, ReceptionID: "rec2"})
+		server.events.Publish(events.Event{Type: events.ProductAdded, PVZID: moscowPVZ, ProductID: "prod2"})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		stream := newFakeEventStream(ctx)
+
+		err := server.WatchPVZEvents(&pb.WatchPVZEventsRequest{Cities: []string{"Москва"}, LastEventId: 1}, stream)
+
+		assert.Equal(t, codes.Canceled, status.Code(err))
+		close(stream.sent)
+		var sent []*pb.PVZEvent
+		for event := range stream.sent {
+			sent = append(sent, event)
+		}
+		assert.Len(t, sent, 2)
+		assert.Equal(t, uint64(2), sent[0].Id)
+		assert.Equal(t, pb.PVZEventType_PVZ_EVENT_TYPE_PRODUCT_ADDED, sent[0].Type)
+		assert.Equal(t, "Москва", sent[0].City)
+		assert.Equal(t, "prod2", sent[1].ProductId)
+		pvzService.AssertExpectations(t)
+	})
+
+	t.Run("live events filtered by pvz", func(t *testing.T) {
+		server, pvzService, _, _ := newTestServer()
+		pvzService.On("GetPVZByID", kazanPVZ).Return(domain.PVZ{ID: kazanPVZ, City: "Казань"}, nil)
+		server.events.Publish(events.Event{Type: events.ReceptionOpened, PVZID: moscowPVZ})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		stream := newFakeEventStream(ctx)
+		done := make(chan error, 1)
+		go func() {
+			done <- server.WatchPVZEvents(&pb.WatchPVZEventsRequest{PvzIds: []string{kazanPVZ}, LastEventId: 1}, stream)
+		}()
+
+		server.events.Publish(events.Event{Type: events.ReceptionClosed, PVZID: moscowPVZ})
+		server.events.Publish(events.Event{Type: events.ReceptionClosed, PVZID: kazanPVZ, ReceptionID: "rec2"})
+
+		select {
+		case event := <-stream.sent:
+			assert.Equal(t, uint64(3), event.Id)
+			assert.Equal(t, kazanPVZ, event.PvzId)
+			assert.Equal(t, pb.PVZEventType_PVZ_EVENT_TYPE_RECEPTION_CLOSED, event.Type)
+		case <-time.After(time.Second):
+			t.Fatal("event was not streamed")
+		}
+
+		cancel()
+		assert.Equal(t, codes.Canceled, status.Code(<-done))
+	})
+
+	t.Run("expired history", func(t *testing.T) {
+		server, _, _, _ := newTestServer()
+
+		err := server.WatchPVZEvents(&pb.WatchPVZEventsRequest{LastEventId: 5}, newFakeEventStream(context.Background()))
+
+		assert.Equal(t, codes.OutOfRange, status.Code(err))
+	})
+
+	t.Run("unassigned employee", func(t *testing.T) {
+		server, _, _, _ := newTestServer()
+		ctx := context.WithValue(context.Background(), claimsKey{},
+			jwt.MapClaims{"userId": uuid.NewString(), "role": "employee"})
+
+		err := server.WatchPVZEvents(&pb.WatchPVZEventsRequest{PvzIds: []string{kazanPVZ}}, newFakeEventStream(ctx))
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("employee without pvz filter gets assigned pvzs only", func(t *testing.T) {
+		server, pvzService, _, _ := newTestServer()
+		employeeID := uuid.NewString()
+		server.assignments = assignedPVZs{employeeID + "/" + kazanPVZ: true}
+		pvzService.On("GetPVZByID", kazanPVZ).Return(domain.PVZ{ID: kazanPVZ, City: "Казань"}, nil)
+
+		server.events.Publish(events.Event{Type: events.ReceptionOpened, PVZID: moscowPVZ})
+		server.events.Publish(events.Event{Type: events.ProductAdded, PVZID: moscowPVZ})
+		server.events.Publish(events.Event{Type: events.ReceptionOpened, PVZID: kazanPVZ})
+		server.events.Publish(events.Event{Type: events.ProductDeleted, PVZID: moscowPVZ})
+
+		ctx, cancel := context.WithCancel(context.WithValue(context.Background(), claimsKey{},
+			jwt.MapClaims{"userId": employeeID, "role": "employee"}))
+		cancel()
+		stream := newFakeEventStream(ctx)
+
+		err := server.WatchPVZEvents(&pb.WatchPVZEventsRequest{LastEventId: 1}, stream)
+
+		assert.Equal(t, codes.Canceled, status.Code(err))
+		close(stream.sent)
+		var sent []*pb.PVZEvent
+		for event := range stream.sent {
+			sent = append(sent, event)
+		}
+		if assert.Len(t, sent, 1) {
+			assert.Equal(t, kazanPVZ, sent[0].PvzId)
+		}
+	})
+
+	t.Run("invalid pvz id", func(t *testing.T) {
+		server, _, _, _ := newTestServer()
+
+		err := server.WatchPVZEvents(
+			&pb.WatchPVZEventsRequest{PvzIds: []string{"bad"}}, newFakeEventStream(context.Background()))
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
@@ -2,79 +2,274 @@ package grpcserver
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
-	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/events"
+	"pvz-service/internal/jwtkeys"
+	"pvz-service/internal/prometheus"
 	pb "pvz-service/internal/proto"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/service"
 )
 
+type ProductProcessor interface {
+	AddProduct(ctx context.Context, pvzID, productType string, attributes map[string]string) (domain.Product, error)
+	DeleteLastProduct(ctx context.Context, pvzID string) error
+}
+
 type PVZServer struct {
 	pb.UnimplementedPVZServiceServer
-	db *sql.DB
+	pvzService       service.PVZService
+	receptionService service.ReceptionService
+	productService   ProductProcessor
+	assignments      PVZAccessChecker
+	events           *events.Broker
 }
 
-func NewPVZServer(db *sql.DB) *PVZServer {
-	return &PVZServer{db: db}
+func NewPVZServer(
+	pvzService service.PVZService,
+	receptionService service.ReceptionService,
+	productService ProductProcessor,
+	assignments PVZAccessChecker,
+	broker *events.Broker,
+) *PVZServer {
+	return &PVZServer{
+		pvzService:       pvzService,
+		receptionService: receptionService,
+		productService:   productService,
+		assignments:      assignments,
+		events:           broker,
+	}
 }
 
 func (s *PVZServer) GetPVZList(ctx context.Context, req *pb.GetPVZListRequest) (*pb.GetPVZListResponse, error) {
-	rows, err := s.db.Query(`
-        SELECT p.id, p.registration_date, p.city
-        FROM pvz p
-        ORDER BY p.registration_date
-      `)
+	if req.GetLimit() > 0 {
+		return s.getPVZListByCursor(ctx, req)
+	}
+	if req.GetCursor() != "" {
+		return nil, status.Error(codes.InvalidArgument, "limit is required with cursor")
+	}
+
+	pvzs, err := s.pvzService.ListPVZs(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query PVZ list: %w", err)
+		return nil, statusFromError(err)
 	}
-	defer rows.Close()
 
-	var pvzList []*pb.PVZ
+	pvzList := make([]*pb.PVZ, 0, len(pvzs))
+	for _, pvz := range pvzs {
+		pvzList = append(pvzList, toPBPVZ(pvz))
+	}
+
+	return &pb.GetPVZListResponse{Pvzs: pvzList}, nil
+}
+
+func (s *PVZServer) getPVZListByCursor(
+	ctx context.Context, req *pb.GetPVZListRequest) (*pb.GetPVZListResponse, error) {
+	result, err := s.pvzService.ListPVZsByCursor(ctx, repository.PVZFilter{}, req.GetCursor(), int(req.GetLimit()))
+	if err != nil {
+		return nil, statusFromError(err)
+	}
 
-	for rows.Next() {
-		var pvzID sql.NullString
-		var pvzRegDate sql.NullTime
-		var pvzCity sql.NullString
+	pvzList := make([]*pb.PVZ, 0, len(result.Items))
+	for _, item := range result.Items {
+		pvzList = append(pvzList, toPBPVZ(item.PVZ))
+	}
 
-		err = rows.Scan(
-			&pvzID, &pvzRegDate, &pvzCity,
-		)
+	return &pb.GetPVZListResponse{Pvzs: pvzList, NextCursor: result.NextCursor}, nil
+}
 
-		if err != nil {
-			log.Println("Error scanning row:", err)
-			continue
-		}
-		if pvzID.Valid {
-			pvz := &pb.PVZ{
-				Id:               pvzID.String,
-				RegistrationDate: timestamppb.New(pvzRegDate.Time),
-				City:             pvzCity.String,
-			}
-
-			pvzList = append(pvzList, pvz)
-		}
+func (s *PVZServer) CreatePVZ(ctx context.Context, req *pb.CreatePVZRequest) (*pb.PVZ, error) {
+	if strings.TrimSpace(req.GetCity()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "city is required")
 	}
 
-	if err := rows.Err(); err != nil {
-		log.Println("Error iterating rows:", err)
+	pvz, err := s.pvzService.CreatePVZ(ctx, req.GetCity())
+	if err != nil {
+		return nil, statusFromError(err)
 	}
 
-	return &pb.GetPVZListResponse{Pvzs: pvzList}, nil
+	prometheus.PickupPointsCreated.Inc()
+	return toPBPVZ(pvz), nil
 }
 
-func StartGRPCServer(db *sql.DB, port string) error {
-	lis, err := net.Listen("tcp", ":"+port)
+func (s *PVZServer) CreateReception(ctx context.Context, req *pb.CreateReceptionRequest) (*pb.Reception, error) {
+	if _, err := uuid.Parse(req.GetPvzId()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid pvz_id format")
+	}
+
+	reception, err := s.receptionService.CreateReception(ctx, req.GetPvzId())
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	prometheus.OrderAcceptancesCreated.Inc()
+	return toPBReception(reception), nil
+}
+
+func (s *PVZServer) CloseLastReception(
+	ctx context.Context, req *pb.CloseLastReceptionRequest) (*pb.Reception, error) {
+	if _, err := uuid.Parse(req.GetPvzId()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid pvz_id format")
+	}
+
+	reception, err := s.receptionService.CloseLastReception(ctx, req.GetPvzId())
 	if err != nil {
-		return fmt.Errorf("failed to listen: %w", err)
+		return nil, statusFromError(err)
 	}
 
-	s := grpc.NewServer()
-	pb.RegisterPVZServiceServer(s, NewPVZServer(db))
+	return toPBReception(reception), nil
+}
+
+func (s *PVZServer) GetReception(
+	ctx context.Context, req *pb.GetReceptionRequest) (*pb.ReceptionWithProducts, error) {
+	if _, err := uuid.Parse(req.GetReceptionId()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid reception_id format")
+	}
+
+	reception, err := s.receptionService.GetReceptionWithProducts(ctx, req.GetReceptionId())
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	if err := checkPVZAccess(ctx, s.assignments, reception.Reception.PvzId); err != nil {
+		return nil, err
+	}
+
+	return toPBReceptionWithProducts(reception), nil
+}
+
+func (s *PVZServer) ListReceptions(
+	ctx context.Context, req *pb.ListReceptionsRequest) (*pb.ListReceptionsResponse, error) {
+	if _, err := uuid.Parse(req.GetPvzId()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid pvz_id format")
+	}
+
+	var filter repository.ReceptionFilter
+	if req.Status != nil {
+		filter.Status = fromPBReceptionStatus(req.GetStatus())
+	}
+	if req.GetStartDate() != nil {
+		filter.StartDate = req.GetStartDate().AsTime()
+	}
+	if req.GetEndDate() != nil {
+		filter.EndDate = req.GetEndDate().AsTime()
+	}
+
+	page, limit := int(req.GetPage()), int(req.GetLimit())
+	if page == 0 {
+		page = 1
+	}
+	if limit == 0 {
+		limit = 10
+	}
+
+	result, err := s.receptionService.ListReceptionsWithProducts(ctx, req.GetPvzId(), filter, page, limit)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	receptions := make([]*pb.ReceptionWithProducts, 0, len(result.Items))
+	for _, item := range result.Items {
+		receptions = append(receptions, toPBReceptionWithProducts(item))
+	}
+
+	return &pb.ListReceptionsResponse{
+		Receptions: receptions,
+		Total:      int32(result.Total),
+		Page:       int32(result.Page),
+		Limit:      int32(result.Limit),
+		TotalPages: int32(result.TotalPages),
+	}, nil
+}
+
+func (s *PVZServer) AddProduct(ctx context.Context, req *pb.AddProductRequest) (*pb.Product, error) {
+	if _, err := uuid.Parse(req.GetPvzId()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid pvz_id format")
+	}
+
+	if strings.TrimSpace(req.GetType()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "invalid product type")
+	}
+
+	product, err := s.productService.AddProduct(ctx, req.GetPvzId(), req.GetType(), req.GetAttributes())
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	prometheus.ProductsAdded.Inc()
+	return toPBProduct(product), nil
+}
+
+func (s *PVZServer) DeleteLastProduct(
+	ctx context.Context, req *pb.DeleteLastProductRequest) (*pb.DeleteLastProductResponse, error) {
+	if _, err := uuid.Parse(req.GetPvzId()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid pvz_id format")
+	}
+
+	if err := s.productService.DeleteLastProduct(ctx, req.GetPvzId()); err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return &pb.DeleteLastProductResponse{}, nil
+}
+
+func timeoutInterceptor(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if timeout <= 0 {
+			return handler(ctx, req)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+func NewGRPCServer(
+	server *PVZServer,
+	dbTimeout time.Duration,
+	keys *jwtkeys.KeySet,
+	revocations TokenRevocationChecker,
+	assignments PVZAccessChecker,
+	logger *slog.Logger,
+) *grpc.Server {
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			UnaryLoggingInterceptor(logger),
+			UnaryAuthInterceptor(keys, revocations),
+			timeoutInterceptor(dbTimeout),
+			PVZAccessInterceptor(assignments),
+		),
+		grpc.ChainStreamInterceptor(
+			StreamLoggingInterceptor(logger),
+			StreamAuthInterceptor(keys, revocations),
+		),
+	)
+	pb.RegisterPVZServiceServer(s, server)
+	return s
+}
+
+// StartGRPCServer registers the grpc.health.v1 service, reports serving and
+// serves on lis until the server is stopped.
+func StartGRPCServer(s *grpc.Server, lis net.Listener, healthServer *health.Server) error {
+	healthpb.RegisterHealthServer(s, healthServer)
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(pb.PVZService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
 
-	log.Printf("gRPC server listening at %v", lis.Addr())
+	slog.Info("gRPC server listening", "addr", lis.Addr().String())
 	if err := s.Serve(lis); err != nil {
 		return fmt.Errorf("failed to serve: %w", err)
 	}
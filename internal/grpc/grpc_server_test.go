@@ -0,0 +1,380 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/events"
+	pb "pvz-service/internal/proto"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/service"
+)
+
+type MockPVZService struct {
+	mock.Mock
+}
+
+func (m *MockPVZService) CreatePVZ(ctx context.Context, city string) (domain.PVZ, error) {
+	args := m.Called(city)
+	return args.Get(0).(domain.PVZ), args.Error(1)
+}
+
+func (m *MockPVZService) GetPVZByID(ctx context.Context, id string) (domain.PVZ, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.PVZ), args.Error(1)
+}
+
+func (m *MockPVZService) ListPVZs(ctx context.Context) ([]domain.PVZ, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.PVZ), args.Error(1)
+}
+
+func (m *MockPVZService) ListPVZsWithRelations(
+	ctx context.Context, filter repository.PVZFilter, sort repository.PVZSort, page, limit int,
+) (repository.PVZListResponse, error) {
+	args := m.Called(filter, sort, page, limit)
+	return args.Get(0).(repository.PVZListResponse), args.Error(1)
+}
+
+func (m *MockPVZService) ListPVZsByCursor(
+	ctx context.Context, filter repository.PVZFilter, cursor string, limit int) (repository.PVZCursorResponse, error) {
+	args := m.Called(filter, cursor, limit)
+	return args.Get(0).(repository.PVZCursorResponse), args.Error(1)
+}
+
+type MockReceptionService struct {
+	mock.Mock
+}
+
+func (m *MockReceptionService) CreateReception(ctx context.Context, pvzID string) (domain.Reception, error) {
+	args := m.Called(pvzID)
+	return args.Get(0).(domain.Reception), args.Error(1)
+}
+
+func (m *MockReceptionService) CloseLastReception(ctx context.Context, pvzID string) (domain.Reception, error) {
+	args := m.Called(pvzID)
+	return args.Get(0).(domain.Reception), args.Error(1)
+}
+
+func (m *MockReceptionService) GetReceptionByID(ctx context.Context, id string) (domain.Reception, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.Reception), args.Error(1)
+}
+
+func (m *MockReceptionService) ListReceptionsByPVZ(ctx context.Context, pvzID string,
+	filter repository.ReceptionFilter, page, limit int) (repository.ReceptionListResponse, error) {
+	args := m.Called(pvzID, filter, page, limit)
+	return args.Get(0).(repository.ReceptionListResponse), args.Error(1)
+}
+
+func (m *MockReceptionService) ListReceptionProducts(
+	ctx context.Context, receptionID string, page, limit int) (repository.ProductListResponse, error) {
+	args := m.Called(receptionID, page, limit)
+	return args.Get(0).(repository.ProductListResponse), args.Error(1)
+}
+
+func (m *MockReceptionService) GetReceptionWithProducts(
+	ctx context.Context, id string) (repository.ReceptionResponse, error) {
+	args := m.Called(id)
+	return args.Get(0).(repository.ReceptionResponse), args.Error(1)
+}
+
+func (m *MockReceptionService) ListReceptionsWithProducts(ctx context.Context, pvzID string,
+	filter repository.ReceptionFilter, page, limit int) (repository.ReceptionWithProductsListResponse, error) {
+	args := m.Called(pvzID, filter, page, limit)
+	return args.Get(0).(repository.ReceptionWithProductsListResponse), args.Error(1)
+}
+
+type MockProductProcessor struct {
+	mock.Mock
+}
+
+func (m *MockProductProcessor) AddProduct(
+	ctx context.Context, pvzID, productType string, attributes map[string]string) (domain.Product, error) {
+	args := m.Called(pvzID, productType, attributes)
+	return args.Get(0).(domain.Product), args.Error(1)
+}
+
+func (m *MockProductProcessor) DeleteLastProduct(ctx context.Context, pvzID string) error {
+	args := m.Called(pvzID)
+	return args.Error(0)
+}
+
+func newTestServer() (*PVZServer, *MockPVZService, *MockReceptionService, *MockProductProcessor) {
+	pvzService := new(MockPVZService)
+	receptionService := new(MockReceptionService)
+	productService := new(MockProductProcessor)
+	server := NewPVZServer(pvzService, receptionService, productService, assignedPVZs{}, events.NewBroker(10, 10))
+	return server, pvzService, receptionService, productService
+}
+
+func TestPVZServer_GetPVZList(t *testing.T) {
+	t.Run("all pvz", func(t *testing.T) {
+		server, pvzService, _, _ := newTestServer()
+		now := time.Now()
+		pvzService.On("ListPVZs").Return([]domain.PVZ{{ID: "pvz1", RegistrationDate: now, City: "Москва"}}, nil)
+
+		resp, err := server.GetPVZList(context.Background(), &pb.GetPVZListRequest{})
+
+		assert.NoError(t, err)
+		assert.Len(t, resp.Pvzs, 1)
+		assert.Equal(t, "Москва", resp.Pvzs[0].City)
+		assert.True(t, resp.Pvzs[0].RegistrationDate.AsTime().Equal(now))
+	})
+
+	t.Run("cursor without limit", func(t *testing.T) {
+		server, _, _, _ := newTestServer()
+
+		_, err := server.GetPVZList(context.Background(), &pb.GetPVZListRequest{Cursor: "abc"})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		server, pvzService, _, _ := newTestServer()
+		pvzService.On("ListPVZs").Return([]domain.PVZ(nil), &domain.InternalError{Msg: "database error"})
+
+		_, err := server.GetPVZList(context.Background(), &pb.GetPVZListRequest{})
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestPVZServer_CreatePVZ(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		server, pvzService, _, _ := newTestServer()
+		pvzService.On("CreatePVZ", "Москва").Return(domain.PVZ{ID: "pvz1", City: "Москва"}, nil)
+
+		resp, err := server.CreatePVZ(context.Background(), &pb.CreatePVZRequest{City: "Москва"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "pvz1", resp.Id)
+	})
+
+	t.Run("invalid city", func(t *testing.T) {
+		server, pvzService, _, _ := newTestServer()
+		pvzService.On("CreatePVZ", "Тверь").Return(domain.PVZ{}, errors.New("invalid city"))
+
+		_, err := server.CreatePVZ(context.Background(), &pb.CreatePVZRequest{City: "Тверь"})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestPVZServer_Receptions(t *testing.T) {
+	pvzID := uuid.New().String()
+
+	t.Run("create conflict", func(t *testing.T) {
+		server, _, receptionService, _ := newTestServer()
+		receptionService.On("CreateReception", pvzID).Return(domain.Reception{}, domain.ErrOpenReceptionExists)
+
+		_, err := server.CreateReception(context.Background(), &pb.CreateReceptionRequest{PvzId: pvzID})
+
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("invalid pvz id", func(t *testing.T) {
+		server, _, receptionService, _ := newTestServer()
+
+		_, err := server.CloseLastReception(context.Background(), &pb.CloseLastReceptionRequest{PvzId: "bad"})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		receptionService.AssertNotCalled(t, "CloseLastReception", mock.Anything)
+	})
+
+	t.Run("close", func(t *testing.T) {
+		server, _, receptionService, _ := newTestServer()
+		closedAt := time.Now()
+		receptionService.On("CloseLastReception", pvzID).
+			Return(domain.Reception{ID: "rec1", PvzId: pvzID, Status: "close", ClosedAt: &closedAt}, nil)
+
+		resp, err := server.CloseLastReception(context.Background(), &pb.CloseLastReceptionRequest{PvzId: pvzID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, pb.ReceptionStatus_RECEPTION_STATUS_CLOSED, resp.Status)
+		assert.True(t, resp.ClosedAt.AsTime().Equal(closedAt))
+	})
+
+	t.Run("close without open reception", func(t *testing.T) {
+		server, _, receptionService, _ := newTestServer()
+		receptionService.On("CloseLastReception", pvzID).
+			Return(domain.Reception{}, domain.ErrNoOpenReception)
+
+		_, err := server.CloseLastReception(context.Background(), &pb.CloseLastReceptionRequest{PvzId: pvzID})
+
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("get not found", func(t *testing.T) {
+		server, _, receptionService, _ := newTestServer()
+		receptionID := uuid.New().String()
+		receptionService.On("GetReceptionWithProducts", receptionID).
+			Return(repository.ReceptionResponse{}, domain.ErrReceptionNotFound)
+
+		_, err := server.GetReception(context.Background(), &pb.GetReceptionRequest{ReceptionId: receptionID})
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("get reception of unassigned pvz", func(t *testing.T) {
+		server, _, receptionService, _ := newTestServer()
+		receptionID := uuid.New().String()
+		receptionService.On("GetReceptionWithProducts", receptionID).Return(repository.ReceptionResponse{
+			Reception: domain.Reception{ID: receptionID, PvzId: pvzID},
+		}, nil)
+		ctx := context.WithValue(context.Background(), claimsKey{},
+			jwt.MapClaims{"userId": uuid.NewString(), "role": "employee"})
+
+		_, err := server.GetReception(ctx, &pb.GetReceptionRequest{ReceptionId: receptionID})
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("list with filter and defaults", func(t *testing.T) {
+		server, _, receptionService, _ := newTestServer()
+		start := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+		closed := pb.ReceptionStatus_RECEPTION_STATUS_CLOSED
+		filter := repository.ReceptionFilter{Status: "close", StartDate: start}
+		receptionService.On("ListReceptionsWithProducts", pvzID, filter, 1, 10).
+			Return(repository.ReceptionWithProductsListResponse{
+				Items: []repository.ReceptionResponse{{
+					Reception: domain.Reception{ID: "rec1", Status: "close"},
+					Products:  []domain.Product{{ID: "prod1", Attributes: map[string]string{"size": "42"}}},
+				}},
+				Total: 1, Page: 1, Limit: 10, TotalPages: 1,
+			}, nil)
+
+		resp, err := server.ListReceptions(context.Background(), &pb.ListReceptionsRequest{
+			PvzId:     pvzID,
+			Status:    &closed,
+			StartDate: timestamppb.New(start),
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), resp.Total)
+		assert.Len(t, resp.Receptions, 1)
+		assert.Equal(t, "42", resp.Receptions[0].Products[0].Attributes["size"])
+	})
+}
+
+func TestPVZServer_Products(t *testing.T) {
+	pvzID := uuid.New().String()
+
+	t.Run("add", func(t *testing.T) {
+		server, _, _, productService := newTestServer()
+		attributes := map[string]string{"size": "42"}
+		productService.On("AddProduct", pvzID, "обувь", attributes).
+			Return(domain.Product{ID: "prod1", Type: "обувь", Attributes: attributes}, nil)
+
+		resp, err := server.AddProduct(context.Background(), &pb.AddProductRequest{
+			PvzId: pvzID, Type: "обувь", Attributes: attributes,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "prod1", resp.Id)
+	})
+
+	t.Run("add invalid attributes", func(t *testing.T) {
+		server, _, _, productService := newTestServer()
+		productService.On("AddProduct", pvzID, "обувь", map[string]string(nil)).
+			Return(domain.Product{}, domain.ErrInvalidProductAttributes)
+
+		_, err := server.AddProduct(context.Background(), &pb.AddProductRequest{PvzId: pvzID, Type: "обувь"})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("delete database error", func(t *testing.T) {
+		server, _, _, productService := newTestServer()
+		productService.On("DeleteLastProduct", pvzID).Return(&domain.InternalError{Msg: "database error"})
+
+		_, err := server.DeleteLastProduct(context.Background(), &pb.DeleteLastProductRequest{PvzId: pvzID})
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("delete without products", func(t *testing.T) {
+		server, _, _, productService := newTestServer()
+		productService.On("DeleteLastProduct", pvzID).Return(domain.ErrNoProductsToDelete)
+
+		_, err := server.DeleteLastProduct(context.Background(), &pb.DeleteLastProductRequest{PvzId: pvzID})
+
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+}
+
+func TestPVZServer_TransactionFailure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	txManager := repository.NewTxManager(db)
+	receptionService := service.NewReceptionService(repository.NewReceptionRepository(db),
+		repository.NewProductRepository(db), repository.NewPVZRepository(db), txManager, nil)
+	productService := service.NewProductService(txManager, repository.NewProductTypeRepository(db), nil)
+	server := NewPVZServer(nil, receptionService, productService, assignedPVZs{}, events.NewBroker(10, 10))
+	pvzID := uuid.NewString()
+
+	mock.ExpectBegin().WillReturnError(errors.New("context deadline exceeded"))
+	_, err = server.DeleteLastProduct(context.Background(), &pb.DeleteLastProductRequest{PvzId: pvzID})
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, "failed to delete product", status.Convert(err).Message())
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FOR UPDATE").WithArgs(pvzID).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "created_at", "pvz_id", "status", "closed_at"}).
+			AddRow(uuid.NewString(), time.Now(), pvzID, "in_progress", nil))
+	mock.ExpectExec("UPDATE receptions SET status = 'close'").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit().WillReturnError(errors.New("pq: could not serialize access"))
+	_, err = server.CloseLastReception(context.Background(), &pb.CloseLastReceptionRequest{PvzId: pvzID})
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, "failed to close reception", status.Convert(err).Message())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStartGRPCServer_Health(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	served := make(chan error, 1)
+	go func() { served <- StartGRPCServer(server, lis, healthServer) }()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	for _, service := range []string{"", pb.PVZService_ServiceDesc.ServiceName} {
+		resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		assert.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+	}
+
+	healthServer.Shutdown()
+	resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.GetStatus())
+
+	server.GracefulStop()
+	assert.NoError(t, <-served)
+}
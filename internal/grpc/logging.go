@@ -0,0 +1,75 @@
+package grpcserver
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"pvz-service/internal/logging"
+)
+
+// UnaryLoggingInterceptor is the gRPC counterpart of middleware.RequestLogger:
+// it takes the request id from x-request-id metadata, echoes it in the response
+// header and logs every call with the fields added while handling it. It must
+// run first so that the other interceptors see the request logger.
+func UnaryLoggingInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		requestID := incomingRequestID(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(strings.ToLower(logging.RequestIDHeader), requestID))
+
+		ctx = logging.NewContext(ctx, logger.With("requestId", requestID))
+		if scoped, ok := req.(interface{ GetPvzId() string }); ok && scoped.GetPvzId() != "" {
+			logging.AddFields(ctx, "pvzId", scoped.GetPvzId())
+		}
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logRPC(ctx, info.FullMethod, err, start)
+		return resp, err
+	}
+}
+
+func StreamLoggingInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		requestID := incomingRequestID(ss.Context())
+		_ = ss.SetHeader(metadata.Pairs(strings.ToLower(logging.RequestIDHeader), requestID))
+
+		ctx := logging.NewContext(ss.Context(), logger.With("requestId", requestID))
+
+		start := time.Now()
+		err := handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+		logRPC(ctx, info.FullMethod, err, start)
+		return err
+	}
+}
+
+func incomingRequestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(logging.RequestIDHeader)
+	if len(values) == 0 {
+		return logging.RequestID("")
+	}
+	return logging.RequestID(values[0])
+}
+
+func logRPC(ctx context.Context, method string, err error, start time.Time) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch code {
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		level = slog.LevelError
+	}
+	logging.FromContext(ctx).Log(ctx, level, "rpc",
+		"method", method,
+		"code", code.String(),
+		"latency", time.Since(start),
+	)
+}
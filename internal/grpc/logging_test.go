@@ -0,0 +1,41 @@
+package grpcserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"pvz-service/internal/logging"
+	pb "pvz-service/internal/proto"
+)
+
+func TestUnaryLoggingInterceptor(t *testing.T) {
+	var buf bytes.Buffer
+	interceptor := UnaryLoggingInterceptor(logging.New(&buf, "info"))
+	info := &grpc.UnaryServerInfo{FullMethod: pb.PVZService_CloseLastReception_FullMethodName}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-1"))
+
+	_, err := interceptor(ctx, &pb.CloseLastReceptionRequest{PvzId: "pvz1"}, info,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			logging.AddFields(ctx, "userId", "user1")
+			return nil, status.Error(codes.Internal, "failed to close reception")
+		})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	var record map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, "rpc", record["msg"])
+	assert.Equal(t, "req-1", record["requestId"])
+	assert.Equal(t, "user1", record["userId"])
+	assert.Equal(t, "pvz1", record["pvzId"])
+	assert.Equal(t, pb.PVZService_CloseLastReception_FullMethodName, record["method"])
+	assert.Equal(t, "Internal", record["code"])
+}
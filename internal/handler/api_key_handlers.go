@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/handler/models"
+	"pvz-service/internal/service"
+)
+
+type APIKeyHandlers struct {
+	apiKeyService service.APIKeyService
+}
+
+func NewAPIKeyHandlers(apiKeyService service.APIKeyService) *APIKeyHandlers {
+	return &APIKeyHandlers{apiKeyService: apiKeyService}
+}
+
+func (h *APIKeyHandlers) CreateAPIKeyHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actorID, ok := userIDFromClaims(c)
+		if !ok {
+			return c.Status(fiber.StatusForbidden).JSON(models.ErrorResponse{Message: "API keys require a user token"})
+		}
+
+		var body models.CreateAPIKeyRequest
+		if err := c.BodyParser(&body); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid request body format"})
+		}
+
+		key, plainKey, err := h.apiKeyService.CreateAPIKey(
+			c.UserContext(), actorID, body.Name, body.Role, body.PVZIDs, body.ExpiresAt)
+		if err != nil {
+			return c.Status(apiKeyErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(models.CreatedAPIKey{APIKey: key, Key: plainKey})
+	}
+}
+
+func (h *APIKeyHandlers) ListAPIKeysHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		keys, err := h.apiKeyService.ListAPIKeys(c.UserContext())
+		if err != nil {
+			return c.Status(apiKeyErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.JSON(keys)
+	}
+}
+
+func (h *APIKeyHandlers) RevokeAPIKeyHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, ok := userIDFromClaims(c); !ok {
+			return c.Status(fiber.StatusForbidden).JSON(models.ErrorResponse{Message: "API keys require a user token"})
+		}
+
+		keyID := c.Params("keyId")
+		if _, err := uuid.Parse(keyID); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid keyId format"})
+		}
+
+		if err := h.apiKeyService.RevokeAPIKey(c.UserContext(), keyID); err != nil {
+			return c.Status(apiKeyErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+// userIDFromClaims returns the user id of a token-authenticated request. Requests
+// made with an API key have none.
+func userIDFromClaims(c *fiber.Ctx) (string, bool) {
+	claims := c.Locals("claims").(jwt.MapClaims)
+	userID, _ := claims["userId"].(string)
+	if _, err := uuid.Parse(userID); err != nil {
+		return "", false
+	}
+	return userID, true
+}
+
+func apiKeyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrAPIKeyNotFound), errors.Is(err, domain.ErrPVZNotFound):
+		return fiber.StatusNotFound
+	case err.Error() == "name is required",
+		err.Error() == "invalid role",
+		err.Error() == "invalid pvzId",
+		err.Error() == "expiresAt must be in the future":
+		return fiber.StatusBadRequest
+	}
+	return fiber.StatusInternalServerError
+}
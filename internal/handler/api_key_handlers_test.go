@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/handler/models"
+)
+
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyService) CreateAPIKey(ctx context.Context, actorID, name, role string, pvzIDs []string,
+	expiresAt *time.Time) (domain.APIKey, string, error) {
+	args := m.Called(actorID, name, role, pvzIDs, expiresAt)
+	return args.Get(0).(domain.APIKey), args.String(1), args.Error(2)
+}
+
+func (m *MockAPIKeyService) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyService) RevokeAPIKey(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyService) AuthenticateAPIKey(ctx context.Context, key string) (domain.APIKey, error) {
+	args := m.Called(key)
+	return args.Get(0).(domain.APIKey), args.Error(1)
+}
+
+func TestAPIKeyHandlers_CreateAPIKeyHandler(t *testing.T) {
+	app := fiber.New()
+	mockService := new(MockAPIKeyService)
+	handler := NewAPIKeyHandlers(mockService)
+
+	actorID := uuid.NewString()
+	pvzID := uuid.NewString()
+	app.Post("/api-keys", withActor(actorID, "moderator"), handler.CreateAPIKeyHandler())
+	app.Post("/via-key/api-keys", withActor("", "moderator"), handler.CreateAPIKeyHandler())
+
+	mockService.On("CreateAPIKey", actorID, "script", "employee", []string{pvzID}, (*time.Time)(nil)).
+		Return(domain.APIKey{ID: "key1", Role: "employee", PVZIDs: []string{pvzID}}, "pvz_secret", nil)
+
+	req := httptest.NewRequest("POST", "/api-keys",
+		bytes.NewBufferString(`{"name":"script","role":"employee","pvzIds":["`+pvzID+`"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	var created models.CreatedAPIKey
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	assert.Equal(t, "key1", created.ID)
+	assert.Equal(t, "pvz_secret", created.Key)
+
+	assert.Equal(t, fiber.StatusForbidden, postJSON(t, app, "/via-key/api-keys", `{"name":"script","role":"employee"}`))
+	mockService.AssertNumberOfCalls(t, "CreateAPIKey", 1)
+}
+
+func TestAPIKeyHandlers_ListAPIKeysHandler(t *testing.T) {
+	app := fiber.New()
+	mockService := new(MockAPIKeyService)
+	handler := NewAPIKeyHandlers(mockService)
+	app.Get("/api-keys", handler.ListAPIKeysHandler())
+
+	mockService.On("ListAPIKeys").Return([]domain.APIKey{{ID: "key1"}, {ID: "key2"}}, nil)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api-keys", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var keys []domain.APIKey
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&keys))
+	assert.Len(t, keys, 2)
+}
+
+func TestAPIKeyHandlers_RevokeAPIKeyHandler(t *testing.T) {
+	app := fiber.New()
+	mockService := new(MockAPIKeyService)
+	handler := NewAPIKeyHandlers(mockService)
+	app.Delete("/api-keys/:keyId", withActor(uuid.NewString(), "moderator"), handler.RevokeAPIKeyHandler())
+
+	keyID := uuid.NewString()
+	missingID := uuid.NewString()
+	mockService.On("RevokeAPIKey", keyID).Return(nil)
+	mockService.On("RevokeAPIKey", missingID).Return(domain.ErrAPIKeyNotFound)
+
+	for id, status := range map[string]int{
+		keyID:     fiber.StatusNoContent,
+		missingID: fiber.StatusNotFound,
+		"bad-id":  fiber.StatusBadRequest,
+	} {
+		resp, err := app.Test(httptest.NewRequest("DELETE", "/api-keys/"+id, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, status, resp.StatusCode, id)
+	}
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/handler/models"
+	"pvz-service/internal/service"
+)
+
+type AssignmentHandlers struct {
+	assignmentService service.AssignmentService
+}
+
+func NewAssignmentHandlers(assignmentService service.AssignmentService) *AssignmentHandlers {
+	return &AssignmentHandlers{assignmentService: assignmentService}
+}
+
+func (h *AssignmentHandlers) AssignPVZHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID := c.Params("userId")
+		if _, err := uuid.Parse(userID); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid userId format"})
+		}
+
+		pvzID := c.Params("pvzId")
+		if _, err := uuid.Parse(pvzID); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid pvzId format"})
+		}
+
+		if err := h.assignmentService.AssignPVZ(c.UserContext(), userID, pvzID); err != nil {
+			return c.Status(assignmentErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (h *AssignmentHandlers) UnassignPVZHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID := c.Params("userId")
+		if _, err := uuid.Parse(userID); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid userId format"})
+		}
+
+		pvzID := c.Params("pvzId")
+		if _, err := uuid.Parse(pvzID); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid pvzId format"})
+		}
+
+		if err := h.assignmentService.UnassignPVZ(c.UserContext(), userID, pvzID); err != nil {
+			return c.Status(assignmentErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (h *AssignmentHandlers) ListUserPVZsHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID := c.Params("userId")
+		if _, err := uuid.Parse(userID); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid userId format"})
+		}
+
+		pvzIDs, err := h.assignmentService.ListAssignedPVZs(c.UserContext(), userID)
+		if err != nil {
+			return c.Status(assignmentErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.JSON(models.UserPVZAssignments{UserID: userID, PVZIDs: pvzIDs})
+	}
+}
+
+func assignmentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound),
+		errors.Is(err, domain.ErrPVZNotFound),
+		errors.Is(err, domain.ErrAssignmentNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, domain.ErrUserNotEmployee):
+		return fiber.StatusBadRequest
+	}
+	return fiber.StatusInternalServerError
+}
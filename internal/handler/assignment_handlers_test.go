@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/handler/models"
+)
+
+type MockAssignmentService struct {
+	mock.Mock
+}
+
+func (m *MockAssignmentService) AssignPVZ(ctx context.Context, userID, pvzID string) error {
+	args := m.Called(userID, pvzID)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentService) UnassignPVZ(ctx context.Context, userID, pvzID string) error {
+	args := m.Called(userID, pvzID)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentService) ListAssignedPVZs(ctx context.Context, userID string) ([]string, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockAssignmentService) IsAssigned(ctx context.Context, userID, pvzID string) (bool, error) {
+	args := m.Called(userID, pvzID)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestAssignmentHandlers_AssignPVZHandler(t *testing.T) {
+	app := fiber.New()
+	mockService := new(MockAssignmentService)
+	handler := NewAssignmentHandlers(mockService)
+	app.Put("/users/:userId/pvz/:pvzId", handler.AssignPVZHandler())
+
+	userID, pvzID := uuid.NewString(), uuid.NewString()
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("AssignPVZ", userID, pvzID).Return(nil).Once()
+
+		resp, err := app.Test(httptest.NewRequest("PUT", "/users/"+userID+"/pvz/"+pvzID, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("not an employee", func(t *testing.T) {
+		mockService.On("AssignPVZ", userID, pvzID).Return(domain.ErrUserNotEmployee).Once()
+
+		resp, err := app.Test(httptest.NewRequest("PUT", "/users/"+userID+"/pvz/"+pvzID, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("pvz not found", func(t *testing.T) {
+		mockService.On("AssignPVZ", userID, pvzID).Return(domain.ErrPVZNotFound).Once()
+
+		resp, err := app.Test(httptest.NewRequest("PUT", "/users/"+userID+"/pvz/"+pvzID, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("invalid user id", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest("PUT", "/users/invalid/pvz/"+pvzID, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestAssignmentHandlers_UnassignPVZHandler(t *testing.T) {
+	app := fiber.New()
+	mockService := new(MockAssignmentService)
+	handler := NewAssignmentHandlers(mockService)
+	app.Delete("/users/:userId/pvz/:pvzId", handler.UnassignPVZHandler())
+
+	userID, pvzID := uuid.NewString(), uuid.NewString()
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("UnassignPVZ", userID, pvzID).Return(nil).Once()
+
+		resp, err := app.Test(httptest.NewRequest("DELETE", "/users/"+userID+"/pvz/"+pvzID, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+	})
+
+	t.Run("not assigned", func(t *testing.T) {
+		mockService.On("UnassignPVZ", userID, pvzID).Return(domain.ErrAssignmentNotFound).Once()
+
+		resp, err := app.Test(httptest.NewRequest("DELETE", "/users/"+userID+"/pvz/"+pvzID, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("invalid pvz id", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest("DELETE", "/users/"+userID+"/pvz/invalid", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestAssignmentHandlers_ListUserPVZsHandler(t *testing.T) {
+	app := fiber.New()
+	mockService := new(MockAssignmentService)
+	handler := NewAssignmentHandlers(mockService)
+	app.Get("/users/:userId/pvz", handler.ListUserPVZsHandler())
+
+	userID, pvzID := uuid.NewString(), uuid.NewString()
+	mockService.On("ListAssignedPVZs", userID).Return([]string{pvzID}, nil)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/"+userID+"/pvz", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body models.UserPVZAssignments
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, userID, body.UserID)
+	assert.Equal(t, []string{pvzID}, body.PVZIDs)
+}
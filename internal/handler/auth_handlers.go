@@ -1,38 +1,60 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"math"
+	"strconv"
 	"time"
 
+	"pvz-service/internal/domain"
 	"pvz-service/internal/handler/models"
+	"pvz-service/internal/jwtkeys"
 	"pvz-service/internal/service"
 )
 
 type AuthHandlers struct {
 	authProcessor service.AuthService
-	secret        string
+	keys          *jwtkeys.KeySet
 }
 
-func NewAuthHandlers(authProcessor service.AuthService, secret string) *AuthHandlers {
+func NewAuthHandlers(authProcessor service.AuthService, keys *jwtkeys.KeySet) *AuthHandlers {
 	return &AuthHandlers{
 		authProcessor: authProcessor,
-		secret:        secret,
+		keys:          keys,
 	}
 }
 
-func (h *AuthHandlers) GenerateToken(userID, role string) (string, error) {
+func (h *AuthHandlers) GenerateToken(userID, role, jti string) (string, error) {
 	claims := jwt.MapClaims{
 		"userId": userID,
 		"role":   role,
+		"jti":    jti,
 		"exp":    time.Now().Add(time.Hour * 1).Unix(),
 		"iat":    time.Now().Unix(),
 		"nbf":    time.Now().Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(h.secret))
+	return h.keys.Sign(claims)
+}
+
+// issueTokens signs an access token with a fresh jti and opens a session for it.
+func (h *AuthHandlers) issueTokens(c *fiber.Ctx, userID, role string) (models.TokenResponse, error) {
+	jti := uuid.NewString()
+	token, err := h.GenerateToken(userID, role, jti)
+	if err != nil {
+		return models.TokenResponse{}, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	refreshToken, err := h.authProcessor.StartSession(c.UserContext(), userID, jti)
+	if err != nil {
+		return models.TokenResponse{}, err
+	}
+
+	return models.TokenResponse{Token: token, RefreshToken: refreshToken}, nil
 }
 
 func (h *AuthHandlers) DummyLoginHandler() fiber.Handler {
@@ -46,14 +68,18 @@ func (h *AuthHandlers) DummyLoginHandler() fiber.Handler {
 			})
 		}
 
-		userID, err := h.authProcessor.DummyLogin(body.Role)
+		userID, err := h.authProcessor.DummyLogin(c.UserContext(), body.Role)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			status := fiber.StatusBadRequest
+			if errors.Is(err, domain.ErrInternal) {
+				status = fiber.StatusInternalServerError
+			}
+			return c.Status(status).JSON(models.ErrorResponse{
 				Message: err.Error(),
 			})
 		}
 
-		token, err := h.GenerateToken(userID, body.Role)
+		token, err := h.GenerateToken(userID, body.Role, uuid.NewString())
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
 				Message: fmt.Sprintf("Failed to generate token: %v", err.Error()),
@@ -77,25 +103,29 @@ func (h *AuthHandlers) RegisterHandler() fiber.Handler {
 				Message: "Invalid request body format",
 			})
 		}
-		userID, err := h.authProcessor.Register(body.Email, body.Password, body.Role)
+		userID, err := h.authProcessor.Register(c.UserContext(), body.Email, body.Password, body.Role)
 		if err != nil {
 			status := fiber.StatusInternalServerError
-			if err.Error() == "invalid role" || err.Error() == "email already exists" {
+			switch {
+			case err.Error() == "invalid role", errors.Is(err, domain.ErrEmailAlreadyExists),
+				errors.Is(err, domain.ErrInvalidEmail), errors.Is(err, domain.ErrWeakPassword):
 				status = fiber.StatusBadRequest
+			case err.Error() == "registration is disabled":
+				status = fiber.StatusForbidden
 			}
 			return c.Status(status).JSON(models.ErrorResponse{
 				Message: err.Error(),
 			})
 		}
 
-		token, err := h.GenerateToken(userID, body.Role)
+		tokens, err := h.issueTokens(c, userID, body.Role)
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
-				Message: "Failed to generate token: " + err.Error(),
+				Message: err.Error(),
 			})
 		}
 
-		return c.Status(fiber.StatusCreated).JSON(models.TokenResponse{Token: token})
+		return c.Status(fiber.StatusCreated).JSON(tokens)
 	}
 }
 
@@ -112,20 +142,91 @@ func (h *AuthHandlers) LoginHandler() fiber.Handler {
 			})
 		}
 
-		userID, role, err := h.authProcessor.Login(body.Email, body.Password)
+		userID, role, err := h.authProcessor.Login(c.UserContext(), body.Email, body.Password, c.IP())
+		if err != nil {
+			status := fiber.StatusUnauthorized
+			var lockout *domain.LockoutError
+			switch {
+			case errors.As(err, &lockout):
+				status = fiber.StatusTooManyRequests
+				c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(lockout.RetryAfter.Seconds()))))
+			case errors.Is(err, domain.ErrUserDisabled):
+				status = fiber.StatusForbidden
+			case errors.Is(err, domain.ErrInternal):
+				status = fiber.StatusInternalServerError
+			}
+			return c.Status(status).JSON(models.ErrorResponse{
+				Message: err.Error(),
+			})
+		}
+
+		tokens, err := h.issueTokens(c, userID, role)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+				Message: err.Error(),
+			})
+		}
+
+		return c.JSON(tokens)
+	}
+}
+
+func (h *AuthHandlers) RefreshTokenHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var body models.RefreshTokenRequest
+		if err := c.BodyParser(&body); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Message: "Invalid request body format",
+			})
+		}
+
+		jti := uuid.NewString()
+		userID, role, refreshToken, err := h.authProcessor.RefreshSession(c.UserContext(), body.RefreshToken, jti)
 		if err != nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{
+			status := fiber.StatusInternalServerError
+			if err.Error() == "invalid refresh token" {
+				status = fiber.StatusUnauthorized
+			}
+			return c.Status(status).JSON(models.ErrorResponse{
 				Message: err.Error(),
 			})
 		}
 
-		token, err := h.GenerateToken(userID, role)
+		token, err := h.GenerateToken(userID, role, jti)
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
 				Message: "Failed to generate token: " + err.Error(),
 			})
 		}
 
-		return c.JSON(models.TokenResponse{Token: token})
+		return c.JSON(models.TokenResponse{Token: token, RefreshToken: refreshToken})
+	}
+}
+
+func (h *AuthHandlers) LogoutHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims := c.Locals("claims").(jwt.MapClaims)
+
+		jti, _ := claims["jti"].(string)
+		expiresAt, err := claims.GetExpirationTime()
+		if jti == "" || err != nil || expiresAt == nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Message: "Token does not support logout",
+			})
+		}
+
+		if err := h.authProcessor.Logout(c.UserContext(), jti, expiresAt.Time); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+				Message: err.Error(),
+			})
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (h *AuthHandlers) JWKSHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return c.JSON(h.keys.JWKS())
 	}
 }
@@ -2,6 +2,8 @@ package handler
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"net/http/httptest"
 	"testing"
@@ -11,23 +13,32 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/handler/models"
+	"pvz-service/internal/jwtkeys"
 )
 
 type MockAuthProcessor struct {
 	mock.Mock
 }
 
-func (m *MockAuthProcessor) Register(email, password, role string) (string, error) {
+func (m *MockAuthProcessor) Register(ctx context.Context, email, password, role string) (string, error) {
 	args := m.Called(email, password, role)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockAuthProcessor) Login(email, password string) (string, string, error) {
+func (m *MockAuthProcessor) CreateUser(ctx context.Context, email, password, role string) (string, error) {
+	args := m.Called(email, password, role)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAuthProcessor) Login(ctx context.Context, email, password, clientIP string) (string, string, error) {
 	args := m.Called(email, password)
 	return args.String(0), args.String(1), args.Error(2)
 }
 
-func (m *MockAuthProcessor) DummyLogin(role string) (string, error) {
+func (m *MockAuthProcessor) DummyLogin(ctx context.Context, role string) (string, error) {
 	args := m.Called(role)
 	return args.String(0), args.Error(1)
 }
@@ -42,10 +53,36 @@ func (m *MockAuthProcessor) ComparePassword(hashedPassword, password string) err
 	return args.Error(0)
 }
 
+func (m *MockAuthProcessor) StartSession(ctx context.Context, userID, accessJTI string) (string, error) {
+	args := m.Called(userID, accessJTI)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAuthProcessor) RefreshSession(
+	ctx context.Context, refreshToken, accessJTI string) (string, string, string, error) {
+	args := m.Called(refreshToken, accessJTI)
+	return args.String(0), args.String(1), args.String(2), args.Error(3)
+}
+
+func (m *MockAuthProcessor) Logout(ctx context.Context, accessJTI string, accessExpiresAt time.Time) error {
+	args := m.Called(accessJTI, accessExpiresAt)
+	return args.Error(0)
+}
+
+func (m *MockAuthProcessor) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	args := m.Called(jti)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAuthProcessor) IsUserDisabled(ctx context.Context, userID string) (bool, error) {
+	args := m.Called(userID)
+	return args.Bool(0), args.Error(1)
+}
+
 func TestAuthHandlers_DummyLoginHandler_Success(t *testing.T) {
 	app := fiber.New()
 	mockProcessor := new(MockAuthProcessor)
-	handler := NewAuthHandlers(mockProcessor, "secret")
+	handler := NewAuthHandlers(mockProcessor, jwtkeys.NewHMACKeySet("secret"))
 
 	mockProcessor.On("DummyLogin", "employee").Return("user123", nil)
 
@@ -63,7 +100,7 @@ func TestAuthHandlers_DummyLoginHandler_Success(t *testing.T) {
 func TestAuthHandlers_DummyLoginHandler_InvalidRole(t *testing.T) {
 	app := fiber.New()
 	mockProcessor := new(MockAuthProcessor)
-	handler := NewAuthHandlers(mockProcessor, "secret")
+	handler := NewAuthHandlers(mockProcessor, jwtkeys.NewHMACKeySet("secret"))
 
 	mockProcessor.On("DummyLogin", "invalid").Return(
 		"", errors.New("invalid role"))
@@ -82,10 +119,11 @@ func TestAuthHandlers_DummyLoginHandler_InvalidRole(t *testing.T) {
 func TestAuthHandlers_RegisterHandler_Success(t *testing.T) {
 	app := fiber.New()
 	mockProcessor := new(MockAuthProcessor)
-	handler := NewAuthHandlers(mockProcessor, "secret")
+	handler := NewAuthHandlers(mockProcessor, jwtkeys.NewHMACKeySet("secret"))
 
 	mockProcessor.On("Register", "test@example.com", "password", "employee").Return(
 		"user123", nil)
+	mockProcessor.On("StartSession", "user123", mock.AnythingOfType("string")).Return("refresh123", nil)
 
 	app.Post("/register", handler.RegisterHandler())
 
@@ -102,7 +140,7 @@ func TestAuthHandlers_RegisterHandler_Success(t *testing.T) {
 func TestAuthHandlers_RegisterHandler_InvalidRole(t *testing.T) {
 	app := fiber.New()
 	mockProcessor := new(MockAuthProcessor)
-	handler := NewAuthHandlers(mockProcessor, "secret")
+	handler := NewAuthHandlers(mockProcessor, jwtkeys.NewHMACKeySet("secret"))
 
 	mockProcessor.On("Register", "test@example.com", "password", "invalid").Return(
 		"", errors.New("invalid role"))
@@ -119,13 +157,87 @@ func TestAuthHandlers_RegisterHandler_InvalidRole(t *testing.T) {
 	mockProcessor.AssertExpectations(t)
 }
 
+func TestAuthHandlers_RegisterHandler_Disabled(t *testing.T) {
+	app := fiber.New()
+	mockProcessor := new(MockAuthProcessor)
+	handler := NewAuthHandlers(mockProcessor, jwtkeys.NewHMACKeySet("secret"))
+
+	mockProcessor.On("Register", "test@example.com", "password", "employee").Return(
+		"", errors.New("registration is disabled"))
+
+	app.Post("/register", handler.RegisterHandler())
+
+	req := httptest.NewRequest("POST", "/register",
+		bytes.NewBufferString(`{"email":"test@example.com","password":"password","role":"employee"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	mockProcessor.AssertExpectations(t)
+}
+
+func TestAuthHandlers_RegisterHandler_WeakPassword(t *testing.T) {
+	app := fiber.New()
+	mockProcessor := new(MockAuthProcessor)
+	handler := NewAuthHandlers(mockProcessor, jwtkeys.NewHMACKeySet("secret"))
+
+	mockProcessor.On("Register", "test@example.com", "123", "employee").Return("", domain.ErrWeakPassword)
+
+	app.Post("/register", handler.RegisterHandler())
+
+	status := postJSON(t, app, "/register", `{"email":"test@example.com","password":"123","role":"employee"}`)
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	mockProcessor.AssertExpectations(t)
+}
+
+func TestAuthHandlers_LoginHandler_DisabledUser(t *testing.T) {
+	app := fiber.New()
+	mockProcessor := new(MockAuthProcessor)
+	handler := NewAuthHandlers(mockProcessor, jwtkeys.NewHMACKeySet("secret"))
+
+	mockProcessor.On("Login", "test@example.com", "password").Return("", "", domain.ErrUserDisabled)
+
+	app.Post("/login", handler.LoginHandler())
+
+	req := httptest.NewRequest("POST", "/login",
+		bytes.NewBufferString(`{"email":"test@example.com","password":"password"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	mockProcessor.AssertExpectations(t)
+}
+
+func TestAuthHandlers_LoginHandler_Locked(t *testing.T) {
+	app := fiber.New()
+	mockProcessor := new(MockAuthProcessor)
+	handler := NewAuthHandlers(mockProcessor, jwtkeys.NewHMACKeySet("secret"))
+
+	mockProcessor.On("Login", "test@example.com", "password").Return("", "",
+		&domain.LockoutError{Err: domain.ErrAccountLocked, RetryAfter: 90*time.Second + time.Millisecond})
+
+	app.Post("/login", handler.LoginHandler())
+
+	req := httptest.NewRequest("POST", "/login",
+		bytes.NewBufferString(`{"email":"test@example.com","password":"password"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "91", resp.Header.Get(fiber.HeaderRetryAfter))
+	mockProcessor.AssertExpectations(t)
+}
+
 func TestAuthHandlers_RegisterHandler_EmailExists(t *testing.T) {
 	app := fiber.New()
 	mockProcessor := new(MockAuthProcessor)
-	handler := NewAuthHandlers(mockProcessor, "secret")
+	handler := NewAuthHandlers(mockProcessor, jwtkeys.NewHMACKeySet("secret"))
 
 	mockProcessor.On("Register", "exists@example.com", "password", "employee").Return(
-		"", errors.New("email already exists"))
+		"", domain.ErrEmailAlreadyExists)
 
 	app.Post("/register", handler.RegisterHandler())
 
@@ -142,10 +254,11 @@ func TestAuthHandlers_RegisterHandler_EmailExists(t *testing.T) {
 func TestAuthHandlers_LoginHandler_Success(t *testing.T) {
 	app := fiber.New()
 	mockProcessor := new(MockAuthProcessor)
-	handler := NewAuthHandlers(mockProcessor, "secret")
+	handler := NewAuthHandlers(mockProcessor, jwtkeys.NewHMACKeySet("secret"))
 
 	mockProcessor.On("Login", "test@example.com", "password").Return(
 		"user123", "employee", nil)
+	mockProcessor.On("StartSession", "user123", mock.AnythingOfType("string")).Return("refresh123", nil)
 
 	app.Post("/login", handler.LoginHandler())
 
@@ -156,13 +269,24 @@ func TestAuthHandlers_LoginHandler_Success(t *testing.T) {
 	resp, err := app.Test(req)
 	assert.NoError(t, err)
 	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body models.TokenResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "refresh123", body.RefreshToken)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(body.Token, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte("secret"), nil
+	})
+	assert.NoError(t, err)
+	mockProcessor.AssertCalled(t, "StartSession", "user123", claims["jti"])
 	mockProcessor.AssertExpectations(t)
 }
 
 func TestAuthHandlers_LoginHandler_InvalidCredentials(t *testing.T) {
 	app := fiber.New()
 	mockProcessor := new(MockAuthProcessor)
-	handler := NewAuthHandlers(mockProcessor, "secret")
+	handler := NewAuthHandlers(mockProcessor, jwtkeys.NewHMACKeySet("secret"))
 
 	mockProcessor.On("Login", "test@example.com", "wrong").Return(
 		"", "", errors.New("invalid email or password"))
@@ -180,8 +304,8 @@ func TestAuthHandlers_LoginHandler_InvalidCredentials(t *testing.T) {
 }
 
 func TestGenerateToken(t *testing.T) {
-	handler := NewAuthHandlers(nil, "secret")
-	token, err := handler.GenerateToken("user123", "employee")
+	handler := NewAuthHandlers(nil, jwtkeys.NewHMACKeySet("secret"))
+	token, err := handler.GenerateToken("user123", "employee", "jti123")
 	assert.NoError(t, err)
 	assert.NotEmpty(t, token)
 
@@ -195,5 +319,106 @@ func TestGenerateToken(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, "user123", claims["userId"])
 	assert.Equal(t, "employee", claims["role"])
+	assert.Equal(t, "jti123", claims["jti"])
 	assert.InDelta(t, time.Now().Add(time.Hour*1).Unix(), claims["exp"].(float64), 10)
 }
+
+func TestAuthHandlers_RefreshTokenHandler(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		app := fiber.New()
+		mockProcessor := new(MockAuthProcessor)
+		handler := NewAuthHandlers(mockProcessor, jwtkeys.NewHMACKeySet("secret"))
+		mockProcessor.On("RefreshSession", "old-refresh", mock.AnythingOfType("string")).
+			Return("user123", "moderator", "new-refresh", nil)
+
+		app.Post("/token/refresh", handler.RefreshTokenHandler())
+
+		req := httptest.NewRequest("POST", "/token/refresh", bytes.NewBufferString(`{"refreshToken":"old-refresh"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var body models.TokenResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, "new-refresh", body.RefreshToken)
+
+		claims := jwt.MapClaims{}
+		_, err = jwt.ParseWithClaims(body.Token, claims, func(token *jwt.Token) (interface{}, error) {
+			return []byte("secret"), nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "moderator", claims["role"])
+		mockProcessor.AssertCalled(t, "RefreshSession", "old-refresh", claims["jti"])
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		app := fiber.New()
+		mockProcessor := new(MockAuthProcessor)
+		handler := NewAuthHandlers(mockProcessor, jwtkeys.NewHMACKeySet("secret"))
+		mockProcessor.On("RefreshSession", "stale", mock.AnythingOfType("string")).
+			Return("", "", "", errors.New("invalid refresh token"))
+
+		app.Post("/token/refresh", handler.RefreshTokenHandler())
+
+		req := httptest.NewRequest("POST", "/token/refresh", bytes.NewBufferString(`{"refreshToken":"stale"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+func TestAuthHandlers_LogoutHandler(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	withClaims := func(claims jwt.MapClaims) fiber.Handler {
+		return func(c *fiber.Ctx) error {
+			c.Locals("claims", claims)
+			return c.Next()
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		app := fiber.New()
+		mockProcessor := new(MockAuthProcessor)
+		handler := NewAuthHandlers(mockProcessor, jwtkeys.NewHMACKeySet("secret"))
+		mockProcessor.On("Logout", "jti123", expiresAt).Return(nil)
+
+		app.Post("/logout", withClaims(jwt.MapClaims{"jti": "jti123", "exp": float64(expiresAt.Unix())}),
+			handler.LogoutHandler())
+
+		resp, err := app.Test(httptest.NewRequest("POST", "/logout", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+		mockProcessor.AssertExpectations(t)
+	})
+
+	t.Run("token without jti", func(t *testing.T) {
+		app := fiber.New()
+		mockProcessor := new(MockAuthProcessor)
+		handler := NewAuthHandlers(mockProcessor, jwtkeys.NewHMACKeySet("secret"))
+
+		app.Post("/logout", withClaims(jwt.MapClaims{"exp": float64(expiresAt.Unix())}), handler.LogoutHandler())
+
+		resp, err := app.Test(httptest.NewRequest("POST", "/logout", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+		mockProcessor.AssertNotCalled(t, "Logout", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthHandlers_JWKSHandler(t *testing.T) {
+	app := fiber.New()
+	handler := NewAuthHandlers(nil, jwtkeys.NewHMACKeySet("secret"))
+	app.Get("/.well-known/jwks.json", handler.JWKSHandler())
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/.well-known/jwks.json", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body jwtkeys.JWKS
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Empty(t, body.Keys)
+}
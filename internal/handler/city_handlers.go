@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/handler/models"
+	"pvz-service/internal/service"
+)
+
+type CityHandlers struct {
+	cityService service.CityService
+}
+
+func NewCityHandlers(cityService service.CityService) *CityHandlers {
+	return &CityHandlers{cityService: cityService}
+}
+
+func (h *CityHandlers) ListCitiesHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		includeInactive := c.QueryBool("includeInactive", false)
+
+		cities, err := h.cityService.ListCities(c.UserContext(), includeInactive)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.JSON(cities)
+	}
+}
+
+func (h *CityHandlers) CreateCityHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var body struct {
+			Name string `json:"name"`
+		}
+		if err := c.BodyParser(&body); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid request"})
+		}
+
+		city, err := h.cityService.CreateCity(c.UserContext(), body.Name)
+		if err != nil {
+			return c.Status(cityErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(city)
+	}
+}
+
+func (h *CityHandlers) UpdateCityHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		cityID := c.Params("cityId")
+		if _, err := uuid.Parse(cityID); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid cityId format"})
+		}
+
+		var body struct {
+			Name     *string `json:"name"`
+			IsActive *bool   `json:"isActive"`
+		}
+		if err := c.BodyParser(&body); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid request"})
+		}
+
+		if body.Name == nil && body.IsActive == nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Nothing to update"})
+		}
+
+		city, err := h.cityService.UpdateCity(c.UserContext(), cityID, body.Name, body.IsActive)
+		if err != nil {
+			return c.Status(cityErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.JSON(city)
+	}
+}
+
+func (h *CityHandlers) DeleteCityHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		cityID := c.Params("cityId")
+		if _, err := uuid.Parse(cityID); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid cityId format"})
+		}
+
+		if err := h.cityService.DeactivateCity(c.UserContext(), cityID); err != nil {
+			return c.Status(cityErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func cityErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrCityNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, domain.ErrCityAlreadyExists):
+		return fiber.StatusConflict
+	case err.Error() == "city name is required":
+		return fiber.StatusBadRequest
+	}
+	return fiber.StatusInternalServerError
+}
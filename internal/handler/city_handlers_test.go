@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/domain"
+)
+
+type MockCityService struct {
+	mock.Mock
+}
+
+func (m *MockCityService) CreateCity(ctx context.Context, name string) (domain.City, error) {
+	args := m.Called(name)
+	return args.Get(0).(domain.City), args.Error(1)
+}
+
+func (m *MockCityService) ListCities(ctx context.Context, includeInactive bool) ([]domain.City, error) {
+	args := m.Called(includeInactive)
+	return args.Get(0).([]domain.City), args.Error(1)
+}
+
+func (m *MockCityService) UpdateCity(ctx context.Context, id string, name *string, isActive *bool) (domain.City, error) {
+	args := m.Called(id, name, isActive)
+	return args.Get(0).(domain.City), args.Error(1)
+}
+
+func (m *MockCityService) DeactivateCity(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func TestCityHandlers_ListCitiesHandler(t *testing.T) {
+	app := fiber.New()
+	mockService := new(MockCityService)
+	handler := NewCityHandlers(mockService)
+
+	mockService.On("ListCities", true).Return([]domain.City{{Name: "Москва", IsActive: true}}, nil)
+
+	app.Get("/cities", handler.ListCitiesHandler())
+
+	req := httptest.NewRequest("GET", "/cities?includeInactive=true", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	mockService.AssertExpectations(t)
+}
+
+func TestCityHandlers_CreateCityHandler(t *testing.T) {
+	app := fiber.New()
+	mockService := new(MockCityService)
+	handler := NewCityHandlers(mockService)
+
+	app.Post("/cities", handler.CreateCityHandler())
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("CreateCity", "Новосибирск").
+			Return(domain.City{ID: uuid.NewString(), Name: "Новосибирск", IsActive: true}, nil)
+
+		req := httptest.NewRequest("POST", "/cities", bytes.NewBufferString(`{"name":"Новосибирск"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		mockService.On("CreateCity", "Москва").Return(domain.City{}, domain.ErrCityAlreadyExists)
+
+		req := httptest.NewRequest("POST", "/cities", bytes.NewBufferString(`{"name":"Москва"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	})
+}
+
+func TestCityHandlers_UpdateCityHandler(t *testing.T) {
+	app := fiber.New()
+	mockService := new(MockCityService)
+	handler := NewCityHandlers(mockService)
+
+	app.Patch("/cities/:cityId", handler.UpdateCityHandler())
+
+	t.Run("success", func(t *testing.T) {
+		cityID := uuid.NewString()
+		mockService.On("UpdateCity", cityID, (*string)(nil), mock.AnythingOfType("*bool")).
+			Return(domain.City{ID: cityID, Name: "Казань"}, nil)
+
+		req := httptest.NewRequest("PATCH", "/cities/"+cityID, bytes.NewBufferString(`{"isActive":false}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("empty body", func(t *testing.T) {
+		req := httptest.NewRequest("PATCH", "/cities/"+uuid.NewString(), bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestCityHandlers_DeleteCityHandler(t *testing.T) {
+	app := fiber.New()
+	mockService := new(MockCityService)
+	handler := NewCityHandlers(mockService)
+
+	app.Delete("/cities/:cityId", handler.DeleteCityHandler())
+
+	t.Run("success", func(t *testing.T) {
+		cityID := uuid.NewString()
+		mockService.On("DeactivateCity", cityID).Return(nil)
+
+		resp, err := app.Test(httptest.NewRequest("DELETE", "/cities/"+cityID, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		cityID := uuid.NewString()
+		mockService.On("DeactivateCity", cityID).Return(domain.ErrCityNotFound)
+
+		resp, err := app.Test(httptest.NewRequest("DELETE", "/cities/"+cityID, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest("DELETE", "/cities/invalid", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+
+	"pvz-service/internal/health"
+)
+
+type ReadinessChecker interface {
+	Ready(ctx context.Context) health.Report
+}
+
+type HealthHandlers struct {
+	checker ReadinessChecker
+}
+
+func NewHealthHandlers(checker ReadinessChecker) *HealthHandlers {
+	return &HealthHandlers{checker: checker}
+}
+
+// LivezHandler only reports that the process serves HTTP; dependencies are
+// left to ReadyzHandler so that a database outage does not restart the pod.
+func (h *HealthHandlers) LivezHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return c.JSON(health.Result{Status: health.StatusOK})
+	}
+}
+
+// HealthHandler is the legacy /health check. It answers like LivezHandler but
+// keeps the plain "OK" body existing monitors parse.
+func (h *HealthHandlers) HealthHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	}
+}
+
+func (h *HealthHandlers) ReadyzHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		report := h.checker.Ready(c.UserContext())
+		if !report.OK() {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(report)
+		}
+		return c.JSON(report)
+	}
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/health"
+)
+
+func TestHealthHandlers_HealthHandler(t *testing.T) {
+	app := fiber.New()
+	handler := NewHealthHandlers(health.NewChecker(time.Second))
+	app.Get("/health", handler.HealthHandler())
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/health", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", string(body))
+}
+
+func TestHealthHandlers_LivezHandler(t *testing.T) {
+	app := fiber.New()
+	handler := NewHealthHandlers(health.NewChecker(time.Second))
+	app.Get("/livez", handler.LivezHandler())
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/livez", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func TestHealthHandlers_ReadyzHandler(t *testing.T) {
+	checker := health.NewChecker(time.Second)
+	dbErr := errors.New("connection refused")
+	checker.Add("database", func(ctx context.Context) error { return dbErr })
+
+	app := fiber.New()
+	app.Get("/readyz", NewHealthHandlers(checker).ReadyzHandler())
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/readyz", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+
+	var report health.Report
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	assert.Equal(t, health.StatusFail, report.Status)
+	assert.Equal(t, "connection refused", report.Checks["database"].Error)
+
+	dbErr = nil
+	resp, err = app.Test(httptest.NewRequest("GET", "/readyz", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"pvz-service/internal/domain"
+)
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Role      string     `json:"role"`
+	PVZIDs    []string   `json:"pvzIds"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// CreatedAPIKey is the only response that contains the plain key.
+type CreatedAPIKey struct {
+	domain.APIKey
+	Key string `json:"key"`
+}
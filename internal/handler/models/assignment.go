@@ -0,0 +1,6 @@
+package models
+
+type UserPVZAssignments struct {
+	UserID string   `json:"userId"`
+	PVZIDs []string `json:"pvzIds"`
+}
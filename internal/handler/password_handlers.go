@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/handler/models"
+	"pvz-service/internal/service"
+)
+
+type PasswordHandlers struct {
+	passwordService service.PasswordService
+}
+
+func NewPasswordHandlers(passwordService service.PasswordService) *PasswordHandlers {
+	return &PasswordHandlers{passwordService: passwordService}
+}
+
+func (h *PasswordHandlers) ChangePasswordHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var body models.ChangePasswordRequest
+		if err := c.BodyParser(&body); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid request body format"})
+		}
+
+		userID, ok := userIDFromClaims(c)
+		if !ok {
+			return c.Status(fiber.StatusForbidden).JSON(models.ErrorResponse{
+				Message: "Password change requires a user token",
+			})
+		}
+
+		err := h.passwordService.ChangePassword(c.UserContext(), userID, body.CurrentPassword, body.NewPassword)
+		if err != nil {
+			return c.Status(passwordErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+// RequestPasswordResetHandler answers 202 whether or not the email is registered.
+func (h *PasswordHandlers) RequestPasswordResetHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var body models.PasswordResetRequest
+		if err := c.BodyParser(&body); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid request body format"})
+		}
+
+		if err := h.passwordService.RequestPasswordReset(c.UserContext(), body.Email); err != nil {
+			return c.Status(passwordErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.SendStatus(fiber.StatusAccepted)
+	}
+}
+
+func (h *PasswordHandlers) ResetPasswordHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var body models.PasswordResetConfirmRequest
+		if err := c.BodyParser(&body); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid request body format"})
+		}
+
+		if err := h.passwordService.ResetPassword(c.UserContext(), body.Token, body.NewPassword); err != nil {
+			return c.Status(passwordErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func passwordErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidCurrentPassword):
+		return fiber.StatusForbidden
+	case errors.Is(err, domain.ErrWeakPassword),
+		errors.Is(err, domain.ErrInvalidEmail),
+		errors.Is(err, domain.ErrInvalidResetToken):
+		return fiber.StatusBadRequest
+	}
+	return fiber.StatusInternalServerError
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/domain"
+)
+
+type MockPasswordService struct {
+	mock.Mock
+}
+
+func (m *MockPasswordService) ChangePassword(ctx context.Context, userID, currentPassword, newPassword string) error {
+	args := m.Called(userID, currentPassword, newPassword)
+	return args.Error(0)
+}
+
+func (m *MockPasswordService) RequestPasswordReset(ctx context.Context, email string) error {
+	args := m.Called(email)
+	return args.Error(0)
+}
+
+func (m *MockPasswordService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	args := m.Called(token, newPassword)
+	return args.Error(0)
+}
+
+func postJSON(t *testing.T, app *fiber.App, path, body string) int {
+	t.Helper()
+	req := httptest.NewRequest("POST", path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	return resp.StatusCode
+}
+
+func TestPasswordHandlers_ChangePasswordHandler(t *testing.T) {
+	app := fiber.New()
+	mockService := new(MockPasswordService)
+	handler := NewPasswordHandlers(mockService)
+	userID := uuid.NewString()
+	app.Post("/users/me/password", withActor(userID, "employee"), handler.ChangePasswordHandler())
+	app.Post("/api-key/password", withActor("", "employee"), handler.ChangePasswordHandler())
+
+	mockService.On("ChangePassword", userID, "old", "new-password").Return(nil)
+	mockService.On("ChangePassword", userID, "wrong", "new-password").Return(domain.ErrInvalidCurrentPassword)
+	mockService.On("ChangePassword", userID, "old", "short").
+		Return(fmt.Errorf("%w: must be at least 8 characters", domain.ErrWeakPassword))
+
+	assert.Equal(t, fiber.StatusNoContent,
+		postJSON(t, app, "/users/me/password", `{"currentPassword":"old","newPassword":"new-password"}`))
+	assert.Equal(t, fiber.StatusForbidden,
+		postJSON(t, app, "/users/me/password", `{"currentPassword":"wrong","newPassword":"new-password"}`))
+	assert.Equal(t, fiber.StatusBadRequest,
+		postJSON(t, app, "/users/me/password", `{"currentPassword":"old","newPassword":"short"}`))
+	assert.Equal(t, fiber.StatusForbidden,
+		postJSON(t, app, "/api-key/password", `{"currentPassword":"old","newPassword":"new-password"}`))
+	mockService.AssertExpectations(t)
+}
+
+func TestPasswordHandlers_RequestPasswordResetHandler(t *testing.T) {
+	app := fiber.New()
+	mockService := new(MockPasswordService)
+	handler := NewPasswordHandlers(mockService)
+	app.Post("/password/reset/request", handler.RequestPasswordResetHandler())
+
+	mockService.On("RequestPasswordReset", "user@example.com").Return(nil)
+	mockService.On("RequestPasswordReset", "nope").Return(domain.ErrInvalidEmail)
+
+	assert.Equal(t, fiber.StatusAccepted,
+		postJSON(t, app, "/password/reset/request", `{"email":"user@example.com"}`))
+	assert.Equal(t, fiber.StatusBadRequest, postJSON(t, app, "/password/reset/request", `{"email":"nope"}`))
+}
+
+func TestPasswordHandlers_ResetPasswordHandler(t *testing.T) {
+	app := fiber.New()
+	mockService := new(MockPasswordService)
+	handler := NewPasswordHandlers(mockService)
+	app.Post("/password/reset", handler.ResetPasswordHandler())
+
+	mockService.On("ResetPassword", "token1", "new-password").Return(nil)
+	mockService.On("ResetPassword", "used", "new-password").Return(domain.ErrInvalidResetToken)
+
+	assert.Equal(t, fiber.StatusNoContent,
+		postJSON(t, app, "/password/reset", `{"token":"token1","newPassword":"new-password"}`))
+	assert.Equal(t, fiber.StatusBadRequest,
+		postJSON(t, app, "/password/reset", `{"token":"used","newPassword":"new-password"}`))
+}
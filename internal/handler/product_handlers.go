@@ -1,17 +1,19 @@
 package handler
 
 import (
+	"context"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"pvz-service/internal/handler/models"
 	"pvz-service/internal/prometheus"
+	"strings"
 
 	"pvz-service/internal/domain"
 )
 
 type ProductProcessor interface {
-	AddProduct(pvzID, productType string) (domain.Product, error)
-	DeleteLastProduct(pvzID string) error
+	AddProduct(ctx context.Context, pvzID, productType string, attributes map[string]string) (domain.Product, error)
+	DeleteLastProduct(ctx context.Context, pvzID string) error
 }
 
 type ProductHandlers struct {
@@ -22,17 +24,12 @@ func NewProductHandlers(productProcessor ProductProcessor) *ProductHandlers {
 	return &ProductHandlers{productProcessor: productProcessor}
 }
 
-var allowedProductTypes = map[string]bool{
-	"электроника": true,
-	"одежда":      true,
-	"обувь":       true,
-}
-
 func (h *ProductHandlers) AddProductHandler() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var body struct {
-			Type  string `json:"type"`
-			PvzId string `json:"pvzId"`
+			Type       string            `json:"type"`
+			PvzId      string            `json:"pvzId"`
+			Attributes map[string]string `json:"attributes"`
 		}
 
 		if err := c.BodyParser(&body); err != nil {
@@ -43,13 +40,13 @@ func (h *ProductHandlers) AddProductHandler() fiber.Handler {
 			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid pvzId format"})
 		}
 
-		if !allowedProductTypes[body.Type] {
+		if strings.TrimSpace(body.Type) == "" {
 			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid product type"})
 		}
 
-		product, err := h.productProcessor.AddProduct(body.PvzId, body.Type)
+		product, err := h.productProcessor.AddProduct(c.UserContext(), body.PvzId, body.Type, body.Attributes)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: err.Error()})
+			return c.Status(receptionErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
 		}
 
 		prometheus.ProductsAdded.Inc()
@@ -65,8 +62,8 @@ func (h *ProductHandlers) DeleteLastProductHandler() fiber.Handler {
 			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid pvzId format"})
 		}
 
-		if err := h.productProcessor.DeleteLastProduct(pvzId); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: err.Error()})
+		if err := h.productProcessor.DeleteLastProduct(c.UserContext(), pvzId); err != nil {
+			return c.Status(receptionErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
 		}
 
 		return c.SendStatus(fiber.StatusOK)
@@ -2,27 +2,36 @@ package handler
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
 	"pvz-service/internal/domain"
+	"pvz-service/internal/handler/models"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/service"
 )
 
 type MockProductProcessor struct {
 	mock.Mock
 }
 
-func (m *MockProductProcessor) AddProduct(pvzID, productType string) (domain.Product, error) {
-	args := m.Called(pvzID, productType)
+func (m *MockProductProcessor) AddProduct(
+	ctx context.Context, pvzID, productType string, attributes map[string]string) (domain.Product, error) {
+	args := m.Called(pvzID, productType, attributes)
 	return args.Get(0).(domain.Product), args.Error(1)
 }
 
-func (m *MockProductProcessor) DeleteLastProduct(pvzID string) error {
+func (m *MockProductProcessor) DeleteLastProduct(ctx context.Context, pvzID string) error {
 	args := m.Called(pvzID)
 	return args.Error(0)
 }
@@ -39,12 +48,13 @@ func TestProductHandlers_AddProductHandler_Success(t *testing.T) {
 		ReceptionId: testUUID,
 	}
 
-	mockProcessor.On("AddProduct", testUUID, "электроника").Return(expectedProduct, nil)
+	mockProcessor.On("AddProduct", testUUID, "электроника", map[string]string{"serial_number": "SN-1"}).
+		Return(expectedProduct, nil)
 
 	app.Post("/products", handler.AddProductHandler())
 
 	req := httptest.NewRequest("POST", "/products", bytes.NewBufferString(
-		`{"type":"электроника","pvzId":"`+testUUID+`"}`))
+		`{"type":"электроника","pvzId":"`+testUUID+`","attributes":{"serial_number":"SN-1"}}`))
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := app.Test(req)
@@ -68,6 +78,21 @@ func TestProductHandlers_AddProductHandler_InvalidUUID(t *testing.T) {
 	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
 }
 
+func TestProductHandlers_AddProductHandler_EmptyType(t *testing.T) {
+	app := fiber.New()
+	handler := NewProductHandlers(nil)
+
+	app.Post("/products", handler.AddProductHandler())
+
+	req := httptest.NewRequest("POST", "/products", bytes.NewBufferString(
+		`{"type":"","pvzId":"`+uuid.NewString()+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
 func TestProductHandlers_DeleteLastProductHandler_Success(t *testing.T) {
 	app := fiber.New()
 	mockProcessor := new(MockProductProcessor)
@@ -84,3 +109,52 @@ func TestProductHandlers_DeleteLastProductHandler_Success(t *testing.T) {
 	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
 	mockProcessor.AssertExpectations(t)
 }
+
+// Runs the real service and transaction manager so that begin and commit
+// failures go through the same path as in production.
+func TestProductHandlers_DeleteLastProductHandler_TransactionFailure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	app := fiber.New()
+	processor := service.NewProductService(repository.NewTxManager(db), repository.NewProductTypeRepository(db), nil)
+	app.Post("/pvz/:pvzId/delete_last_product", NewProductHandlers(processor).DeleteLastProductHandler())
+	pvzID := uuid.NewString()
+	driverErr := errors.New("pq: connection reset by peer")
+
+	request := func() (int, string) {
+		resp, err := app.Test(httptest.NewRequest("POST", "/pvz/"+pvzID+"/delete_last_product", nil))
+		assert.NoError(t, err)
+		var body models.ErrorResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return resp.StatusCode, body.Message
+	}
+
+	t.Run("begin", func(t *testing.T) {
+		mock.ExpectBegin().WillReturnError(driverErr)
+
+		status, message := request()
+		assert.Equal(t, fiber.StatusInternalServerError, status)
+		assert.Equal(t, "failed to delete product", message)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		receptionID, productID := uuid.NewString(), uuid.NewString()
+		mock.ExpectBegin()
+		mock.ExpectQuery("FOR UPDATE").WithArgs(pvzID).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "created_at", "pvz_id", "status", "closed_at"}).
+				AddRow(receptionID, time.Now(), pvzID, "in_progress", nil))
+		mock.ExpectQuery("FROM products WHERE reception_id").WithArgs(receptionID).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "created_at", "type", "reception_id", "attributes"}).
+				AddRow(productID, time.Now(), "обувь", receptionID, []byte("{}")))
+		mock.ExpectExec("DELETE FROM products").WithArgs(productID).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit().WillReturnError(driverErr)
+
+		status, message := request()
+		assert.Equal(t, fiber.StatusInternalServerError, status)
+		assert.Equal(t, "failed to delete product", message)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/handler/models"
+	"pvz-service/internal/service"
+)
+
+type ProductTypeHandlers struct {
+	productTypeService service.ProductTypeService
+}
+
+func NewProductTypeHandlers(productTypeService service.ProductTypeService) *ProductTypeHandlers {
+	return &ProductTypeHandlers{productTypeService: productTypeService}
+}
+
+func (h *ProductTypeHandlers) ListProductTypesHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		productTypes, err := h.productTypeService.ListProductTypes(c.UserContext(), c.QueryBool("includeInactive", false))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.JSON(productTypes)
+	}
+}
+
+func (h *ProductTypeHandlers) CreateProductTypeHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var body struct {
+			Code               string   `json:"code"`
+			DisplayName        string   `json:"displayName"`
+			RequiredAttributes []string `json:"requiredAttributes"`
+		}
+		if err := c.BodyParser(&body); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid request"})
+		}
+
+		productType, err := h.productTypeService.CreateProductType(
+			c.UserContext(), body.Code, body.DisplayName, body.RequiredAttributes)
+		if err != nil {
+			return c.Status(productTypeErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(productType)
+	}
+}
+
+func (h *ProductTypeHandlers) UpdateProductTypeHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		code, err := url.PathUnescape(c.Params("code"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid product type code"})
+		}
+
+		var body struct {
+			DisplayName        *string  `json:"displayName"`
+			RequiredAttributes []string `json:"requiredAttributes"`
+			IsActive           *bool    `json:"isActive"`
+		}
+		if err := c.BodyParser(&body); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid request"})
+		}
+
+		if body.DisplayName == nil && body.RequiredAttributes == nil && body.IsActive == nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Nothing to update"})
+		}
+
+		productType, err := h.productTypeService.UpdateProductType(c.UserContext(),
+			code, body.DisplayName, body.RequiredAttributes, body.IsActive)
+		if err != nil {
+			return c.Status(productTypeErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.JSON(productType)
+	}
+}
+
+func (h *ProductTypeHandlers) DeleteProductTypeHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		code, err := url.PathUnescape(c.Params("code"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid product type code"})
+		}
+
+		if err := h.productTypeService.DeactivateProductType(c.UserContext(), code); err != nil {
+			return c.Status(productTypeErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func productTypeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrProductTypeNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, domain.ErrProductTypeAlreadyExists):
+		return fiber.StatusConflict
+	}
+
+	switch err.Error() {
+	case "product type code is required", "display name is required", "attribute name must not be empty":
+		return fiber.StatusBadRequest
+	}
+	return fiber.StatusInternalServerError
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/domain"
+)
+
+type MockProductTypeService struct {
+	mock.Mock
+}
+
+func (m *MockProductTypeService) CreateProductType(
+	ctx context.Context, code, displayName string, requiredAttributes []string) (domain.ProductType, error) {
+	args := m.Called(code, displayName, requiredAttributes)
+	return args.Get(0).(domain.ProductType), args.Error(1)
+}
+
+func (m *MockProductTypeService) ListProductTypes(ctx context.Context, includeInactive bool) ([]domain.ProductType, error) {
+	args := m.Called(includeInactive)
+	return args.Get(0).([]domain.ProductType), args.Error(1)
+}
+
+func (m *MockProductTypeService) UpdateProductType(
+	ctx context.Context, code string, displayName *string, requiredAttributes []string, isActive *bool) (domain.ProductType, error) {
+	args := m.Called(code, displayName, requiredAttributes, isActive)
+	return args.Get(0).(domain.ProductType), args.Error(1)
+}
+
+func (m *MockProductTypeService) DeactivateProductType(ctx context.Context, code string) error {
+	args := m.Called(code)
+	return args.Error(0)
+}
+
+func TestProductTypeHandlers_CreateProductTypeHandler(t *testing.T) {
+	app := fiber.New()
+	mockService := new(MockProductTypeService)
+	handler := NewProductTypeHandlers(mockService)
+
+	app.Post("/product_types", handler.CreateProductTypeHandler())
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("CreateProductType", "обувь", "Обувь", []string{"size"}).
+			Return(domain.ProductType{Code: "обувь", DisplayName: "Обувь", RequiredAttributes: []string{"size"}}, nil)
+
+		req := httptest.NewRequest("POST", "/product_types", bytes.NewBufferString(
+			`{"code":"обувь","displayName":"Обувь","requiredAttributes":["size"]}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("already exists", func(t *testing.T) {
+		mockService.On("CreateProductType", "одежда", "Одежда", []string(nil)).
+			Return(domain.ProductType{}, domain.ErrProductTypeAlreadyExists)
+
+		req := httptest.NewRequest("POST", "/product_types", bytes.NewBufferString(
+			`{"code":"одежда","displayName":"Одежда"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	})
+}
+
+func TestProductTypeHandlers_UpdateProductTypeHandler(t *testing.T) {
+	app := fiber.New()
+	mockService := new(MockProductTypeService)
+	handler := NewProductTypeHandlers(mockService)
+
+	app.Patch("/product_types/:code", handler.UpdateProductTypeHandler())
+
+	t.Run("escaped code", func(t *testing.T) {
+		mockService.On("UpdateProductType", "электроника", (*string)(nil), []string{"serial_number"}, (*bool)(nil)).
+			Return(domain.ProductType{Code: "электроника"}, nil)
+
+		req := httptest.NewRequest("PATCH", "/product_types/"+url.PathEscape("электроника"),
+			bytes.NewBufferString(`{"requiredAttributes":["serial_number"]}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockService.On("UpdateProductType", "мебель", mock.Anything, []string(nil), (*bool)(nil)).
+			Return(domain.ProductType{}, domain.ErrProductTypeNotFound)
+
+		req := httptest.NewRequest("PATCH", "/product_types/"+url.PathEscape("мебель"),
+			bytes.NewBufferString(`{"displayName":"Мебель"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestProductTypeHandlers_DeleteProductTypeHandler(t *testing.T) {
+	app := fiber.New()
+	mockService := new(MockProductTypeService)
+	handler := NewProductTypeHandlers(mockService)
+
+	mockService.On("DeactivateProductType", "обувь").Return(nil)
+
+	app.Delete("/product_types/:code", handler.DeleteProductTypeHandler())
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/product_types/"+url.PathEscape("обувь"), nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+	mockService.AssertExpectations(t)
+}
@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"pvz-service/internal/handler/models"
 	"pvz-service/internal/prometheus"
+	"pvz-service/internal/repository"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -27,9 +30,13 @@ func (h *PVZHandlers) CreatePVZHandler() fiber.Handler {
 			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid request"})
 		}
 
-		pvz, err := h.pvzService.CreatePVZ(body.City)
+		pvz, err := h.pvzService.CreatePVZ(c.UserContext(), body.City)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: err.Error()})
+			status := fiber.StatusBadRequest
+			if errors.Is(err, domain.ErrInternal) {
+				status = fiber.StatusInternalServerError
+			}
+			return c.Status(status).JSON(models.ErrorResponse{Message: err.Error()})
 		}
 
 		prometheus.PickupPointsCreated.Inc()
@@ -41,6 +48,10 @@ func (h *PVZHandlers) CreatePVZHandler() fiber.Handler {
 
 func (h *PVZHandlers) GetPVZListHandler() fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		if c.Context().QueryArgs().Has("cursor") {
+			return h.getPVZListByCursor(c)
+		}
+
 		pageStr := c.Query("page")
 		if pageStr == "" {
 			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
@@ -69,31 +80,169 @@ func (h *PVZHandlers) GetPVZListHandler() fiber.Handler {
 			})
 		}
 
-		startDate := c.Query("startDate")
-		if startDate != "" {
-			if _, err := time.Parse(time.RFC3339, startDate); err != nil {
-				return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
-					Message: "invalid startDate format, must be RFC3339",
-				})
-			}
+		filter, err := parsePVZFilter(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: err.Error()})
 		}
 
-		endDate := c.Query("endDate")
-		if endDate != "" {
-			if _, err := time.Parse(time.RFC3339, endDate); err != nil {
-				return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
-					Message: "invalid endDate format, must be RFC3339",
-				})
-			}
+		sort, err := parsePVZSort(c.Query("sort"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: err.Error()})
 		}
 
-		result, err := h.pvzService.ListPVZsWithRelations(startDate, endDate, page, limit)
+		result, err := h.pvzService.ListPVZsWithRelations(c.UserContext(), filter, sort, page, limit)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
-				Message: err.Error(),
-			})
+			status := fiber.StatusBadRequest
+			if errors.Is(err, domain.ErrInternal) {
+				status = fiber.StatusInternalServerError
+			}
+			return c.Status(status).JSON(models.ErrorResponse{Message: err.Error()})
 		}
 
 		return c.JSON(result)
 	}
 }
+
+// getPVZListByCursor serves GET /pvz in keyset mode, selected by the presence of the cursor parameter.
+// An empty cursor requests the first page.
+func (h *PVZHandlers) getPVZListByCursor(c *fiber.Ctx) error {
+	if c.Query("page") != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Message: "cursor and page parameters are mutually exclusive",
+		})
+	}
+
+	if c.Query("sort") != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Message: "sort is not supported with cursor pagination",
+		})
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit < 1 || limit > service.MaxCursorPageLimit {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Message: "limit must be between 1 and " + strconv.Itoa(service.MaxCursorPageLimit),
+		})
+	}
+
+	filter, err := parsePVZFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: err.Error()})
+	}
+
+	result, err := h.pvzService.ListPVZsByCursor(c.UserContext(), filter, c.Query("cursor"), limit)
+	if err != nil {
+		status := fiber.StatusBadRequest
+		if errors.Is(err, domain.ErrInternal) {
+			status = fiber.StatusInternalServerError
+		}
+		return c.Status(status).JSON(models.ErrorResponse{Message: err.Error()})
+	}
+
+	return c.JSON(result)
+}
+
+// parsePVZFilter reads the list filters from the query string. city and productType accept
+// repeated parameters as well as comma-separated values.
+func parsePVZFilter(c *fiber.Ctx) (repository.PVZFilter, error) {
+	var filter repository.PVZFilter
+	var err error
+
+	if startDate := c.Query("startDate"); startDate != "" {
+		if filter.StartDate, err = time.Parse(time.RFC3339, startDate); err != nil {
+			return filter, errors.New("invalid startDate format, must be RFC3339")
+		}
+	}
+
+	if endDate := c.Query("endDate"); endDate != "" {
+		if filter.EndDate, err = time.Parse(time.RFC3339, endDate); err != nil {
+			return filter, errors.New("invalid endDate format, must be RFC3339")
+		}
+	}
+
+	if !filter.StartDate.IsZero() && !filter.EndDate.IsZero() && filter.StartDate.After(filter.EndDate) {
+		return filter, errors.New("startDate must not be after endDate")
+	}
+
+	if filter.Cities, err = multiValueQuery(c, "city"); err != nil {
+		return filter, err
+	}
+
+	if filter.ProductTypes, err = multiValueQuery(c, "productType"); err != nil {
+		return filter, err
+	}
+
+	switch status := c.Query("receptionStatus"); status {
+	case "", "in_progress", "close":
+		filter.ReceptionStatus = status
+	default:
+		return filter, errors.New("receptionStatus must be in_progress or close")
+	}
+
+	if value := c.Query("hasOpenReception"); value != "" {
+		hasOpen, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, errors.New("hasOpenReception must be true or false")
+		}
+		filter.HasOpenReception = &hasOpen
+	}
+
+	if filter.MinProducts, err = nonNegativeQuery(c, "minProducts"); err != nil {
+		return filter, err
+	}
+
+	if filter.MaxProducts, err = nonNegativeQuery(c, "maxProducts"); err != nil {
+		return filter, err
+	}
+
+	if filter.MinProducts != nil && filter.MaxProducts != nil && *filter.MinProducts > *filter.MaxProducts {
+		return filter, errors.New("minProducts must not be greater than maxProducts")
+	}
+
+	return filter, nil
+}
+
+// parsePVZSort accepts a sort field with an optional "-" prefix for descending order.
+func parsePVZSort(value string) (repository.PVZSort, error) {
+	if value == "" {
+		return repository.PVZSort{}, nil
+	}
+
+	sort := repository.PVZSort{Field: value}
+	if strings.HasPrefix(value, "-") {
+		sort = repository.PVZSort{Field: value[1:], Desc: true}
+	}
+
+	if !repository.IsValidPVZSortField(sort.Field) {
+		return repository.PVZSort{}, errors.New("sort must be one of registrationDate, lastReception, productCount")
+	}
+
+	return sort, nil
+}
+
+func multiValueQuery(c *fiber.Ctx, key string) ([]string, error) {
+	var values []string
+	for _, raw := range c.Context().QueryArgs().PeekMulti(key) {
+		for _, value := range strings.Split(string(raw), ",") {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				return nil, errors.New(key + " must not be empty")
+			}
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+func nonNegativeQuery(c *fiber.Ctx, key string) (*int, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return nil, errors.New(key + " must be a non-negative integer")
+	}
+	return &value, nil
+}
@@ -2,12 +2,15 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"net/http/httptest"
+	"net/url"
 	"pvz-service/internal/handler/models"
 	"strconv"
 	"testing"
@@ -21,20 +24,39 @@ type MockPVZService struct {
 	mock.Mock
 }
 
-func (m *MockPVZService) CreatePVZ(city string) (domain.PVZ, error) {
+func (m *MockPVZService) CreatePVZ(ctx context.Context, city string) (domain.PVZ, error) {
 	args := m.Called(city)
 	return args.Get(0).(domain.PVZ), args.Error(1)
 }
 
-func (m *MockPVZService) GetPVZByID(id string) (domain.PVZ, error) {
+func (m *MockPVZService) GetPVZByID(ctx context.Context, id string) (domain.PVZ, error) {
 	args := m.Called(id)
 	return args.Get(0).(domain.PVZ), args.Error(1)
 }
 
 func (m *MockPVZService) ListPVZsWithRelations(
-	startDate, endDate string, page, limit int) ([]repository.PVZResponse, error) {
-	args := m.Called(startDate, endDate, page, limit)
-	return args.Get(0).([]repository.PVZResponse), args.Error(1)
+	ctx context.Context, filter repository.PVZFilter, sort repository.PVZSort, page, limit int,
+) (repository.PVZListResponse, error) {
+	args := m.Called(filter, sort, page, limit)
+	return args.Get(0).(repository.PVZListResponse), args.Error(1)
+}
+
+func (m *MockPVZService) ListPVZsByCursor(
+	ctx context.Context, filter repository.PVZFilter, cursor string, limit int) (repository.PVZCursorResponse, error) {
+	args := m.Called(filter, cursor, limit)
+	return args.Get(0).(repository.PVZCursorResponse), args.Error(1)
+}
+
+func (m *MockPVZService) ListPVZs(ctx context.Context) ([]domain.PVZ, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.PVZ), args.Error(1)
+}
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	assert.NoError(t, err)
+	return parsed
 }
 
 func TestPVZHandlers_CreatePVZHandler(t *testing.T) {
@@ -79,13 +101,18 @@ func TestPVZHandlers_GetPVZListHandler(t *testing.T) {
 	handler := NewPVZHandlers(mockProcessor)
 
 	t.Run("success with UTC timezone", func(t *testing.T) {
-		expected := []repository.PVZResponse{
-			{
-				PVZ: domain.PVZ{
-					ID:   "pvz1",
-					City: "Москва",
+		expected := repository.PVZListResponse{
+			Items: []repository.PVZResponse{
+				{
+					PVZ: domain.PVZ{
+						ID:   "pvz1",
+						City: "Москва",
+					},
 				},
 			},
+			Total: 1,
+			Page:  1,
+			Limit: 10,
 		}
 
 		startDate := "2025-04-01T00:00:00Z"
@@ -93,7 +120,8 @@ func TestPVZHandlers_GetPVZListHandler(t *testing.T) {
 		page := 1
 		limit := 10
 
-		mockProcessor.On("ListPVZsWithRelations", startDate, endDate, page, limit).
+		filter := repository.PVZFilter{StartDate: mustParseTime(t, startDate), EndDate: mustParseTime(t, endDate)}
+		mockProcessor.On("ListPVZsWithRelations", filter, repository.PVZSort{}, page, limit).
 			Return(expected, nil)
 
 		app.Get("/pvz", handler.GetPVZListHandler())
@@ -109,13 +137,18 @@ func TestPVZHandlers_GetPVZListHandler(t *testing.T) {
 	})
 
 	t.Run("success with dynamic time", func(t *testing.T) {
-		expected := []repository.PVZResponse{
-			{
-				PVZ: domain.PVZ{
-					ID:   "pvz1",
-					City: "Москва",
+		expected := repository.PVZListResponse{
+			Items: []repository.PVZResponse{
+				{
+					PVZ: domain.PVZ{
+						ID:   "pvz1",
+						City: "Москва",
+					},
 				},
 			},
+			Total: 1,
+			Page:  1,
+			Limit: 10,
 		}
 
 		now := time.Now().UTC()
@@ -124,7 +157,8 @@ func TestPVZHandlers_GetPVZListHandler(t *testing.T) {
 		page := 1
 		limit := 10
 
-		mockProcessor.On("ListPVZsWithRelations", startDate, endDate, page, limit).
+		filter := repository.PVZFilter{StartDate: mustParseTime(t, startDate), EndDate: mustParseTime(t, endDate)}
+		mockProcessor.On("ListPVZsWithRelations", filter, repository.PVZSort{}, page, limit).
 			Return(expected, nil)
 
 		app.Get("/pvz", handler.GetPVZListHandler())
@@ -140,19 +174,24 @@ func TestPVZHandlers_GetPVZListHandler(t *testing.T) {
 	})
 
 	t.Run("success without dates", func(t *testing.T) {
-		expected := []repository.PVZResponse{
-			{
-				PVZ: domain.PVZ{
-					ID:   "pvz1",
-					City: "Москва",
+		expected := repository.PVZListResponse{
+			Items: []repository.PVZResponse{
+				{
+					PVZ: domain.PVZ{
+						ID:   "pvz1",
+						City: "Москва",
+					},
 				},
 			},
+			Total: 1,
+			Page:  1,
+			Limit: 10,
 		}
 
 		page := 1
 		limit := 10
 
-		mockProcessor.On("ListPVZsWithRelations", "", "", page, limit).
+		mockProcessor.On("ListPVZsWithRelations", repository.PVZFilter{}, repository.PVZSort{}, page, limit).
 			Return(expected, nil)
 
 		app.Get("/pvz", handler.GetPVZListHandler())
@@ -163,6 +202,13 @@ func TestPVZHandlers_GetPVZListHandler(t *testing.T) {
 		resp, err := app.Test(req)
 		assert.NoError(t, err)
 		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var body repository.PVZListResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Len(t, body.Items, 1)
+		assert.Equal(t, 1, body.Total)
+		assert.Equal(t, 1, body.Page)
+		assert.Equal(t, 10, body.Limit)
 		mockProcessor.AssertExpectations(t)
 	})
 
@@ -205,9 +251,9 @@ func TestPVZHandlers_GetPVZListHandler(t *testing.T) {
 	})
 
 	t.Run("valid maximum limit", func(t *testing.T) {
-		expected := []repository.PVZResponse{}
+		expected := repository.PVZListResponse{Items: []repository.PVZResponse{}, Page: 1, Limit: 30}
 
-		mockProcessor.On("ListPVZsWithRelations", "", "", 1, 30).
+		mockProcessor.On("ListPVZsWithRelations", repository.PVZFilter{}, repository.PVZSort{}, 1, 30).
 			Return(expected, nil)
 
 		app.Get("/pvz", handler.GetPVZListHandler())
@@ -233,4 +279,176 @@ func TestPVZHandlers_GetPVZListHandler(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
 	})
+
+	t.Run("database error", func(t *testing.T) {
+		mockProcessor.On("ListPVZsWithRelations", repository.PVZFilter{}, repository.PVZSort{}, 2, 5).
+			Return(repository.PVZListResponse{}, &domain.InternalError{Msg: "database error"})
+
+		app.Get("/pvz", handler.GetPVZListHandler())
+		req := httptest.NewRequest("GET", "/pvz?page=2&limit=5", nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	})
+
+	t.Run("start date after end date", func(t *testing.T) {
+		startDate := "2025-04-20T00:00:00Z"
+		endDate := "2025-04-01T00:00:00Z"
+		app.Get("/pvz", handler.GetPVZListHandler())
+		req := httptest.NewRequest("GET", "/pvz?startDate="+startDate+"&endDate="+endDate+"&page=1&limit=10", nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestPVZHandlers_GetPVZListHandler_Cursor(t *testing.T) {
+	app := fiber.New()
+	mockProcessor := new(MockPVZService)
+	handler := NewPVZHandlers(mockProcessor)
+	app.Get("/pvz", handler.GetPVZListHandler())
+
+	t.Run("first page", func(t *testing.T) {
+		expected := repository.PVZCursorResponse{
+			Items:      []repository.PVZResponse{{PVZ: domain.PVZ{ID: "pvz1", City: "Москва"}}},
+			Limit:      100,
+			NextCursor: "next",
+		}
+		mockProcessor.On("ListPVZsByCursor", repository.PVZFilter{}, "", 100).Return(expected, nil).Once()
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/pvz?cursor=&limit=100", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var body repository.PVZCursorResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, "next", body.NextCursor)
+		assert.Len(t, body.Items, 1)
+		mockProcessor.AssertExpectations(t)
+	})
+
+	t.Run("next page", func(t *testing.T) {
+		mockProcessor.On("ListPVZsByCursor", repository.PVZFilter{}, "abc", 500).
+			Return(repository.PVZCursorResponse{Items: []repository.PVZResponse{}, Limit: 500}, nil).Once()
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/pvz?cursor=abc&limit=500", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		mockProcessor.AssertExpectations(t)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		mockProcessor.On("ListPVZsByCursor", repository.PVZFilter{}, "broken", 10).
+			Return(repository.PVZCursorResponse{}, errors.New("invalid cursor")).Once()
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/pvz?cursor=broken&limit=10", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("filters are passed in cursor mode", func(t *testing.T) {
+		filter := repository.PVZFilter{Cities: []string{"Казань"}}
+		mockProcessor.On("ListPVZsByCursor", filter, "", 10).
+			Return(repository.PVZCursorResponse{Items: []repository.PVZResponse{}, Limit: 10}, nil).Once()
+
+		query := url.Values{"cursor": {""}, "limit": {"10"}, "city": {"Казань"}}
+		resp, err := app.Test(httptest.NewRequest("GET", "/pvz?"+query.Encode(), nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		mockProcessor.AssertExpectations(t)
+	})
+
+	t.Run("sort with cursor", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest("GET", "/pvz?cursor=&limit=10&sort=productCount", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("cursor with page", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest("GET", "/pvz?cursor=&page=1&limit=10", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("limit above maximum", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest("GET", "/pvz?cursor=&limit=1001", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("missing limit", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest("GET", "/pvz?cursor=", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestPVZHandlers_GetPVZListHandler_Filters(t *testing.T) {
+	app := fiber.New()
+	mockProcessor := new(MockPVZService)
+	handler := NewPVZHandlers(mockProcessor)
+	app.Get("/pvz", handler.GetPVZListHandler())
+
+	t.Run("all filters and descending sort", func(t *testing.T) {
+		hasOpen := true
+		minProducts, maxProducts := 1, 50
+		filter := repository.PVZFilter{
+			Cities:           []string{"Москва", "Казань", "Санкт-Петербург"},
+			ReceptionStatus:  "close",
+			ProductTypes:     []string{"обувь", "одежда"},
+			HasOpenReception: &hasOpen,
+			MinProducts:      &minProducts,
+			MaxProducts:      &maxProducts,
+		}
+		sort := repository.PVZSort{Field: repository.PVZSortProductCount, Desc: true}
+		mockProcessor.On("ListPVZsWithRelations", filter, sort, 1, 10).
+			Return(repository.PVZListResponse{Items: []repository.PVZResponse{}, Page: 1, Limit: 10}, nil).Once()
+
+		query := url.Values{}
+		query.Add("page", "1")
+		query.Add("limit", "10")
+		query.Add("city", "Москва,Казань")
+		query.Add("city", "Санкт-Петербург")
+		query.Add("receptionStatus", "close")
+		query.Add("productType", "обувь")
+		query.Add("productType", "одежда")
+		query.Add("hasOpenReception", "true")
+		query.Add("minProducts", "1")
+		query.Add("maxProducts", "50")
+		query.Add("sort", "-productCount")
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/pvz?"+query.Encode(), nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		mockProcessor.AssertExpectations(t)
+	})
+
+	t.Run("ascending sort by last reception", func(t *testing.T) {
+		sort := repository.PVZSort{Field: repository.PVZSortLastReception}
+		mockProcessor.On("ListPVZsWithRelations", repository.PVZFilter{}, sort, 1, 10).
+			Return(repository.PVZListResponse{Items: []repository.PVZResponse{}, Page: 1, Limit: 10}, nil).Once()
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/pvz?page=1&limit=10&sort=lastReception", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		mockProcessor.AssertExpectations(t)
+	})
+
+	invalid := map[string]string{
+		"unknown sort":             "sort=city",
+		"unknown reception status": "receptionStatus=open",
+		"empty city":               "city=",
+		"invalid hasOpenReception": "hasOpenReception=maybe",
+		"negative minProducts":     "minProducts=-1",
+		"non-numeric maxProducts":  "maxProducts=many",
+		"min greater than max":     "minProducts=5&maxProducts=2",
+		"start after end":          "startDate=2025-04-20T00:00:00Z&endDate=2025-04-01T00:00:00Z",
+	}
+	for name, query := range invalid {
+		t.Run(name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest("GET", "/pvz?page=1&limit=10&"+query, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+		})
+	}
 }
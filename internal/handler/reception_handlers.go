@@ -1,20 +1,32 @@
 package handler
 
 import (
+	"errors"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"pvz-service/internal/handler/models"
 	"pvz-service/internal/prometheus"
+	"strconv"
+	"time"
 
+	"pvz-service/internal/domain"
+	"pvz-service/internal/repository"
 	"pvz-service/internal/service"
 )
 
+// PVZAccessCheck sends the error response and returns false when the caller may
+// not access the PVZ, see middleware.CheckPVZAccess.
+type PVZAccessCheck func(c *fiber.Ctx, pvzID string) (bool, error)
+
 type ReceptionHandlers struct {
 	receptionProcessor service.ReceptionService
+	checkPVZAccess     PVZAccessCheck
 }
 
-func NewReceptionHandlers(receptionProcessor service.ReceptionService) *ReceptionHandlers {
-	return &ReceptionHandlers{receptionProcessor: receptionProcessor}
+func NewReceptionHandlers(
+	receptionProcessor service.ReceptionService, checkPVZAccess PVZAccessCheck,
+) *ReceptionHandlers {
+	return &ReceptionHandlers{receptionProcessor: receptionProcessor, checkPVZAccess: checkPVZAccess}
 }
 
 func (h *ReceptionHandlers) CreateReceptionHandler() fiber.Handler {
@@ -30,9 +42,9 @@ func (h *ReceptionHandlers) CreateReceptionHandler() fiber.Handler {
 			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid pvzId format"})
 		}
 
-		reception, err := h.receptionProcessor.CreateReception(body.PvzId)
+		reception, err := h.receptionProcessor.CreateReception(c.UserContext(), body.PvzId)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: err.Error()})
+			return c.Status(receptionErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
 		}
 
 		prometheus.OrderAcceptancesCreated.Inc()
@@ -48,11 +60,143 @@ func (h *ReceptionHandlers) CloseLastReceptionHandler() fiber.Handler {
 			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid pvzId format"})
 		}
 
-		reception, err := h.receptionProcessor.CloseLastReception(pvzId)
+		reception, err := h.receptionProcessor.CloseLastReception(c.UserContext(), pvzId)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: err.Error()})
+			return c.Status(receptionErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.JSON(reception)
+	}
+}
+
+func (h *ReceptionHandlers) GetReceptionHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		receptionID := c.Params("receptionId")
+		if _, err := uuid.Parse(receptionID); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid receptionId format"})
+		}
+
+		reception, err := h.receptionProcessor.GetReceptionByID(c.UserContext(), receptionID)
+		if err != nil {
+			return c.Status(receptionErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+		if ok, err := h.checkPVZAccess(c, reception.PvzId); !ok {
+			return err
 		}
 
 		return c.JSON(reception)
 	}
 }
+
+func (h *ReceptionHandlers) ListPVZReceptionsHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		pvzID := c.Params("pvzId")
+		if _, err := uuid.Parse(pvzID); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid pvzId format"})
+		}
+
+		page, limit, err := parsePageParams(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		var filter repository.ReceptionFilter
+		switch status := c.Query("status"); status {
+		case "", "in_progress", "close":
+			filter.Status = status
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Message: "status must be in_progress or close",
+			})
+		}
+
+		if startDate := c.Query("startDate"); startDate != "" {
+			if filter.StartDate, err = time.Parse(time.RFC3339, startDate); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+					Message: "invalid startDate format, must be RFC3339",
+				})
+			}
+		}
+
+		if endDate := c.Query("endDate"); endDate != "" {
+			if filter.EndDate, err = time.Parse(time.RFC3339, endDate); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+					Message: "invalid endDate format, must be RFC3339",
+				})
+			}
+		}
+
+		result, err := h.receptionProcessor.ListReceptionsByPVZ(c.UserContext(), pvzID, filter, page, limit)
+		if err != nil {
+			return c.Status(receptionErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.JSON(result)
+	}
+}
+
+func (h *ReceptionHandlers) ListReceptionProductsHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		receptionID := c.Params("receptionId")
+		if _, err := uuid.Parse(receptionID); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid receptionId format"})
+		}
+
+		page, limit, err := parsePageParams(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		// The reception is loaded first to learn its PVZ for the access check.
+		reception, err := h.receptionProcessor.GetReceptionByID(c.UserContext(), receptionID)
+		if err != nil {
+			return c.Status(receptionErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+		if ok, err := h.checkPVZAccess(c, reception.PvzId); !ok {
+			return err
+		}
+
+		result, err := h.receptionProcessor.ListReceptionProducts(c.UserContext(), receptionID, page, limit)
+		if err != nil {
+			return c.Status(receptionErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.JSON(result)
+	}
+}
+
+// receptionErrorStatus maps reception and product workflow errors with the same
+// classification as the gRPC server. Conflicts and failed preconditions stay 400
+// as documented in the API.
+func receptionErrorStatus(err error) int {
+	switch domain.KindOf(err) {
+	case domain.KindNotFound:
+		return fiber.StatusNotFound
+	case domain.KindInternal:
+		return fiber.StatusInternalServerError
+	}
+	return fiber.StatusBadRequest
+}
+
+// parsePageParams reads optional page and limit with the swagger defaults of 1 and 10.
+func parsePageParams(c *fiber.Ctx) (int, int, error) {
+	page, limit := 1, 10
+
+	if raw := c.Query("page"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 {
+			return 0, 0, errors.New("page must be a positive integer")
+		}
+		page = value
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 || value > 30 {
+			return 0, 0, errors.New("limit must be between 1 and 30")
+		}
+		limit = value
+	}
+
+	return page, limit, nil
+}
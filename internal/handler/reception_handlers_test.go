@@ -2,35 +2,85 @@ package handler
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"pvz-service/internal/domain"
+	"pvz-service/internal/handler/models"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/service"
 )
 
+// allowPVZs stands in for middleware.CheckPVZAccess and denies only the given PVZs.
+func allowPVZs(denied ...string) PVZAccessCheck {
+	return func(c *fiber.Ctx, pvzID string) (bool, error) {
+		for _, id := range denied {
+			if id == pvzID {
+				return false, c.Status(fiber.StatusForbidden).JSON(models.ErrorResponse{
+					Message: "PVZ is not assigned to the user",
+				})
+			}
+		}
+		return true, nil
+	}
+}
+
 type MockReceptionProcessor struct {
 	mock.Mock
 }
 
-func (m *MockReceptionProcessor) CreateReception(pvzID string) (domain.Reception, error) {
+func (m *MockReceptionProcessor) CreateReception(ctx context.Context, pvzID string) (domain.Reception, error) {
 	args := m.Called(pvzID)
 	return args.Get(0).(domain.Reception), args.Error(1)
 }
 
-func (m *MockReceptionProcessor) CloseLastReception(pvzID string) (domain.Reception, error) {
+func (m *MockReceptionProcessor) CloseLastReception(ctx context.Context, pvzID string) (domain.Reception, error) {
 	args := m.Called(pvzID)
 	return args.Get(0).(domain.Reception), args.Error(1)
 }
 
+func (m *MockReceptionProcessor) GetReceptionByID(ctx context.Context, id string) (domain.Reception, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.Reception), args.Error(1)
+}
+
+func (m *MockReceptionProcessor) ListReceptionsByPVZ(ctx context.Context, pvzID string,
+	filter repository.ReceptionFilter, page, limit int) (repository.ReceptionListResponse, error) {
+	args := m.Called(pvzID, filter, page, limit)
+	return args.Get(0).(repository.ReceptionListResponse), args.Error(1)
+}
+
+func (m *MockReceptionProcessor) ListReceptionProducts(
+	ctx context.Context, receptionID string, page, limit int) (repository.ProductListResponse, error) {
+	args := m.Called(receptionID, page, limit)
+	return args.Get(0).(repository.ProductListResponse), args.Error(1)
+}
+
+func (m *MockReceptionProcessor) GetReceptionWithProducts(
+	ctx context.Context, id string) (repository.ReceptionResponse, error) {
+	args := m.Called(id)
+	return args.Get(0).(repository.ReceptionResponse), args.Error(1)
+}
+
+func (m *MockReceptionProcessor) ListReceptionsWithProducts(ctx context.Context, pvzID string,
+	filter repository.ReceptionFilter, page, limit int) (repository.ReceptionWithProductsListResponse, error) {
+	args := m.Called(pvzID, filter, page, limit)
+	return args.Get(0).(repository.ReceptionWithProductsListResponse), args.Error(1)
+}
+
 func TestReceptionHandlers_CreateReceptionHandler(t *testing.T) {
 	app := fiber.New()
 	mockProcessor := new(MockReceptionProcessor)
-	handler := NewReceptionHandlers(mockProcessor)
+	handler := NewReceptionHandlers(mockProcessor, allowPVZs())
 
 	t.Run("success", func(t *testing.T) {
 		pvzID := uuid.New().String()
@@ -77,12 +127,34 @@ func TestReceptionHandlers_CreateReceptionHandler(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
 	})
+
+	for name, tc := range map[string]struct {
+		err    error
+		status int
+	}{
+		"pvz not found":  {domain.ErrPVZNotFound, fiber.StatusNotFound},
+		"internal error": {&domain.InternalError{Msg: "failed to create reception"}, fiber.StatusInternalServerError},
+		"already open":   {domain.ErrOpenReceptionExists, fiber.StatusBadRequest},
+	} {
+		t.Run(name, func(t *testing.T) {
+			pvzID := uuid.New().String()
+			mockProcessor.On("CreateReception", pvzID).Return(domain.Reception{}, tc.err)
+			app.Post("/receptions", handler.CreateReceptionHandler())
+
+			req := httptest.NewRequest("POST", "/receptions", bytes.NewBufferString(`{"pvzId":"`+pvzID+`"}`))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.status, resp.StatusCode)
+		})
+	}
 }
 
 func TestReceptionHandlers_CloseLastReceptionHandler(t *testing.T) {
 	app := fiber.New()
 	mockProcessor := new(MockReceptionProcessor)
-	handler := NewReceptionHandlers(mockProcessor)
+	handler := NewReceptionHandlers(mockProcessor, allowPVZs())
 
 	t.Run("success", func(t *testing.T) {
 		pvzID := uuid.New().String()
@@ -114,3 +186,214 @@ func TestReceptionHandlers_CloseLastReceptionHandler(t *testing.T) {
 		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
 	})
 }
+
+func TestReceptionHandlers_GetReceptionHandler(t *testing.T) {
+	app := fiber.New()
+	mockProcessor := new(MockReceptionProcessor)
+	deniedPVZ := uuid.NewString()
+	handler := NewReceptionHandlers(mockProcessor, allowPVZs(deniedPVZ))
+	app.Get("/receptions/:receptionId", handler.GetReceptionHandler())
+
+	t.Run("success", func(t *testing.T) {
+		receptionID := uuid.New().String()
+		mockProcessor.On("GetReceptionByID", receptionID).
+			Return(domain.Reception{ID: receptionID, Status: "in_progress"}, nil).Once()
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/receptions/"+receptionID, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var body domain.Reception
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, receptionID, body.ID)
+	})
+
+	t.Run("unassigned pvz", func(t *testing.T) {
+		receptionID := uuid.New().String()
+		mockProcessor.On("GetReceptionByID", receptionID).
+			Return(domain.Reception{ID: receptionID, PvzId: deniedPVZ}, nil).Once()
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/receptions/"+receptionID, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		receptionID := uuid.New().String()
+		mockProcessor.On("GetReceptionByID", receptionID).
+			Return(domain.Reception{}, domain.ErrReceptionNotFound).Once()
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/receptions/"+receptionID, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest("GET", "/receptions/invalid-uuid", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestReceptionHandlers_ListPVZReceptionsHandler(t *testing.T) {
+	app := fiber.New()
+	mockProcessor := new(MockReceptionProcessor)
+	handler := NewReceptionHandlers(mockProcessor, allowPVZs())
+	app.Get("/pvz/:pvzId/receptions", handler.ListPVZReceptionsHandler())
+
+	t.Run("defaults", func(t *testing.T) {
+		pvzID := uuid.New().String()
+		mockProcessor.On("ListReceptionsByPVZ", pvzID, repository.ReceptionFilter{}, 1, 10).
+			Return(repository.ReceptionListResponse{Items: []domain.Reception{}, Page: 1, Limit: 10}, nil).Once()
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/pvz/"+pvzID+"/receptions", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		mockProcessor.AssertExpectations(t)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		pvzID := uuid.New().String()
+		filter := repository.ReceptionFilter{
+			Status:    "close",
+			StartDate: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2025, 4, 20, 0, 0, 0, 0, time.UTC),
+		}
+		mockProcessor.On("ListReceptionsByPVZ", pvzID, filter, 2, 5).
+			Return(repository.ReceptionListResponse{Items: []domain.Reception{}, Page: 2, Limit: 5}, nil).Once()
+
+		url := "/pvz/" + pvzID + "/receptions?status=close&startDate=2025-04-01T00:00:00Z" +
+			"&endDate=2025-04-20T00:00:00Z&page=2&limit=5"
+		resp, err := app.Test(httptest.NewRequest("GET", url, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		mockProcessor.AssertExpectations(t)
+	})
+
+	t.Run("pvz not found", func(t *testing.T) {
+		pvzID := uuid.New().String()
+		mockProcessor.On("ListReceptionsByPVZ", pvzID, repository.ReceptionFilter{}, 1, 10).
+			Return(repository.ReceptionListResponse{}, domain.ErrPVZNotFound).Once()
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/pvz/"+pvzID+"/receptions", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	})
+
+	invalid := map[string]string{
+		"invalid pvzId":  "/pvz/invalid-uuid/receptions",
+		"invalid status": "/pvz/" + uuid.New().String() + "/receptions?status=open",
+		"invalid date":   "/pvz/" + uuid.New().String() + "/receptions?startDate=yesterday",
+		"invalid page":   "/pvz/" + uuid.New().String() + "/receptions?page=0",
+		"invalid limit":  "/pvz/" + uuid.New().String() + "/receptions?limit=31",
+	}
+	for name, url := range invalid {
+		t.Run(name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest("GET", url, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+		})
+	}
+}
+
+func TestReceptionHandlers_ListReceptionProductsHandler(t *testing.T) {
+	app := fiber.New()
+	mockProcessor := new(MockReceptionProcessor)
+	deniedPVZ := uuid.NewString()
+	handler := NewReceptionHandlers(mockProcessor, allowPVZs(deniedPVZ))
+	app.Get("/receptions/:receptionId/products", handler.ListReceptionProductsHandler())
+
+	t.Run("success", func(t *testing.T) {
+		receptionID := uuid.New().String()
+		expected := repository.ProductListResponse{
+			Items: []domain.Product{{ID: "prod1"}, {ID: "prod2"}},
+			Total: 2,
+			Page:  1,
+			Limit: 10,
+		}
+		mockProcessor.On("GetReceptionByID", receptionID).
+			Return(domain.Reception{ID: receptionID, PvzId: uuid.NewString()}, nil).Once()
+		mockProcessor.On("ListReceptionProducts", receptionID, 1, 10).Return(expected, nil).Once()
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/receptions/"+receptionID+"/products", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var body repository.ProductListResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Len(t, body.Items, 2)
+		assert.Equal(t, 2, body.Total)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		receptionID := uuid.New().String()
+		mockProcessor.On("GetReceptionByID", receptionID).
+			Return(domain.Reception{ID: receptionID, PvzId: uuid.NewString()}, nil).Once()
+		mockProcessor.On("ListReceptionProducts", receptionID, 1, 10).
+			Return(repository.ProductListResponse{}, &domain.InternalError{Msg: "database error"}).Once()
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/receptions/"+receptionID+"/products", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	})
+
+	t.Run("unassigned pvz", func(t *testing.T) {
+		receptionID := uuid.New().String()
+		mockProcessor.On("GetReceptionByID", receptionID).
+			Return(domain.Reception{ID: receptionID, PvzId: deniedPVZ}, nil).Once()
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/receptions/"+receptionID+"/products", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest("GET", "/receptions/invalid-uuid/products", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestReceptionHandlers_CloseLastReceptionHandler_TransactionFailure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	app := fiber.New()
+	processor := service.NewReceptionService(repository.NewReceptionRepository(db), repository.NewProductRepository(db),
+		repository.NewPVZRepository(db), repository.NewTxManager(db), nil)
+	app.Post("/pvz/:pvzId/close_last_reception", NewReceptionHandlers(processor, allowPVZs()).CloseLastReceptionHandler())
+	pvzID := uuid.NewString()
+
+	request := func() (int, string) {
+		resp, err := app.Test(httptest.NewRequest("POST", "/pvz/"+pvzID+"/close_last_reception", nil))
+		assert.NoError(t, err)
+		var body models.ErrorResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return resp.StatusCode, body.Message
+	}
+
+	t.Run("begin", func(t *testing.T) {
+		mock.ExpectBegin().WillReturnError(errors.New("context deadline exceeded"))
+
+		status, message := request()
+		assert.Equal(t, fiber.StatusInternalServerError, status)
+		assert.Equal(t, "failed to close reception", message)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		receptionID := uuid.NewString()
+		mock.ExpectBegin()
+		mock.ExpectQuery("FOR UPDATE").WithArgs(pvzID).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "created_at", "pvz_id", "status", "closed_at"}).
+				AddRow(receptionID, time.Now(), pvzID, "in_progress", nil))
+		mock.ExpectExec("UPDATE receptions SET status = 'close'").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit().WillReturnError(errors.New("pq: could not serialize access"))
+
+		status, message := request()
+		assert.Equal(t, fiber.StatusInternalServerError, status)
+		assert.Equal(t, "failed to close reception", message)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/handler/models"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/service"
+)
+
+type UserHandlers struct {
+	userService service.UserService
+}
+
+func NewUserHandlers(userService service.UserService) *UserHandlers {
+	return &UserHandlers{userService: userService}
+}
+
+func (h *UserHandlers) ListUsersHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		page, limit, err := parsePageParams(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		filter := repository.UserFilter{Role: c.Query("role")}
+		if raw := c.Query("disabled"); raw != "" {
+			disabled, err := strconv.ParseBool(raw)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+					Message: "disabled must be true or false",
+				})
+			}
+			filter.Disabled = &disabled
+		}
+
+		result, err := h.userService.ListUsers(c.UserContext(), filter, page, limit)
+		if err != nil {
+			return c.Status(userErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.JSON(result)
+	}
+}
+
+func (h *UserHandlers) GetUserHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID := c.Params("userId")
+		if _, err := uuid.Parse(userID); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid userId format"})
+		}
+
+		user, err := h.userService.GetUser(c.UserContext(), userID)
+		if err != nil {
+			return c.Status(userErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.JSON(user)
+	}
+}
+
+func (h *UserHandlers) UpdateUserHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID := c.Params("userId")
+		if _, err := uuid.Parse(userID); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid userId format"})
+		}
+
+		var body struct {
+			Role     *string `json:"role"`
+			Disabled *bool   `json:"disabled"`
+		}
+		if err := c.BodyParser(&body); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid request"})
+		}
+
+		if body.Role == nil && body.Disabled == nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Nothing to update"})
+		}
+
+		claims := c.Locals("claims").(jwt.MapClaims)
+		actorID, _ := claims["userId"].(string)
+		actorRole, _ := claims["role"].(string)
+
+		user, err := h.userService.UpdateUser(c.UserContext(), actorID, actorRole, userID, body.Role, body.Disabled)
+		if err != nil {
+			return c.Status(userErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.JSON(user)
+	}
+}
+
+func (h *UserHandlers) DeleteUserHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID := c.Params("userId")
+		if _, err := uuid.Parse(userID); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Message: "Invalid userId format"})
+		}
+
+		claims := c.Locals("claims").(jwt.MapClaims)
+		actorID, _ := claims["userId"].(string)
+
+		if err := h.userService.DeleteUser(c.UserContext(), actorID, userID); err != nil {
+			return c.Status(userErrorStatus(err)).JSON(models.ErrorResponse{Message: err.Error()})
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func userErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, domain.ErrForbiddenUserEdit):
+		return fiber.StatusForbidden
+	case errors.Is(err, domain.ErrSelfModification),
+		err.Error() == "invalid role",
+		err.Error() == "invalid page number",
+		err.Error() == "invalid limit":
+		return fiber.StatusBadRequest
+	}
+	return fiber.StatusInternalServerError
+}
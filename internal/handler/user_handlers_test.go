@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/repository"
+)
+
+type MockUserService struct {
+	mock.Mock
+}
+
+func (m *MockUserService) ListUsers(
+	ctx context.Context, filter repository.UserFilter, page, limit int) (repository.UserListResponse, error) {
+	args := m.Called(filter, page, limit)
+	return args.Get(0).(repository.UserListResponse), args.Error(1)
+}
+
+func (m *MockUserService) GetUser(ctx context.Context, id string) (domain.User, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func (m *MockUserService) UpdateUser(
+	ctx context.Context, actorID, actorRole, id string, role *string, disabled *bool) (domain.User, error) {
+	args := m.Called(actorID, actorRole, id, role, disabled)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func (m *MockUserService) DeleteUser(ctx context.Context, actorID, id string) error {
+	args := m.Called(actorID, id)
+	return args.Error(0)
+}
+
+func withActor(actorID, role string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals("claims", jwt.MapClaims{"userId": actorID, "role": role})
+		return c.Next()
+	}
+}
+
+func TestUserHandlers_ListUsersHandler(t *testing.T) {
+	app := fiber.New()
+	mockService := new(MockUserService)
+	handler := NewUserHandlers(mockService)
+	app.Get("/users", handler.ListUsersHandler())
+
+	disabled := true
+	mockService.On("ListUsers", repository.UserFilter{Role: "employee", Disabled: &disabled}, 2, 5).
+		Return(repository.UserListResponse{Items: []domain.User{}, Page: 2, Limit: 5}, nil)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users?role=employee&disabled=true&page=2&limit=5", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	mockService.AssertExpectations(t)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/users?disabled=maybe", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func TestUserHandlers_GetUserHandler(t *testing.T) {
+	app := fiber.New()
+	mockService := new(MockUserService)
+	handler := NewUserHandlers(mockService)
+	app.Get("/users/:userId", handler.GetUserHandler())
+
+	userID := uuid.NewString()
+	mockService.On("GetUser", userID).Return(domain.User{}, domain.ErrUserNotFound)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/"+userID, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+func TestUserHandlers_UpdateUserHandler(t *testing.T) {
+	app := fiber.New()
+	mockService := new(MockUserService)
+	handler := NewUserHandlers(mockService)
+	app.Patch("/users/:userId", withActor("actor1", "moderator"), handler.UpdateUserHandler())
+
+	userID := uuid.NewString()
+	patch := func(body string) int {
+		req := httptest.NewRequest("PATCH", "/users/"+userID, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("UpdateUser", "actor1", "moderator", userID, (*string)(nil), mock.AnythingOfType("*bool")).
+			Return(domain.User{ID: userID, Disabled: true}, nil).Once()
+
+		assert.Equal(t, fiber.StatusOK, patch(`{"disabled":true}`))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		mockService.On("UpdateUser", "actor1", "moderator", userID, mock.AnythingOfType("*string"), (*bool)(nil)).
+			Return(domain.User{}, domain.ErrForbiddenUserEdit).Once()
+
+		assert.Equal(t, fiber.StatusForbidden, patch(`{"role":"admin"}`))
+	})
+
+	t.Run("nothing to update", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusBadRequest, patch(`{}`))
+	})
+}
+
+func TestUserHandlers_DeleteUserHandler(t *testing.T) {
+	app := fiber.New()
+	mockService := new(MockUserService)
+	handler := NewUserHandlers(mockService)
+	app.Delete("/users/:userId", withActor("actor1", "admin"), handler.DeleteUserHandler())
+
+	userID := uuid.NewString()
+	mockService.On("DeleteUser", "actor1", userID).Return(nil)
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/users/"+userID, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+	mockService.AssertExpectations(t)
+}
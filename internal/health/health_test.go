@@ -0,0 +1,55 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecker_Ready(t *testing.T) {
+	checker := NewChecker(time.Second)
+	checker.Add("database", func(ctx context.Context) error { return nil })
+	checker.Add("grpc", func(ctx context.Context) error { return nil })
+
+	report := checker.Ready(context.Background())
+	assert.True(t, report.OK())
+	assert.Equal(t, Result{Status: StatusOK}, report.Checks["database"])
+	assert.Len(t, report.Checks, 2)
+}
+
+func TestChecker_FailingCheck(t *testing.T) {
+	checker := NewChecker(time.Second)
+	checker.Add("database", func(ctx context.Context) error { return errors.New("connection refused") })
+	checker.Add("grpc", func(ctx context.Context) error { return nil })
+
+	report := checker.Ready(context.Background())
+	assert.False(t, report.OK())
+	assert.Equal(t, Result{Status: StatusFail, Error: "connection refused"}, report.Checks["database"])
+	assert.Equal(t, StatusOK, report.Checks["grpc"].Status)
+}
+
+func TestChecker_Timeout(t *testing.T) {
+	checker := NewChecker(20 * time.Millisecond)
+	checker.Add("database", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report := checker.Ready(context.Background())
+	assert.False(t, report.OK())
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["database"].Error)
+}
+
+func TestChecker_Shutdown(t *testing.T) {
+	checker := NewChecker(time.Second)
+	checker.Add("database", func(ctx context.Context) error { return nil })
+	checker.Shutdown()
+
+	report := checker.Ready(context.Background())
+	assert.False(t, report.OK())
+	assert.Equal(t, ErrShuttingDown.Error(), report.Checks["shutdown"].Error)
+	assert.Equal(t, StatusOK, report.Checks["database"].Status)
+}
@@ -0,0 +1,229 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	privateKeySuffix = ".pem"
+	publicKeySuffix  = ".pub.pem"
+)
+
+var ErrUnknownKeyID = errors.New("unknown key id")
+
+// Key is a single signing or verification key identified by its kid.
+// Verification-only keys have no private part and are kept to accept
+// tokens signed before a rotation.
+type Key struct {
+	ID        string
+	Method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// KeySet signs tokens with the active key and verifies them against any key
+// in the set selected by the kid header.
+type KeySet struct {
+	active  *Key
+	keys    map[string]*Key
+	methods []string
+}
+
+// NewHMACKeySet returns a single-key HS256 set. Tokens are signed without kid,
+// and the key is never published in the JWKS.
+func NewHMACKeySet(secret string) *KeySet {
+	key := &Key{Method: jwt.SigningMethodHS256, signKey: []byte(secret), verifyKey: []byte(secret)}
+	return newKeySet(key, []*Key{key})
+}
+
+// Load reads the key set from dir, or falls back to an HMAC set built from
+// secret when dir is empty.
+func Load(dir, activeKeyID, secret string) (*KeySet, error) {
+	if dir == "" {
+		return NewHMACKeySet(secret), nil
+	}
+	return LoadKeySet(dir, activeKeyID)
+}
+
+// LoadKeySet reads <kid>.pem private keys and <kid>.pub.pem public keys from dir.
+// RSA keys sign with RS256 and Ed25519 keys with EdDSA. The active key must
+// have a private part; every other key is used for verification only.
+func LoadKeySet(dir, activeKeyID string) (*KeySet, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key directory: %w", err)
+	}
+
+	keys := make(map[string]*Key)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, privateKeySuffix) {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key %s: %w", name, err)
+		}
+
+		var key *Key
+		if strings.HasSuffix(name, publicKeySuffix) {
+			key, err = parsePublicKey(strings.TrimSuffix(name, publicKeySuffix), data)
+		} else {
+			key, err = parsePrivateKey(strings.TrimSuffix(name, privateKeySuffix), data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse key %s: %w", name, err)
+		}
+
+		// A private key also covers its own public part.
+		if existing, ok := keys[key.ID]; ok && existing.signKey != nil {
+			continue
+		}
+		keys[key.ID] = key
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no keys found in %s", dir)
+	}
+
+	if activeKeyID == "" {
+		if len(keys) > 1 {
+			return nil, errors.New("active key id is required when several keys are configured")
+		}
+		for id := range keys {
+			activeKeyID = id
+		}
+	}
+
+	active, ok := keys[activeKeyID]
+	if !ok {
+		return nil, fmt.Errorf("active key %q not found", activeKeyID)
+	}
+	if active.signKey == nil {
+		return nil, fmt.Errorf("active key %q has no private key", activeKeyID)
+	}
+
+	list := make([]*Key, 0, len(keys))
+	for _, key := range keys {
+		list = append(list, key)
+	}
+	return newKeySet(active, list), nil
+}
+
+func newKeySet(active *Key, keys []*Key) *KeySet {
+	set := &KeySet{active: active, keys: make(map[string]*Key, len(keys))}
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		set.keys[key.ID] = key
+		if alg := key.Method.Alg(); !seen[alg] {
+			seen[alg] = true
+			set.methods = append(set.methods, alg)
+		}
+	}
+	sort.Strings(set.methods)
+	return set
+}
+
+// ActiveKeyID returns the kid used for newly signed tokens.
+func (s *KeySet) ActiveKeyID() string {
+	return s.active.ID
+}
+
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.active.Method, claims)
+	if s.active.ID != "" {
+		token.Header["kid"] = s.active.ID
+	}
+	return token.SignedString(s.active.signKey)
+}
+
+// Parse verifies tokenString with the key named by its kid header. The token
+// algorithm must match the key type, so a public key can never be used as an
+// HMAC secret.
+func (s *KeySet) Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, claims, s.keyFunc, jwt.WithValidMethods(s.methods))
+}
+
+func (s *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s for key %q", token.Method.Alg(), kid)
+	}
+	return key.verifyKey, nil
+}
+
+func parsePrivateKey(kid string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data")
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodRS256, signKey: key, verifyKey: &key.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodEdDSA, signKey: key, verifyKey: key.Public()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+}
+
+func parsePublicKey(kid string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data")
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PublicKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodRS256, verifyKey: key}, nil
+	case ed25519.PublicKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodEdDSA, verifyKey: key}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+}
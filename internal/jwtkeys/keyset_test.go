@@ -0,0 +1,186 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	assert.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+// writeKeys creates an RSA key "rsa-1", an Ed25519 key "ed-2" and a public-only
+// RSA key "old" in a temporary directory. It returns the directory and the
+// private part of "old" so tests can forge tokens signed before a rotation.
+func writeKeys(t *testing.T) (string, *rsa.PrivateKey) {
+	t.Helper()
+	dir := t.TempDir()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	writePEM(t, filepath.Join(dir, "rsa-1.pem"), "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(edKey)
+	assert.NoError(t, err)
+	writePEM(t, filepath.Join(dir, "ed-2.pem"), "PRIVATE KEY", der)
+
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	der, err = x509.MarshalPKIXPublicKey(&oldKey.PublicKey)
+	assert.NoError(t, err)
+	writePEM(t, filepath.Join(dir, "old.pub.pem"), "PUBLIC KEY", der)
+
+	return dir, oldKey
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{"role": "employee", "exp": time.Now().Add(time.Hour).Unix()}
+}
+
+func TestLoadKeySet(t *testing.T) {
+	dir, _ := writeKeys(t)
+
+	t.Run("active key required with several keys", func(t *testing.T) {
+		_, err := LoadKeySet(dir, "")
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown active key", func(t *testing.T) {
+		_, err := LoadKeySet(dir, "missing")
+		assert.Error(t, err)
+	})
+
+	t.Run("public-only active key", func(t *testing.T) {
+		_, err := LoadKeySet(dir, "old")
+		assert.Error(t, err)
+	})
+
+	t.Run("empty directory", func(t *testing.T) {
+		_, err := LoadKeySet(t.TempDir(), "")
+		assert.Error(t, err)
+	})
+
+	t.Run("falls back to HMAC without directory", func(t *testing.T) {
+		keys, err := Load("", "", "secret")
+		assert.NoError(t, err)
+
+		token, err := keys.Sign(validClaims())
+		assert.NoError(t, err)
+		_, err = jwt.Parse(token, func(*jwt.Token) (interface{}, error) { return []byte("secret"), nil })
+		assert.NoError(t, err)
+		assert.Empty(t, keys.JWKS().Keys)
+	})
+}
+
+func TestKeySet_SignAndParse(t *testing.T) {
+	dir, oldKey := writeKeys(t)
+
+	for _, kid := range []string{"rsa-1", "ed-2"} {
+		t.Run(kid, func(t *testing.T) {
+			keys, err := LoadKeySet(dir, kid)
+			assert.NoError(t, err)
+
+			token, err := keys.Sign(validClaims())
+			assert.NoError(t, err)
+
+			claims := jwt.MapClaims{}
+			parsed, err := keys.Parse(token, claims)
+			assert.NoError(t, err)
+			assert.Equal(t, kid, parsed.Header["kid"])
+			assert.Equal(t, "employee", claims["role"])
+		})
+	}
+
+	keys, err := LoadKeySet(dir, "ed-2")
+	assert.NoError(t, err)
+
+	t.Run("token signed by rotated key", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, validClaims())
+		token.Header["kid"] = "old"
+		signed, err := token.SignedString(oldKey)
+		assert.NoError(t, err)
+
+		_, err = keys.Parse(signed, jwt.MapClaims{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown kid", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, validClaims())
+		token.Header["kid"] = "missing"
+		signed, err := token.SignedString(oldKey)
+		assert.NoError(t, err)
+
+		_, err = keys.Parse(signed, jwt.MapClaims{})
+		assert.ErrorIs(t, err, ErrUnknownKeyID)
+	})
+
+	t.Run("missing kid", func(t *testing.T) {
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodRS256, validClaims()).SignedString(oldKey)
+		assert.NoError(t, err)
+
+		_, err = keys.Parse(signed, jwt.MapClaims{})
+		assert.ErrorIs(t, err, ErrUnknownKeyID)
+	})
+
+	t.Run("algorithm does not match key", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, validClaims())
+		token.Header["kid"] = "rsa-1"
+		_, edKey, err := ed25519.GenerateKey(rand.Reader)
+		assert.NoError(t, err)
+		signed, err := token.SignedString(edKey)
+		assert.NoError(t, err)
+
+		_, err = keys.Parse(signed, jwt.MapClaims{})
+		assert.Error(t, err)
+	})
+
+	t.Run("HMAC token signed with public key", func(t *testing.T) {
+		der, err := x509.MarshalPKIXPublicKey(&oldKey.PublicKey)
+		assert.NoError(t, err)
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims())
+		token.Header["kid"] = "old"
+		signed, err := token.SignedString(der)
+		assert.NoError(t, err)
+
+		_, err = keys.Parse(signed, jwt.MapClaims{})
+		assert.Error(t, err)
+	})
+}
+
+func TestKeySet_JWKS(t *testing.T) {
+	dir, _ := writeKeys(t)
+	keys, err := LoadKeySet(dir, "rsa-1")
+	assert.NoError(t, err)
+
+	jwks := keys.JWKS()
+
+	if !assert.Len(t, jwks.Keys, 3) {
+		return
+	}
+	assert.Equal(t, "ed-2", jwks.Keys[0].Kid)
+	assert.Equal(t, "OKP", jwks.Keys[0].Kty)
+	assert.Equal(t, "Ed25519", jwks.Keys[0].Crv)
+	assert.Equal(t, "EdDSA", jwks.Keys[0].Alg)
+	assert.NotEmpty(t, jwks.Keys[0].X)
+
+	assert.Equal(t, "old", jwks.Keys[1].Kid)
+	assert.Equal(t, "rsa-1", jwks.Keys[2].Kid)
+	assert.Equal(t, "RSA", jwks.Keys[2].Kty)
+	assert.Equal(t, "RS256", jwks.Keys[2].Alg)
+	assert.Equal(t, "AQAB", jwks.Keys[2].E)
+	assert.NotEmpty(t, jwks.Keys[2].N)
+}
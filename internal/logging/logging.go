@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"regexp"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the request id in HTTP headers and, lowercased, in
+// gRPC metadata.
+const RequestIDHeader = "X-Request-ID"
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID keeps an incoming request id if it is safe to log and echo back,
+// otherwise it generates a new one.
+func RequestID(incoming string) string {
+	if requestIDPattern.MatchString(incoming) {
+		return incoming
+	}
+	return uuid.NewString()
+}
+
+// New returns a JSON logger writing records at level and above. Level is one
+// of debug, info, warn and error; anything else means info.
+func New(w io.Writer, level string) *slog.Logger {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		lvl = slog.LevelInfo
+	}
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: lvl}))
+}
+
+type contextKey struct{}
+
+// scope is shared by a request's context and every context derived from it,
+// so fields added deep in the call, e.g. by the auth middleware, also show up
+// in the access log written once the request is done.
+type scope struct {
+	mu     sync.Mutex
+	logger *slog.Logger
+}
+
+// NewContext starts a request scope logging through logger.
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, &scope{logger: logger})
+}
+
+// FromContext returns the request's logger, or the default logger outside a
+// request scope.
+func FromContext(ctx context.Context) *slog.Logger {
+	if s, ok := ctx.Value(contextKey{}).(*scope); ok {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.logger
+	}
+	return slog.Default()
+}
+
+// AddFields attaches key-value pairs to every later record of the request.
+// Outside a request scope it does nothing.
+func AddFields(ctx context.Context, args ...any) {
+	if s, ok := ctx.Value(contextKey{}).(*scope); ok {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.logger = s.logger.With(args...)
+	}
+}
@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew_Level(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, "warn")
+
+	logger.Info("skipped")
+	logger.Warn("kept", "key", "value")
+
+	var record map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "kept", record["msg"])
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "value", record["key"])
+}
+
+func TestAddFields_SharedByDerivedContexts(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := NewContext(context.Background(), New(&buf, "info"))
+
+	child, cancel := context.WithCancel(ctx)
+	defer cancel()
+	AddFields(child, "userId", "user1")
+
+	FromContext(ctx).Info("done")
+
+	var record map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "user1", record["userId"])
+}
+
+func TestFromContext_OutsideScope(t *testing.T) {
+	AddFields(context.Background(), "ignored", true)
+	assert.NotNil(t, FromContext(context.Background()))
+}
+
+func TestRequestID(t *testing.T) {
+	assert.Equal(t, "abc-123", RequestID("abc-123"))
+
+	for _, incoming := range []string{"", "with space", "line\nbreak", string(make([]byte, 129))} {
+		generated := RequestID(incoming)
+		assert.NotEqual(t, incoming, generated)
+		assert.Len(t, generated, 36)
+	}
+}
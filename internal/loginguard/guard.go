@@ -0,0 +1,111 @@
+package loginguard
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"pvz-service/internal/domain"
+)
+
+// Store keeps failed login counters. Keys are opaque to the store.
+type Store interface {
+	// LockedUntil returns the end of the key's lockout, or the zero time if the key is not locked.
+	LockedUntil(ctx context.Context, key string, now time.Time) (time.Time, error)
+	// AddFailure records a failure and returns the number of failures since windowStart.
+	// Counters that started before windowStart are reset.
+	AddFailure(ctx context.Context, key string, now, windowStart time.Time) (int, error)
+	// Lock blocks the key until the given time and resets its counter.
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+}
+
+type Config struct {
+	MaxAttempts      int
+	MaxAttemptsPerIP int
+	Window           time.Duration
+	Lockout          time.Duration
+}
+
+// Guard limits failed logins per email and per client IP. Reaching the email limit
+// locks the account, reaching the IP limit blocks the address; both last Lockout.
+type Guard struct {
+	store Store
+	cfg   Config
+	now   func() time.Time
+}
+
+func NewGuard(store Store, cfg Config) *Guard {
+	return &Guard{store: store, cfg: cfg, now: time.Now}
+}
+
+// Check returns a *domain.LockoutError if logins for the email or from the IP are blocked.
+func (g *Guard) Check(ctx context.Context, email, ip string) error {
+	now := g.now()
+
+	if err := g.checkKey(ctx, emailKey(email), domain.ErrAccountLocked, now); err != nil {
+		return err
+	}
+	if ip == "" {
+		return nil
+	}
+	return g.checkKey(ctx, ipKey(ip), domain.ErrTooManyAttempts, now)
+}
+
+// Failure records a failed login and returns a *domain.LockoutError if it used up
+// the last allowed attempt.
+func (g *Guard) Failure(ctx context.Context, email, ip string) error {
+	now := g.now()
+
+	if err := g.addFailure(ctx, emailKey(email), g.cfg.MaxAttempts, domain.ErrAccountLocked, now); err != nil {
+		return err
+	}
+	if ip == "" {
+		return nil
+	}
+	return g.addFailure(ctx, ipKey(ip), g.cfg.MaxAttemptsPerIP, domain.ErrTooManyAttempts, now)
+}
+
+// Success clears the email's counter. The IP counter is kept so that a valid
+// account can't be used to reset it.
+func (g *Guard) Success(ctx context.Context, email string) error {
+	return g.store.Reset(ctx, emailKey(email))
+}
+
+func (g *Guard) checkKey(ctx context.Context, key string, reason error, now time.Time) error {
+	lockedUntil, err := g.store.LockedUntil(ctx, key, now)
+	if err != nil {
+		return err
+	}
+	if lockedUntil.After(now) {
+		return &domain.LockoutError{Err: reason, RetryAfter: lockedUntil.Sub(now)}
+	}
+	return nil
+}
+
+func (g *Guard) addFailure(ctx context.Context, key string, limit int, reason error, now time.Time) error {
+	if limit <= 0 {
+		return nil
+	}
+
+	failures, err := g.store.AddFailure(ctx, key, now, now.Add(-g.cfg.Window))
+	if err != nil {
+		return err
+	}
+	if failures < limit {
+		return nil
+	}
+
+	if err := g.store.Lock(ctx, key, now.Add(g.cfg.Lockout)); err != nil {
+		return err
+	}
+	return &domain.LockoutError{Err: reason, RetryAfter: g.cfg.Lockout}
+}
+
+func emailKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
@@ -0,0 +1,80 @@
+package loginguard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/domain"
+)
+
+func newTestGuard(now *time.Time) *Guard {
+	guard := NewGuard(NewMemoryStore(), Config{
+		MaxAttempts:      3,
+		MaxAttemptsPerIP: 5,
+		Window:           10 * time.Minute,
+		Lockout:          15 * time.Minute,
+	})
+	guard.now = func() time.Time { return *now }
+	return guard
+}
+
+func TestGuard_LocksAccount(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	guard := newTestGuard(&now)
+
+	assert.NoError(t, guard.Failure(ctx, "user@example.com", "10.0.0.1"))
+	assert.NoError(t, guard.Failure(ctx, "User@Example.com", "10.0.0.2"))
+
+	err := guard.Failure(ctx, "user@example.com", "10.0.0.3")
+	var lockout *domain.LockoutError
+	if assert.ErrorAs(t, err, &lockout) {
+		assert.ErrorIs(t, err, domain.ErrAccountLocked)
+		assert.Equal(t, 15*time.Minute, lockout.RetryAfter)
+	}
+
+	now = now.Add(5 * time.Minute)
+	err = guard.Check(ctx, "user@example.com", "10.0.0.4")
+	if assert.ErrorAs(t, err, &lockout) {
+		assert.Equal(t, 10*time.Minute, lockout.RetryAfter)
+	}
+	assert.NoError(t, guard.Check(ctx, "other@example.com", "10.0.0.4"))
+
+	now = now.Add(10 * time.Minute)
+	assert.NoError(t, guard.Check(ctx, "user@example.com", "10.0.0.4"))
+}
+
+func TestGuard_BlocksIP(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	guard := newTestGuard(&now)
+
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, guard.Failure(ctx, string(rune('a'+i))+"@example.com", "10.0.0.1"))
+	}
+	assert.ErrorIs(t, guard.Failure(ctx, "e@example.com", "10.0.0.1"), domain.ErrTooManyAttempts)
+
+	assert.ErrorIs(t, guard.Check(ctx, "f@example.com", "10.0.0.1"), domain.ErrTooManyAttempts)
+	assert.NoError(t, guard.Check(ctx, "f@example.com", "10.0.0.2"))
+}
+
+func TestGuard_WindowAndSuccess(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	guard := newTestGuard(&now)
+
+	assert.NoError(t, guard.Failure(ctx, "user@example.com", ""))
+	assert.NoError(t, guard.Failure(ctx, "user@example.com", ""))
+
+	now = now.Add(11 * time.Minute)
+	assert.NoError(t, guard.Failure(ctx, "user@example.com", ""))
+	assert.NoError(t, guard.Failure(ctx, "user@example.com", ""))
+
+	assert.NoError(t, guard.Success(ctx, "user@example.com"))
+	assert.NoError(t, guard.Failure(ctx, "user@example.com", ""))
+	assert.NoError(t, guard.Failure(ctx, "user@example.com", ""))
+	assert.NoError(t, guard.Check(ctx, "user@example.com", ""))
+}
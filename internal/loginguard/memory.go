@@ -0,0 +1,85 @@
+package loginguard
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps counters in process memory. Counters are lost on restart and
+// are not shared between instances.
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryEntry
+	lastPurge time.Time
+}
+
+type memoryEntry struct {
+	failures      int
+	windowStarted time.Time
+	lockedUntil   time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]*memoryEntry)}
+}
+
+func (s *MemoryStore) LockedUntil(ctx context.Context, key string, now time.Time) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.entries[key]; ok {
+		return entry.lockedUntil, nil
+	}
+	return time.Time{}, nil
+}
+
+func (s *MemoryStore) AddFailure(ctx context.Context, key string, now, windowStart time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Drop stale entries at most once per window so the map doesn't grow unbounded.
+	if s.lastPurge.Before(windowStart) {
+		for k, entry := range s.entries {
+			if entry.windowStarted.Before(windowStart) && !entry.lockedUntil.After(now) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastPurge = now
+	}
+
+	entry, ok := s.entries[key]
+	if !ok {
+		entry = &memoryEntry{windowStarted: now}
+		s.entries[key] = entry
+	}
+	if entry.windowStarted.Before(windowStart) {
+		entry.failures = 0
+		entry.windowStarted = now
+	}
+
+	entry.failures++
+	return entry.failures, nil
+}
+
+func (s *MemoryStore) Lock(ctx context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		entry = &memoryEntry{}
+		s.entries[key] = entry
+	}
+	entry.failures = 0
+	entry.lockedUntil = until
+	return nil
+}
+
+func (s *MemoryStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
@@ -1,16 +1,40 @@
 package middleware
 
 import (
+	"context"
 	"errors"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"pvz-service/internal/domain"
 	"pvz-service/internal/handler/models"
+	"pvz-service/internal/jwtkeys"
+	"pvz-service/internal/logging"
 	"strings"
 )
 
-func AuthMiddleware(secret string) fiber.Handler {
+// TokenRevocationChecker also reports disabled users; their tokens are treated
+// as revoked.
+type TokenRevocationChecker interface {
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	IsUserDisabled(ctx context.Context, userID string) (bool, error)
+}
+
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (domain.APIKey, error)
+}
+
+// AuthMiddleware accepts a Bearer token or, when apiKeys is set and there is no
+// Authorization header, an X-API-Key header. API key requests get claims with the
+// key's role, apiKeyId and pvzIds instead of userId.
+func AuthMiddleware(
+	keys *jwtkeys.KeySet, revocations TokenRevocationChecker, apiKeys APIKeyAuthenticator,
+) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		authHeader := c.Get("Authorization")
+		if authHeader == "" && apiKeys != nil && c.Get("X-API-Key") != "" {
+			return authenticateAPIKey(c, apiKeys)
+		}
 		if authHeader == "" {
 			return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{Message: "Missing authorization header"})
 		}
@@ -18,9 +42,7 @@ func AuthMiddleware(secret string) fiber.Handler {
 		tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
 
 		claims := jwt.MapClaims{}
-		tkn, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(secret), nil
-		})
+		tkn, err := keys.Parse(tokenString, claims)
 
 		if err != nil {
 			if errors.Is(err, jwt.ErrTokenExpired) && !tkn.Valid {
@@ -29,11 +51,53 @@ func AuthMiddleware(secret string) fiber.Handler {
 			return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{Message: "Invalid token"})
 		}
 
+		if jti, ok := claims["jti"].(string); ok && revocations != nil {
+			revoked, err := revocations.IsTokenRevoked(c.UserContext(), jti)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{Message: "Failed to check token"})
+			}
+			if revoked {
+				return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{Message: "Token revoked"})
+			}
+		}
+
+		if userID, ok := claims["userId"].(string); ok && revocations != nil {
+			if _, err := uuid.Parse(userID); err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{Message: "Invalid token"})
+			}
+			disabled, err := revocations.IsUserDisabled(c.UserContext(), userID)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{Message: "Failed to check token"})
+			}
+			if disabled {
+				return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{Message: "User disabled"})
+			}
+		}
+
 		c.Locals("claims", claims)
+		logging.AddFields(c.UserContext(), "userId", claims["userId"], "role", claims["role"])
 		return c.Next()
 	}
 }
 
+func authenticateAPIKey(c *fiber.Ctx, apiKeys APIKeyAuthenticator) error {
+	apiKey, err := apiKeys.AuthenticateAPIKey(c.UserContext(), c.Get("X-API-Key"))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAPIKey) {
+			return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{Message: "Invalid API key"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{Message: "Failed to check API key"})
+	}
+
+	c.Locals("claims", jwt.MapClaims{
+		"role":     apiKey.Role,
+		"apiKeyId": apiKey.ID,
+		"pvzIds":   apiKey.PVZIDs,
+	})
+	logging.AddFields(c.UserContext(), "apiKeyId", apiKey.ID, "role", apiKey.Role)
+	return c.Next()
+}
+
 func CheckRole(roles ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		claims := c.Locals("claims").(jwt.MapClaims)
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/jwtkeys"
+)
+
+type revokedTokens map[string]bool
+
+func (r revokedTokens) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	if jti == "broken" {
+		return false, errors.New("database error")
+	}
+	return r[jti], nil
+}
+
+func (r revokedTokens) IsUserDisabled(ctx context.Context, userID string) (bool, error) {
+	return r[userID], nil
+}
+
+func TestAuthMiddleware_Revocation(t *testing.T) {
+	app := fiber.New()
+	app.Use(AuthMiddleware(jwtkeys.NewHMACKeySet("secret"), revokedTokens{"revoked": true}, nil))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	request := func(claims jwt.MapClaims) int {
+		claims["role"] = "employee"
+		claims["exp"] = time.Now().Add(time.Hour).Unix()
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, fiber.StatusOK, request(jwt.MapClaims{"jti": "active"}))
+	assert.Equal(t, fiber.StatusOK, request(jwt.MapClaims{}))
+	assert.Equal(t, fiber.StatusUnauthorized, request(jwt.MapClaims{"jti": "revoked"}))
+	assert.Equal(t, fiber.StatusInternalServerError, request(jwt.MapClaims{"jti": "broken"}))
+}
+
+func TestAuthMiddleware_DisabledUser(t *testing.T) {
+	const disabledUser = "00000000-0000-0000-0000-000000000001"
+
+	app := fiber.New()
+	app.Use(AuthMiddleware(jwtkeys.NewHMACKeySet("secret"), revokedTokens{disabledUser: true}, nil))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	request := func(userID string) int {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"userId": userID, "role": "employee", "exp": time.Now().Add(time.Hour).Unix(),
+		}).SignedString([]byte("secret"))
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, fiber.StatusOK, request("00000000-0000-0000-0000-000000000002"))
+	assert.Equal(t, fiber.StatusUnauthorized, request(disabledUser))
+	assert.Equal(t, fiber.StatusUnauthorized, request("not-a-uuid"))
+}
+
+type apiKeys map[string]domain.APIKey
+
+func (a apiKeys) AuthenticateAPIKey(ctx context.Context, key string) (domain.APIKey, error) {
+	if key == "pvz_broken" {
+		return domain.APIKey{}, errors.New("database error")
+	}
+	apiKey, ok := a[key]
+	if !ok {
+		return domain.APIKey{}, domain.ErrInvalidAPIKey
+	}
+	return apiKey, nil
+}
+
+func TestAuthMiddleware_APIKey(t *testing.T) {
+	app := fiber.New()
+	app.Use(AuthMiddleware(jwtkeys.NewHMACKeySet("secret"), revokedTokens{}, apiKeys{
+		"pvz_valid": {ID: "key1", Role: "moderator", PVZIDs: []string{}},
+	}))
+	app.Get("/", CheckRole("moderator"), func(c *fiber.Ctx) error {
+		claims := c.Locals("claims").(jwt.MapClaims)
+		return c.SendString(claims["apiKeyId"].(string))
+	})
+
+	request := func(key string) (int, string) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("X-API-Key", key)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	status, body := request("pvz_valid")
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Equal(t, "key1", body)
+
+	status, _ = request("pvz_unknown")
+	assert.Equal(t, fiber.StatusUnauthorized, status)
+
+	status, _ = request("pvz_broken")
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+
+	status, _ = request("")
+	assert.Equal(t, fiber.StatusUnauthorized, status)
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"pvz-service/internal/logging"
+)
+
+// RequestLogger assigns the request id, honouring a well-formed incoming
+// X-Request-ID, puts a logger carrying it into the user context and writes an
+// access log line once the request is done. Fields added later through
+// logging.AddFields, such as the user from AuthMiddleware, end up in that line.
+func RequestLogger(logger *slog.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID := logging.RequestID(c.Get(logging.RequestIDHeader))
+		c.Set(logging.RequestIDHeader, requestID)
+
+		ctx := logging.NewContext(c.UserContext(), logger.With("requestId", requestID))
+		c.SetUserContext(ctx)
+
+		start := time.Now()
+		if err := c.Next(); err != nil {
+			// Let the error handler set the status now so the log line has it.
+			if err := c.App().Config().ErrorHandler(c, err); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+		level := slog.LevelInfo
+		if status >= fiber.StatusInternalServerError {
+			level = slog.LevelError
+		}
+
+		args := []any{
+			"method", c.Method(),
+			"route", c.Route().Path,
+			"path", c.Path(),
+			"status", status,
+			"latency", time.Since(start),
+		}
+		if pvzID := c.Params("pvzId"); pvzID != "" {
+			args = append(args, "pvzId", pvzID)
+		}
+		logging.FromContext(ctx).Log(ctx, level, "request", args...)
+		return nil
+	}
+}
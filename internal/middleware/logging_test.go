@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/logging"
+)
+
+func TestRequestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	app := fiber.New()
+	app.Use(RequestLogger(logging.New(&buf, "info")))
+	app.Post("/pvz/:pvzId/close_last_reception", func(c *fiber.Ctx) error {
+		logging.AddFields(c.UserContext(), "userId", "user1")
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to close reception")
+	})
+
+	req := httptest.NewRequest("POST", "/pvz/pvz1/close_last_reception", nil)
+	req.Header.Set(logging.RequestIDHeader, "req-1")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, "req-1", resp.Header.Get(logging.RequestIDHeader))
+
+	var record map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, "req-1", record["requestId"])
+	assert.Equal(t, "user1", record["userId"])
+	assert.Equal(t, "pvz1", record["pvzId"])
+	assert.Equal(t, "/pvz/:pvzId/close_last_reception", record["route"])
+	assert.Equal(t, float64(fiber.StatusInternalServerError), record["status"])
+}
+
+func TestRequestLogger_GeneratesRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	app := fiber.New()
+	app.Use(RequestLogger(logging.New(&buf, "info")))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(logging.RequestIDHeader, "not a valid id")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+
+	requestID := resp.Header.Get(logging.RequestIDHeader)
+	assert.Len(t, requestID, 36)
+
+	var record map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "INFO", record["level"])
+	assert.Equal(t, requestID, record["requestId"])
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/handler/models"
+	"pvz-service/internal/logging"
+)
+
+type PVZAccessChecker interface {
+	IsAssigned(ctx context.Context, userID, pvzID string) (bool, error)
+}
+
+// RequirePVZAccess rejects requests for a PVZ the caller is not assigned to. The PVZ
+// id is taken from the pvzId route parameter or, failing that, from the pvzId field
+// of the JSON body. Malformed ids are left for the handler to reject.
+func RequirePVZAccess(checker PVZAccessChecker) fiber.Handler {
+	check := CheckPVZAccess(checker)
+	return func(c *fiber.Ctx) error {
+		pvzID := c.Params("pvzId")
+		if pvzID == "" {
+			var body struct {
+				PvzId string `json:"pvzId"`
+			}
+			if err := c.BodyParser(&body); err != nil {
+				return c.Next()
+			}
+			pvzID = body.PvzId
+			logging.AddFields(c.UserContext(), "pvzId", pvzID)
+		}
+		if _, err := uuid.Parse(pvzID); err != nil {
+			return c.Next()
+		}
+
+		if ok, err := check(c, pvzID); !ok {
+			return err
+		}
+		return c.Next()
+	}
+}
+
+// CheckPVZAccess returns the check behind RequirePVZAccess for handlers that only
+// learn the PVZ id after loading a resource. The check sends the error response
+// and returns false when access is denied. Only employees are limited to their
+// assignments; API keys are checked against their own PVZ list, an empty list
+// allows every PVZ.
+func CheckPVZAccess(checker PVZAccessChecker) func(c *fiber.Ctx, pvzID string) (bool, error) {
+	return func(c *fiber.Ctx, pvzID string) (bool, error) {
+		claims := c.Locals("claims").(jwt.MapClaims)
+		if _, ok := claims["apiKeyId"]; ok {
+			if !apiKeyAllowsPVZ(claims, pvzID) {
+				return false, c.Status(fiber.StatusForbidden).JSON(models.ErrorResponse{
+					Message: "PVZ is not allowed for the API key",
+				})
+			}
+			return true, nil
+		}
+
+		if role, _ := claims["role"].(string); role != domain.RoleEmployee {
+			return true, nil
+		}
+
+		userID, _ := claims["userId"].(string)
+		if _, err := uuid.Parse(userID); err != nil {
+			return false, c.Status(fiber.StatusForbidden).JSON(models.ErrorResponse{
+				Message: "PVZ is not assigned to the user",
+			})
+		}
+
+		assigned, err := checker.IsAssigned(c.UserContext(), userID, pvzID)
+		if err != nil {
+			return false, c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+				Message: "Failed to check PVZ access",
+			})
+		}
+		if !assigned {
+			return false, c.Status(fiber.StatusForbidden).JSON(models.ErrorResponse{
+				Message: "PVZ is not assigned to the user",
+			})
+		}
+		return true, nil
+	}
+}
+
+func apiKeyAllowsPVZ(claims jwt.MapClaims, pvzID string) bool {
+	pvzIDs, _ := claims["pvzIds"].([]string)
+	if len(pvzIDs) == 0 {
+		return true
+	}
+	for _, allowed := range pvzIDs {
+		if allowed == pvzID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	assignedPVZ   = "11111111-1111-1111-1111-111111111111"
+	unassignedPVZ = "22222222-2222-2222-2222-222222222222"
+	brokenPVZ     = "33333333-3333-3333-3333-333333333333"
+	employeeID    = "44444444-4444-4444-4444-444444444444"
+)
+
+type assignedPVZs map[string]bool
+
+func (a assignedPVZs) IsAssigned(ctx context.Context, userID, pvzID string) (bool, error) {
+	if pvzID == brokenPVZ {
+		return false, errors.New("database error")
+	}
+	return userID == employeeID && a[pvzID], nil
+}
+
+func TestRequirePVZAccess(t *testing.T) {
+	app := fiber.New()
+	withClaims := func(c *fiber.Ctx) error {
+		c.Locals("claims", jwt.MapClaims{"userId": c.Get("X-User"), "role": c.Get("X-Role", "employee")})
+		return c.Next()
+	}
+	access := RequirePVZAccess(assignedPVZs{assignedPVZ: true})
+	app.Post("/pvz/:pvzId/close_last_reception", withClaims, access, func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Post("/receptions", withClaims, access, func(c *fiber.Ctx) error {
+		var body struct {
+			PvzId string `json:"pvzId"`
+		}
+		if err := c.BodyParser(&body); err != nil {
+			return c.SendStatus(fiber.StatusBadRequest)
+		}
+		return c.SendString(body.PvzId)
+	})
+
+	request := func(path, body, userID string) (int, string) {
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User", userID)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+
+	t.Run("assigned pvz in path", func(t *testing.T) {
+		status, _ := request("/pvz/"+assignedPVZ+"/close_last_reception", "", employeeID)
+		assert.Equal(t, fiber.StatusOK, status)
+	})
+
+	t.Run("unassigned pvz in path", func(t *testing.T) {
+		status, _ := request("/pvz/"+unassignedPVZ+"/close_last_reception", "", employeeID)
+		assert.Equal(t, fiber.StatusForbidden, status)
+	})
+
+	t.Run("assigned pvz in body is still readable by the handler", func(t *testing.T) {
+		status, body := request("/receptions", `{"pvzId":"`+assignedPVZ+`"}`, employeeID)
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, assignedPVZ, body)
+	})
+
+	t.Run("unassigned pvz in body", func(t *testing.T) {
+		status, _ := request("/receptions", `{"pvzId":"`+unassignedPVZ+`"}`, employeeID)
+		assert.Equal(t, fiber.StatusForbidden, status)
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		status, _ := request("/receptions", `{"pvzId":"`+assignedPVZ+`"}`, "55555555-5555-5555-5555-555555555555")
+		assert.Equal(t, fiber.StatusForbidden, status)
+	})
+
+	t.Run("moderator is not limited to assignments", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/pvz/"+unassignedPVZ+"/close_last_reception", nil)
+		req.Header.Set("X-User", "55555555-5555-5555-5555-555555555555")
+		req.Header.Set("X-Role", "moderator")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("token without user id", func(t *testing.T) {
+		status, _ := request("/receptions", `{"pvzId":"`+assignedPVZ+`"}`, "")
+		assert.Equal(t, fiber.StatusForbidden, status)
+	})
+
+	t.Run("malformed pvz id is left to the handler", func(t *testing.T) {
+		status, body := request("/receptions", `{"pvzId":"invalid"}`, employeeID)
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, "invalid", body)
+	})
+
+	t.Run("checker error", func(t *testing.T) {
+		status, _ := request("/receptions", `{"pvzId":"`+brokenPVZ+`"}`, employeeID)
+		assert.Equal(t, fiber.StatusInternalServerError, status)
+	})
+}
+
+func TestRequirePVZAccess_APIKey(t *testing.T) {
+	app := fiber.New()
+	withKey := func(c *fiber.Ctx) error {
+		pvzIDs := []string{}
+		if scope := c.Get("X-Scope"); scope != "" {
+			pvzIDs = strings.Split(scope, ",")
+		}
+		c.Locals("claims", jwt.MapClaims{"apiKeyId": "key1", "role": "employee", "pvzIds": pvzIDs})
+		return c.Next()
+	}
+	app.Post("/pvz/:pvzId/close_last_reception", withKey, RequirePVZAccess(assignedPVZs{}), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	request := func(pvzID, scope string) int {
+		req := httptest.NewRequest("POST", "/pvz/"+pvzID+"/close_last_reception", nil)
+		req.Header.Set("X-Scope", scope)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, fiber.StatusOK, request(unassignedPVZ, ""))
+	assert.Equal(t, fiber.StatusOK, request(assignedPVZ, assignedPVZ))
+	assert.Equal(t, fiber.StatusForbidden, request(unassignedPVZ, assignedPVZ))
+}
@@ -0,0 +1,223 @@
+package migrate
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/migrations"
+)
+
+var testFS = fstest.MapFS{
+	"0002_add_notes.up.sql":   {Data: []byte("CREATE TABLE notes (id INT)")},
+	"0002_add_notes.down.sql": {Data: []byte("DROP TABLE notes")},
+	"0001_init.up.sql":        {Data: []byte("CREATE TABLE users (id INT)")},
+	"0001_init.down.sql":      {Data: []byte("DROP TABLE users")},
+	"migrations.go":           {Data: []byte("package migrations")},
+}
+
+func expectLock(mock sqlmock.Sqlmock, applied ...int64) {
+	mock.ExpectExec("SELECT pg_advisory_lock").WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rows := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, version := range applied {
+		rows.AddRow(version, time.Now())
+	}
+	mock.ExpectQuery("SELECT version, applied_at FROM schema_migrations").WillReturnRows(rows)
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestLoad(t *testing.T) {
+	migrations, err := Load(testFS)
+	assert.NoError(t, err)
+	assert.Len(t, migrations, 2)
+	assert.Equal(t, int64(1), migrations[0].Version)
+	assert.Equal(t, "init", migrations[0].Name)
+	assert.Equal(t, "DROP TABLE users", migrations[0].Down)
+	assert.Equal(t, int64(2), migrations[1].Version)
+}
+
+func TestLoad_MissingDown(t *testing.T) {
+	_, err := Load(fstest.MapFS{"0001_init.up.sql": {Data: []byte("SELECT 1")}})
+	assert.Error(t, err)
+}
+
+func TestMigrator_Up(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := New(db, testFS)
+	assert.NoError(t, err)
+
+	expectLock(mock, 1)
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE notes").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO schema_migrations").
+		WithArgs(int64(2), "add_notes").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlock(mock)
+
+	assert.NoError(t, migrator.Up(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Up_FailureRollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := New(db, testFS)
+	assert.NoError(t, err)
+
+	expectLock(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE users").WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+	expectUnlock(mock)
+
+	err = migrator.Up(context.Background())
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := New(db, testFS)
+	assert.NoError(t, err)
+
+	expectLock(mock, 1, 2)
+	mock.ExpectBegin()
+	mock.ExpectExec("DROP TABLE notes").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM schema_migrations").WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlock(mock)
+
+	assert.NoError(t, migrator.Down(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_ToZero(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := New(db, testFS)
+	assert.NoError(t, err)
+
+	expectLock(mock, 1, 2)
+	for _, stmt := range []string{"DROP TABLE notes", "DROP TABLE users"} {
+		mock.ExpectBegin()
+		mock.ExpectExec(stmt).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("DELETE FROM schema_migrations").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}
+	expectUnlock(mock)
+
+	assert.NoError(t, migrator.To(context.Background(), 0))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_To_UnknownVersion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := New(db, testFS)
+	assert.NoError(t, err)
+
+	err = migrator.To(context.Background(), 7)
+	assert.ErrorIs(t, err, ErrUnknownVersion)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Status(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := New(db, testFS)
+	assert.NoError(t, err)
+
+	expectLock(mock, 1)
+	expectUnlock(mock)
+
+	statuses, err := migrator.Status(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 2)
+	assert.NotNil(t, statuses[0].AppliedAt)
+	assert.Nil(t, statuses[1].AppliedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_CheckApplied(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := New(db, testFS)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), migrator.Latest())
+
+	for _, version := range []int64{1, 2, 3} {
+		mock.ExpectQuery("SELECT COALESCE").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(version))
+	}
+
+	assert.EqualError(t, migrator.CheckApplied(context.Background()), "schema version 1, want 2")
+	assert.NoError(t, migrator.CheckApplied(context.Background()))
+	assert.NoError(t, migrator.CheckApplied(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrations_UpgradeFromBaseline(t *testing.T) {
+	all, err := Load(migrations.FS)
+	assert.NoError(t, err)
+	assert.Greater(t, len(all), 1)
+
+	// 0001 must stay the schema of the original init.sql so existing databases upgrade through 0002+.
+	baseline := all[0]
+	assert.Equal(t, int64(1), baseline.Version)
+	assert.NotContains(t, baseline.Up, "ALTER")
+	assert.NotContains(t, baseline.Up, "admin")
+	assert.NotContains(t, baseline.Up, "cities")
+	assert.NotContains(t, baseline.Up, "product_types")
+	assert.Contains(t, baseline.Up, "CHECK (city IN ('Москва', 'Санкт-Петербург', 'Казань'))")
+	assert.Contains(t, baseline.Up, "CHECK (type IN ('электроника', 'одежда', 'обувь'))")
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := New(db, migrations.FS)
+	assert.NoError(t, err)
+
+	expectLock(mock, 1)
+	for _, migration := range all[1:] {
+		assert.NotEmpty(t, strings.TrimSpace(migration.Down), "migration %d has an empty down", migration.Version)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(migration.Up)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INSERT INTO schema_migrations").
+			WithArgs(migration.Version, migration.Name).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}
+	expectUnlock(mock)
+
+	assert.NoError(t, migrator.Up(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"pvz-service/internal/logging"
+)
+
+// Notifier delivers messages to users. Real delivery (email, SMS) plugs in here;
+// LogNotifier and FileNotifier are stubs for local use.
+type Notifier interface {
+	SendPasswordReset(ctx context.Context, email, token string) error
+}
+
+// LogNotifier writes messages, including the reset token, to the request logger.
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) SendPasswordReset(ctx context.Context, email, token string) error {
+	logging.FromContext(ctx).InfoContext(ctx, "Password reset requested", "email", email, "token", token)
+	return nil
+}
+
+// FileNotifier appends messages to a file as JSON lines.
+type FileNotifier struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{path: path}
+}
+
+type message struct {
+	Type   string    `json:"type"`
+	To     string    `json:"to"`
+	Token  string    `json:"token"`
+	SentAt time.Time `json:"sentAt"`
+}
+
+func (n *FileNotifier) SendPasswordReset(ctx context.Context, email, token string) error {
+	line, err := json.Marshal(message{Type: "password_reset", To: email, Token: token, SentAt: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	file, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,29 @@
+package notify
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileNotifier_SendPasswordReset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	notifier := NewFileNotifier(path)
+
+	assert.NoError(t, notifier.SendPasswordReset(context.Background(), "a@example.com", "token1"))
+	assert.NoError(t, notifier.SendPasswordReset(context.Background(), "b@example.com", "token2"))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if !assert.Len(t, lines, 2) {
+		return
+	}
+	assert.Contains(t, lines[0], `"to":"a@example.com"`)
+	assert.Contains(t, lines[1], `"token":"token2"`)
+}
@@ -0,0 +1,109 @@
+package passwordpolicy
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"pvz-service/internal/domain"
+)
+
+// maxLength is the bcrypt input limit in bytes.
+const maxLength = 72
+
+// Policy validates new passwords. The character classes are lowercase and uppercase
+// letters, digits and everything else.
+type Policy struct {
+	minLength  int
+	minClasses int
+	denylist   map[string]struct{}
+}
+
+func New(minLength, minClasses int, denylist []string) *Policy {
+	p := &Policy{
+		minLength:  minLength,
+		minClasses: minClasses,
+		denylist:   make(map[string]struct{}, len(denylist)),
+	}
+	for _, password := range denylist {
+		p.denylist[strings.ToLower(password)] = struct{}{}
+	}
+	return p
+}
+
+// Load builds a policy with the denylist read from path. An empty path means no denylist.
+func Load(minLength, minClasses int, path string) (*Policy, error) {
+	if path == "" {
+		return New(minLength, minClasses, nil), nil
+	}
+
+	denylist, err := readDenylist(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read password denylist: %w", err)
+	}
+	return New(minLength, minClasses, denylist), nil
+}
+
+// Validate returns an error wrapping domain.ErrWeakPassword that says which rule failed.
+func (p *Policy) Validate(password string) error {
+	if len([]rune(password)) < p.minLength {
+		return fmt.Errorf("%w: must be at least %d characters", domain.ErrWeakPassword, p.minLength)
+	}
+	if len(password) > maxLength {
+		return fmt.Errorf("%w: must be at most %d bytes", domain.ErrWeakPassword, maxLength)
+	}
+	if classes(password) < p.minClasses {
+		return fmt.Errorf("%w: must contain at least %d of lowercase, uppercase, digits and symbols",
+			domain.ErrWeakPassword, p.minClasses)
+	}
+	if _, ok := p.denylist[strings.ToLower(password)]; ok {
+		return fmt.Errorf("%w: password is too common", domain.ErrWeakPassword)
+	}
+	return nil
+}
+
+func classes(password string) int {
+	var lower, upper, digit, other bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+
+	count := 0
+	for _, present := range []bool{lower, upper, digit, other} {
+		if present {
+			count++
+		}
+	}
+	return count
+}
+
+// readDenylist reads one password per line, skipping blank lines and lines starting with #.
+func readDenylist(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var denylist []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		denylist = append(denylist, line)
+	}
+	return denylist, scanner.Err()
+}
@@ -0,0 +1,53 @@
+package passwordpolicy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/domain"
+)
+
+func TestPolicy_Validate(t *testing.T) {
+	policy := New(8, 2, []string{"Password1"})
+
+	tests := []struct {
+		name     string
+		password string
+		valid    bool
+	}{
+		{"valid", "correct-horse", true},
+		{"too short", "ab1", false},
+		{"too long", strings.Repeat("a1", 40), false},
+		{"single class", "abcdefghij", false},
+		{"denylisted ignoring case", "password1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Validate(tt.password)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, domain.ErrWeakPassword)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "denylist.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("# common passwords\n\nqwerty123\n  letmein1  \n"), 0o600))
+
+	policy, err := Load(6, 1, path)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, policy.Validate("qwerty123"), domain.ErrWeakPassword)
+	assert.ErrorIs(t, policy.Validate("LETMEIN1"), domain.ErrWeakPassword)
+	assert.NoError(t, policy.Validate("letmein2"))
+
+	_, err = Load(6, 1, filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
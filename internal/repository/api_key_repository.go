@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/utils"
+)
+
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key domain.APIKey, keyHash string) (domain.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string, now time.Time) error
+	UseAPIKey(ctx context.Context, keyHash string, now time.Time) (domain.APIKey, error)
+}
+
+type APIKeyRepositoryImpl struct {
+	db DBTX
+}
+
+func NewAPIKeyRepository(db DBTX) *APIKeyRepositoryImpl {
+	return &APIKeyRepositoryImpl{db: db}
+}
+
+const apiKeyColumns = `id, name, prefix, role,
+	ARRAY(SELECT pvz_id FROM api_key_pvzs WHERE api_key_id = api_keys.id ORDER BY pvz_id),
+	created_by, created_at, expires_at, last_used_at, revoked_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAPIKey(row rowScanner) (domain.APIKey, error) {
+	var key domain.APIKey
+	var createdBy sql.NullString
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+	err := row.Scan(&key.ID, &key.Name, &key.Prefix, &key.Role, pq.Array(&key.PVZIDs),
+		&createdBy, &key.CreatedAt, &expiresAt, &lastUsedAt, &revokedAt)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+
+	if key.PVZIDs == nil {
+		key.PVZIDs = []string{}
+	}
+	if createdBy.Valid {
+		key.CreatedBy = &createdBy.String
+	}
+	key.ExpiresAt = utils.NullableTime(expiresAt)
+	key.LastUsedAt = utils.NullableTime(lastUsedAt)
+	key.RevokedAt = utils.NullableTime(revokedAt)
+	return key, nil
+}
+
+// CreateAPIKey stores the key and its PVZ bindings in a single statement. Unknown
+// PVZ ids fail with domain.ErrPVZNotFound.
+func (r *APIKeyRepositoryImpl) CreateAPIKey(
+	ctx context.Context, key domain.APIKey, keyHash string) (domain.APIKey, error) {
+	var createdBy interface{}
+	if key.CreatedBy != nil {
+		createdBy = *key.CreatedBy
+	}
+
+	err := r.db.QueryRowContext(ctx,
+		`WITH k AS (
+			INSERT INTO api_keys (name, prefix, key_hash, role, created_by, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id, created_at
+		), p AS (
+			INSERT INTO api_key_pvzs (api_key_id, pvz_id)
+			SELECT k.id, unnest($7::uuid[]) FROM k
+		)
+		SELECT id, created_at FROM k`,
+		key.Name, key.Prefix, keyHash, key.Role, createdBy, key.ExpiresAt, pq.Array(key.PVZIDs),
+	).Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" && pqErr.Constraint == "api_key_pvzs_pvz_id_fkey" {
+			return domain.APIKey{}, domain.ErrPVZNotFound
+		}
+		return domain.APIKey{}, err
+	}
+
+	if key.PVZIDs == nil {
+		key.PVZIDs = []string{}
+	}
+	return key, nil
+}
+
+func (r *APIKeyRepositoryImpl) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys ORDER BY created_at, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []domain.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// RevokeAPIKey returns sql.ErrNoRows if the key does not exist or is already revoked.
+func (r *APIKeyRepositoryImpl) RevokeAPIKey(ctx context.Context, id string, now time.Time) error {
+	res, err := r.db.ExecContext(ctx,
+		"UPDATE api_keys SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL",
+		id, now,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// UseAPIKey looks up a live key by hash and records its use. It returns
+// sql.ErrNoRows if the key is unknown, revoked or expired.
+func (r *APIKeyRepositoryImpl) UseAPIKey(ctx context.Context, keyHash string, now time.Time) (domain.APIKey, error) {
+	return scanAPIKey(r.db.QueryRowContext(ctx,
+		`UPDATE api_keys SET last_used_at = $2
+		WHERE key_hash = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > $2)
+		RETURNING `+apiKeyColumns,
+		keyHash, now,
+	))
+}
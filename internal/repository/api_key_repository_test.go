@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/domain"
+)
+
+var apiKeyRowColumns = []string{
+	"id", "name", "prefix", "role", "pvz_ids", "created_by", "created_at", "expires_at", "last_used_at", "revoked_at",
+}
+
+func TestAPIKeyRepository_CreateAPIKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAPIKeyRepository(db)
+	createdBy := "user1"
+	createdAt := time.Now()
+	key := domain.APIKey{Name: "script", Prefix: "pvz_abcdefgh", Role: "employee", CreatedBy: &createdBy}
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery("INSERT INTO api_keys").
+			WithArgs("script", "pvz_abcdefgh", "hash1", "employee", "user1", nil, pq.Array([]string(nil))).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("key1", createdAt))
+
+		created, err := repo.CreateAPIKey(context.Background(), key, "hash1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "key1", created.ID)
+		assert.Equal(t, createdAt, created.CreatedAt)
+		assert.Equal(t, []string{}, created.PVZIDs)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown pvz", func(t *testing.T) {
+		mock.ExpectQuery("INSERT INTO api_keys").
+			WillReturnError(&pq.Error{Code: "23503", Constraint: "api_key_pvzs_pvz_id_fkey"})
+
+		_, err := repo.CreateAPIKey(context.Background(), key, "hash1")
+
+		assert.ErrorIs(t, err, domain.ErrPVZNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAPIKeyRepository_ListAPIKeys(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAPIKeyRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT id, name, prefix, role").
+		WillReturnRows(sqlmock.NewRows(apiKeyRowColumns).
+			AddRow("key1", "script", "pvz_abcdefgh", "employee", "{pvz1,pvz2}", "user1", now, nil, now, nil).
+			AddRow("key2", "report", "pvz_ijklmnop", "moderator", "{}", nil, now, now, nil, now))
+
+	keys, err := repo.ListAPIKeys(context.Background())
+
+	assert.NoError(t, err)
+	if !assert.Len(t, keys, 2) {
+		return
+	}
+	assert.Equal(t, []string{"pvz1", "pvz2"}, keys[0].PVZIDs)
+	assert.Equal(t, "user1", *keys[0].CreatedBy)
+	assert.NotNil(t, keys[0].LastUsedAt)
+	assert.Equal(t, []string{}, keys[1].PVZIDs)
+	assert.Nil(t, keys[1].CreatedBy)
+	assert.NotNil(t, keys[1].RevokedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyRepository_RevokeAPIKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAPIKeyRepository(db)
+	now := time.Now()
+
+	mock.ExpectExec("UPDATE api_keys SET revoked_at = \\$2").
+		WithArgs("key1", now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE api_keys SET revoked_at = \\$2").
+		WithArgs("key1", now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.RevokeAPIKey(context.Background(), "key1", now))
+	assert.ErrorIs(t, repo.RevokeAPIKey(context.Background(), "key1", now), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyRepository_UseAPIKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAPIKeyRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery("UPDATE api_keys SET last_used_at = \\$2").
+		WithArgs("hash1", now).
+		WillReturnRows(sqlmock.NewRows(apiKeyRowColumns).
+			AddRow("key1", "script", "pvz_abcdefgh", "employee", "{pvz1}", nil, now, nil, now, nil))
+	mock.ExpectQuery("UPDATE api_keys SET last_used_at = \\$2").
+		WithArgs("hash2", now).
+		WillReturnRows(sqlmock.NewRows(apiKeyRowColumns))
+
+	key, err := repo.UseAPIKey(context.Background(), "hash1", now)
+	assert.NoError(t, err)
+	assert.Equal(t, "employee", key.Role)
+	assert.Equal(t, []string{"pvz1"}, key.PVZIDs)
+
+	_, err = repo.UseAPIKey(context.Background(), "hash2", now)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+type AssignmentRepository interface {
+	AssignPVZ(ctx context.Context, userID, pvzID string) error
+	UnassignPVZ(ctx context.Context, userID, pvzID string) error
+	ListUserPVZs(ctx context.Context, userID string) ([]string, error)
+	IsAssigned(ctx context.Context, userID, pvzID string) (bool, error)
+}
+
+type AssignmentRepositoryImpl struct {
+	db DBTX
+}
+
+func NewAssignmentRepository(db DBTX) *AssignmentRepositoryImpl {
+	return &AssignmentRepositoryImpl{db: db}
+}
+
+// AssignPVZ is idempotent: assigning an already assigned PVZ is not an error.
+func (r *AssignmentRepositoryImpl) AssignPVZ(ctx context.Context, userID, pvzID string) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO user_pvz_assignments (user_id, pvz_id) VALUES ($1, $2)
+		ON CONFLICT (user_id, pvz_id) DO NOTHING`,
+		userID, pvzID,
+	)
+	return err
+}
+
+// UnassignPVZ returns sql.ErrNoRows if the user was not assigned to the PVZ.
+func (r *AssignmentRepositoryImpl) UnassignPVZ(ctx context.Context, userID, pvzID string) error {
+	res, err := r.db.ExecContext(ctx,
+		"DELETE FROM user_pvz_assignments WHERE user_id = $1 AND pvz_id = $2",
+		userID, pvzID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *AssignmentRepositoryImpl) ListUserPVZs(ctx context.Context, userID string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT pvz_id FROM user_pvz_assignments WHERE user_id = $1 ORDER BY assigned_at, pvz_id",
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pvzIDs := make([]string, 0)
+	for rows.Next() {
+		var pvzID string
+		if err := rows.Scan(&pvzID); err != nil {
+			return nil, err
+		}
+		pvzIDs = append(pvzIDs, pvzID)
+	}
+
+	return pvzIDs, rows.Err()
+}
+
+func (r *AssignmentRepositoryImpl) IsAssigned(ctx context.Context, userID, pvzID string) (bool, error) {
+	var assigned bool
+	err := r.db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM user_pvz_assignments WHERE user_id = $1 AND pvz_id = $2)",
+		userID, pvzID,
+	).Scan(&assigned)
+	return assigned, err
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssignmentRepository_AssignPVZ(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAssignmentRepository(db)
+
+	mock.ExpectExec("INSERT INTO user_pvz_assignments .* ON CONFLICT").
+		WithArgs("user1", "pvz1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.AssignPVZ(context.Background(), "user1", "pvz1")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssignmentRepository_UnassignPVZ(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAssignmentRepository(db)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM user_pvz_assignments").
+			WithArgs("user1", "pvz1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.UnassignPVZ(context.Background(), "user1", "pvz1")
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not assigned", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM user_pvz_assignments").
+			WithArgs("user1", "pvz2").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.UnassignPVZ(context.Background(), "user1", "pvz2")
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAssignmentRepository_ListUserPVZs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAssignmentRepository(db)
+
+	mock.ExpectQuery("SELECT pvz_id FROM user_pvz_assignments WHERE user_id =").
+		WithArgs("user1").
+		WillReturnRows(sqlmock.NewRows([]string{"pvz_id"}).AddRow("pvz1").AddRow("pvz2"))
+
+	pvzIDs, err := repo.ListUserPVZs(context.Background(), "user1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pvz1", "pvz2"}, pvzIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssignmentRepository_IsAssigned(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAssignmentRepository(db)
+
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs("user1", "pvz1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	assigned, err := repo.IsAssigned(context.Background(), "user1", "pvz1")
+	assert.NoError(t, err)
+	assert.True(t, assigned)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
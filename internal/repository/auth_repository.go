@@ -1,16 +1,23 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
-	"errors"
 	"github.com/google/uuid"
-	"github.com/lib/pq"
+
+	"pvz-service/internal/domain"
 )
 
+// userEmailKey is the default name Postgres gives the UNIQUE constraint on users.email.
+const userEmailKey = "users_email_key"
+
 type AuthRepository interface {
-	CreateUser(email, hashedPassword, role string) (string, error)
-	FindUserByEmail(email string) (string, string, string, error)
-	FindUserByRole(role string) (string, error)
+	CreateUser(ctx context.Context, email, hashedPassword, role string) (string, error)
+	FindUserByEmail(ctx context.Context, email string) (string, string, string, error)
+	FindUserByID(ctx context.Context, userID string) (string, string, error)
+	IsUserDisabled(ctx context.Context, userID string) (bool, error)
+	GetPasswordHash(ctx context.Context, userID string) (string, error)
+	UpdatePassword(ctx context.Context, userID, hashedPassword string) error
 }
 
 type AuthRepositoryImpl struct {
@@ -21,24 +28,24 @@ func NewAuthRepository(db *sql.DB) AuthRepository {
 	return &AuthRepositoryImpl{db: db}
 }
 
-func (r *AuthRepositoryImpl) CreateUser(email, hashedPassword, role string) (string, error) {
+func (r *AuthRepositoryImpl) CreateUser(ctx context.Context, email, hashedPassword, role string) (string, error) {
 	userID := uuid.New().String()
-	_, err := r.db.Exec(
+	_, err := r.db.ExecContext(ctx,
 		"INSERT INTO users (id, email, password, role) VALUES ($1, $2, $3, $4)",
 		userID, email, hashedPassword, role,
 	)
 	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
-			return "", errors.New("email already exists")
+		if isUniqueViolationOf(err, userEmailKey) {
+			return "", domain.ErrEmailAlreadyExists
 		}
 		return "", err
 	}
 	return userID, nil
 }
 
-func (r *AuthRepositoryImpl) FindUserByEmail(email string) (string, string, string, error) {
+func (r *AuthRepositoryImpl) FindUserByEmail(ctx context.Context, email string) (string, string, string, error) {
 	var userID, hashedPassword, role string
-	err := r.db.QueryRow(
+	err := r.db.QueryRowContext(ctx,
 		"SELECT id, password, role FROM users WHERE email = $1",
 		email,
 	).Scan(&userID, &hashedPassword, &role)
@@ -46,8 +53,38 @@ func (r *AuthRepositoryImpl) FindUserByEmail(email string) (string, string, stri
 	return userID, hashedPassword, role, err
 }
 
-func (r *AuthRepositoryImpl) FindUserByRole(role string) (string, error) {
-	var userID string
-	err := r.db.QueryRow("SELECT id FROM users WHERE role = $1 LIMIT 1", role).Scan(&userID)
-	return userID, err
+// FindUserByID returns the email and role of the user.
+func (r *AuthRepositoryImpl) FindUserByID(ctx context.Context, userID string) (string, string, error) {
+	var email, role string
+	err := r.db.QueryRowContext(ctx, "SELECT email, role FROM users WHERE id = $1", userID).Scan(&email, &role)
+	return email, role, err
+}
+
+func (r *AuthRepositoryImpl) IsUserDisabled(ctx context.Context, userID string) (bool, error) {
+	var disabled bool
+	err := r.db.QueryRowContext(ctx, "SELECT disabled FROM users WHERE id = $1", userID).Scan(&disabled)
+	return disabled, err
+}
+
+func (r *AuthRepositoryImpl) GetPasswordHash(ctx context.Context, userID string) (string, error) {
+	var hashedPassword string
+	err := r.db.QueryRowContext(ctx, "SELECT password FROM users WHERE id = $1", userID).Scan(&hashedPassword)
+	return hashedPassword, err
+}
+
+// UpdatePassword returns sql.ErrNoRows if the user does not exist.
+func (r *AuthRepositoryImpl) UpdatePassword(ctx context.Context, userID, hashedPassword string) error {
+	res, err := r.db.ExecContext(ctx, "UPDATE users SET password = $2 WHERE id = $1", userID, hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
 }
@@ -1,12 +1,16 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/domain"
 )
 
 func TestAuthRepository_CreateUser_Success(t *testing.T) {
@@ -22,7 +26,7 @@ func TestAuthRepository_CreateUser_Success(t *testing.T) {
 		WithArgs(sqlmock.AnyArg(), "test@example.com", sqlmock.AnyArg(), "employee").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	_, err = repo.CreateUser("test@example.com", "hashedpassword", "employee")
+	_, err = repo.CreateUser(context.Background(), "test@example.com", "hashedpassword", "employee")
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -38,11 +42,10 @@ func TestAuthRepository_CreateUser_EmailExists(t *testing.T) {
 
 	mock.ExpectExec("INSERT INTO users").
 		WithArgs(sqlmock.AnyArg(), "exists@example.com", sqlmock.AnyArg(), "employee").
-		WillReturnError(errors.New("email already exists"))
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "users_email_key"})
 
-	_, err = repo.CreateUser("exists@example.com", "hashedpassword", "employee")
-	assert.Error(t, err)
-	assert.Equal(t, "email already exists", err.Error())
+	_, err = repo.CreateUser(context.Background(), "exists@example.com", "hashedpassword", "employee")
+	assert.ErrorIs(t, err, domain.ErrEmailAlreadyExists)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -64,7 +67,7 @@ func TestAuthRepository_FindUserByEmail_Success(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows([]string{"id", "password", "role"}).
 			AddRow(expectedID, expectedPassword, expectedRole))
 
-	id, password, role, err := repo.FindUserByEmail("test@example.com")
+	id, password, role, err := repo.FindUserByEmail(context.Background(), "test@example.com")
 	assert.NoError(t, err)
 	assert.Equal(t, expectedID, id)
 	assert.Equal(t, expectedPassword, password)
@@ -85,48 +88,69 @@ func TestAuthRepository_FindUserByEmail_NotFound(t *testing.T) {
 		WithArgs("nonexistent@example.com").
 		WillReturnError(sql.ErrNoRows)
 
-	_, _, _, err = repo.FindUserByEmail("nonexistent@example.com")
+	_, _, _, err = repo.FindUserByEmail(context.Background(), "nonexistent@example.com")
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, sql.ErrNoRows))
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestAuthRepository_FindUserByRole_Success(t *testing.T) {
+func TestAuthRepository_FindUserByID(t *testing.T) {
 	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
+	assert.NoError(t, err)
 	defer db.Close()
 
 	repo := NewAuthRepository(db)
 
-	expectedID := "user123"
+	mock.ExpectQuery("SELECT email, role FROM users WHERE id =").
+		WithArgs("user123").
+		WillReturnRows(sqlmock.NewRows([]string{"email", "role"}).AddRow("test@example.com", "employee"))
 
-	mock.ExpectQuery("SELECT id FROM users WHERE role =").
-		WithArgs("employee").
-		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedID))
+	email, role, err := repo.FindUserByID(context.Background(), "user123")
+	assert.NoError(t, err)
+	assert.Equal(t, "test@example.com", email)
+	assert.Equal(t, "employee", role)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 
-	id, err := repo.FindUserByRole("employee")
+func TestAuthRepository_IsUserDisabled(t *testing.T) {
+	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
-	assert.Equal(t, expectedID, id)
+	defer db.Close()
+
+	repo := NewAuthRepository(db)
+
+	mock.ExpectQuery("SELECT disabled FROM users WHERE id =").
+		WithArgs("user123").
+		WillReturnRows(sqlmock.NewRows([]string{"disabled"}).AddRow(true))
+
+	disabled, err := repo.IsUserDisabled(context.Background(), "user123")
+	assert.NoError(t, err)
+	assert.True(t, disabled)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestAuthRepository_FindUserByRole_NotFound(t *testing.T) {
+func TestAuthRepository_UpdatePassword(t *testing.T) {
 	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
+	assert.NoError(t, err)
 	defer db.Close()
 
 	repo := NewAuthRepository(db)
 
-	mock.ExpectQuery("SELECT id FROM users WHERE role =").
-		WithArgs("moderator").
-		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT password FROM users WHERE id =").
+		WithArgs("user123").
+		WillReturnRows(sqlmock.NewRows([]string{"password"}).AddRow("old-hash"))
+	mock.ExpectExec("UPDATE users SET password = \\$2").
+		WithArgs("user123", "new-hash").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE users SET password = \\$2").
+		WithArgs("missing", "new-hash").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	hash, err := repo.GetPasswordHash(context.Background(), "user123")
+	assert.NoError(t, err)
+	assert.Equal(t, "old-hash", hash)
 
-	_, err = repo.FindUserByRole("moderator")
-	assert.Error(t, err)
-	assert.True(t, errors.Is(err, sql.ErrNoRows))
+	assert.NoError(t, repo.UpdatePassword(context.Background(), "user123", "new-hash"))
+	assert.ErrorIs(t, repo.UpdatePassword(context.Background(), "missing", "new-hash"), sql.ErrNoRows)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
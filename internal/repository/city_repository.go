@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+
+	"pvz-service/internal/domain"
+)
+
+type CityRepository interface {
+	CreateCity(ctx context.Context, name string, idGenerator func() uuid.UUID) (domain.City, error)
+	GetCityByID(ctx context.Context, id string) (domain.City, error)
+	GetCityByName(ctx context.Context, name string) (domain.City, error)
+	ListCities(ctx context.Context, onlyActive bool) ([]domain.City, error)
+	UpdateCity(ctx context.Context, id string, name *string, isActive *bool) (domain.City, error)
+	DeactivateCity(ctx context.Context, id string) error
+}
+
+type CityRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewCityRepository(db *sql.DB) *CityRepositoryImpl {
+	return &CityRepositoryImpl{db: db}
+}
+
+func (r *CityRepositoryImpl) CreateCity(
+	ctx context.Context, name string, idGenerator func() uuid.UUID) (domain.City, error) {
+	var city domain.City
+	err := r.db.QueryRowContext(ctx,
+		`INSERT INTO cities (id, name) VALUES ($1, $2)
+		 RETURNING id, name, is_active, created_at`,
+		idGenerator().String(), name,
+	).Scan(&city.ID, &city.Name, &city.IsActive, &city.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.City{}, domain.ErrCityAlreadyExists
+		}
+		return domain.City{}, err
+	}
+	return city, nil
+}
+
+func (r *CityRepositoryImpl) GetCityByID(ctx context.Context, id string) (domain.City, error) {
+	var city domain.City
+	err := r.db.QueryRowContext(ctx, "SELECT id, name, is_active, created_at FROM cities WHERE id = $1", id).
+		Scan(&city.ID, &city.Name, &city.IsActive, &city.CreatedAt)
+	return city, err
+}
+
+func (r *CityRepositoryImpl) GetCityByName(ctx context.Context, name string) (domain.City, error) {
+	var city domain.City
+	err := r.db.QueryRowContext(ctx, "SELECT id, name, is_active, created_at FROM cities WHERE name = $1", name).
+		Scan(&city.ID, &city.Name, &city.IsActive, &city.CreatedAt)
+	return city, err
+}
+
+func (r *CityRepositoryImpl) ListCities(ctx context.Context, onlyActive bool) ([]domain.City, error) {
+	query := "SELECT id, name, is_active, created_at FROM cities"
+	if onlyActive {
+		query += " WHERE is_active"
+	}
+	query += " ORDER BY name"
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cities := []domain.City{}
+	for rows.Next() {
+		var city domain.City
+		if err := rows.Scan(&city.ID, &city.Name, &city.IsActive, &city.CreatedAt); err != nil {
+			return nil, err
+		}
+		cities = append(cities, city)
+	}
+
+	return cities, rows.Err()
+}
+
+func (r *CityRepositoryImpl) UpdateCity(
+	ctx context.Context, id string, name *string, isActive *bool) (domain.City, error) {
+	var city domain.City
+	err := r.db.QueryRowContext(ctx,
+		`UPDATE cities
+		 SET name = COALESCE($2, name), is_active = COALESCE($3, is_active)
+		 WHERE id = $1
+		 RETURNING id, name, is_active, created_at`,
+		id, name, isActive,
+	).Scan(&city.ID, &city.Name, &city.IsActive, &city.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.City{}, domain.ErrCityAlreadyExists
+		}
+		return domain.City{}, err
+	}
+	return city, nil
+}
+
+func (r *CityRepositoryImpl) DeactivateCity(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, "UPDATE cities SET is_active = FALSE WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
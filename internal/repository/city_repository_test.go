@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/domain"
+)
+
+func TestCityRepository_CreateCity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCityRepository(db)
+	cityID := uuid.NewString()
+	now := time.Now()
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery("INSERT INTO cities").
+			WithArgs(cityID, "Новосибирск").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "is_active", "created_at"}).
+				AddRow(cityID, "Новосибирск", true, now))
+
+		city, err := repo.CreateCity(context.Background(), "Новосибирск", func() uuid.UUID { return uuid.MustParse(cityID) })
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.City{ID: cityID, Name: "Новосибирск", IsActive: true, CreatedAt: now}, city)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		mock.ExpectQuery("INSERT INTO cities").
+			WithArgs(cityID, "Москва").
+			WillReturnError(&pq.Error{Code: "23505"})
+
+		_, err := repo.CreateCity(context.Background(), "Москва", func() uuid.UUID { return uuid.MustParse(cityID) })
+
+		assert.ErrorIs(t, err, domain.ErrCityAlreadyExists)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCityRepository_GetCityByName(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCityRepository(db)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery("SELECT id, name, is_active, created_at FROM cities WHERE name =").
+			WithArgs("Казань").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "is_active", "created_at"}).
+				AddRow("city1", "Казань", false, time.Now()))
+
+		city, err := repo.GetCityByName(context.Background(), "Казань")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Казань", city.Name)
+		assert.False(t, city.IsActive)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery("SELECT id, name, is_active, created_at FROM cities WHERE name =").
+			WithArgs("Нью-Йорк").
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.GetCityByName(context.Background(), "Нью-Йорк")
+
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCityRepository_ListCities(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCityRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT id, name, is_active, created_at FROM cities WHERE is_active ORDER BY name").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "is_active", "created_at"}).
+			AddRow("city1", "Казань", true, now).
+			AddRow("city2", "Москва", true, now))
+
+	cities, err := repo.ListCities(context.Background(), true)
+
+	assert.NoError(t, err)
+	assert.Len(t, cities, 2)
+	assert.Equal(t, "Москва", cities[1].Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCityRepository_UpdateCity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCityRepository(db)
+	cityID := uuid.NewString()
+	isActive := false
+
+	mock.ExpectQuery("UPDATE cities").
+		WithArgs(cityID, nil, isActive).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "is_active", "created_at"}).
+			AddRow(cityID, "Казань", false, time.Now()))
+
+	city, err := repo.UpdateCity(context.Background(), cityID, nil, &isActive)
+
+	assert.NoError(t, err)
+	assert.False(t, city.IsActive)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCityRepository_DeactivateCity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCityRepository(db)
+	cityID := uuid.NewString()
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec("UPDATE cities SET is_active = FALSE WHERE id =").
+			WithArgs(cityID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.DeactivateCity(context.Background(), cityID))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectExec("UPDATE cities SET is_active = FALSE WHERE id =").
+			WithArgs(cityID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, repo.DeactivateCity(context.Background(), cityID), sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
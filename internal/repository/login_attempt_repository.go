@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// LoginAttemptRepository is the Postgres-backed loginguard.Store.
+type LoginAttemptRepository interface {
+	LockedUntil(ctx context.Context, key string, now time.Time) (time.Time, error)
+	AddFailure(ctx context.Context, key string, now, windowStart time.Time) (int, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+}
+
+type LoginAttemptRepositoryImpl struct {
+	db DBTX
+}
+
+func NewLoginAttemptRepository(db DBTX) *LoginAttemptRepositoryImpl {
+	return &LoginAttemptRepositoryImpl{db: db}
+}
+
+func (r *LoginAttemptRepositoryImpl) LockedUntil(ctx context.Context, key string, now time.Time) (time.Time, error) {
+	var lockedUntil sql.NullTime
+	err := r.db.QueryRowContext(ctx,
+		"SELECT locked_until FROM login_attempts WHERE key = $1", key,
+	).Scan(&lockedUntil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return lockedUntil.Time, nil
+}
+
+// AddFailure drops counters whose window has passed and whose lockout is over,
+// including the key's own, then increments the key's counter.
+func (r *LoginAttemptRepositoryImpl) AddFailure(
+	ctx context.Context, key string, now, windowStart time.Time) (int, error) {
+	if _, err := r.db.ExecContext(ctx,
+		`DELETE FROM login_attempts
+		WHERE window_started_at < $1 AND (locked_until IS NULL OR locked_until <= $2)`,
+		windowStart, now,
+	); err != nil {
+		return 0, err
+	}
+
+	var failures int
+	err := r.db.QueryRowContext(ctx,
+		`INSERT INTO login_attempts (key, failures, window_started_at) VALUES ($1, 1, $2)
+		ON CONFLICT (key) DO UPDATE SET failures = login_attempts.failures + 1
+		RETURNING failures`,
+		key, now,
+	).Scan(&failures)
+	return failures, err
+}
+
+func (r *LoginAttemptRepositoryImpl) Lock(ctx context.Context, key string, until time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE login_attempts SET failures = 0, locked_until = $2 WHERE key = $1",
+		key, until,
+	)
+	return err
+}
+
+func (r *LoginAttemptRepositoryImpl) Reset(ctx context.Context, key string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM login_attempts WHERE key = $1", key)
+	return err
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginAttemptRepository_LockedUntil(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLoginAttemptRepository(db)
+	now := time.Now()
+	until := now.Add(time.Minute)
+
+	mock.ExpectQuery("SELECT locked_until FROM login_attempts").
+		WithArgs("email:a@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"locked_until"}).AddRow(until))
+	mock.ExpectQuery("SELECT locked_until FROM login_attempts").
+		WithArgs("email:b@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"locked_until"}))
+
+	lockedUntil, err := repo.LockedUntil(context.Background(), "email:a@example.com", now)
+	assert.NoError(t, err)
+	assert.Equal(t, until, lockedUntil)
+
+	lockedUntil, err = repo.LockedUntil(context.Background(), "email:b@example.com", now)
+	assert.NoError(t, err)
+	assert.True(t, lockedUntil.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginAttemptRepository_AddFailure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLoginAttemptRepository(db)
+	now := time.Now()
+	windowStart := now.Add(-15 * time.Minute)
+
+	mock.ExpectExec("DELETE FROM login_attempts").
+		WithArgs(windowStart, now).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery("INSERT INTO login_attempts").
+		WithArgs("ip:10.0.0.1", now).
+		WillReturnRows(sqlmock.NewRows([]string{"failures"}).AddRow(3))
+
+	failures, err := repo.AddFailure(context.Background(), "ip:10.0.0.1", now, windowStart)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, failures)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginAttemptRepository_LockAndReset(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLoginAttemptRepository(db)
+	until := time.Now().Add(time.Minute)
+
+	mock.ExpectExec("UPDATE login_attempts SET failures = 0, locked_until = \\$2").
+		WithArgs("email:a@example.com", until).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM login_attempts WHERE key = \\$1").
+		WithArgs("email:a@example.com").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.Lock(context.Background(), "email:a@example.com", until))
+	assert.NoError(t, repo.Reset(context.Background(), "email:a@example.com"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type PasswordResetRepository interface {
+	CreateResetToken(ctx context.Context, userID, tokenHash string, expiresAt, now time.Time) error
+	ResetPassword(ctx context.Context, tokenHash, hashedPassword string, now time.Time) (string, error)
+}
+
+type PasswordResetRepositoryImpl struct {
+	db DBTX
+}
+
+func NewPasswordResetRepository(db DBTX) *PasswordResetRepositoryImpl {
+	return &PasswordResetRepositoryImpl{db: db}
+}
+
+// CreateResetToken stores the hash of a reset token and drops tokens that have
+// expired or were used.
+func (r *PasswordResetRepositoryImpl) CreateResetToken(
+	ctx context.Context, userID, tokenHash string, expiresAt, now time.Time) error {
+	if _, err := r.db.ExecContext(ctx,
+		"DELETE FROM password_reset_tokens WHERE expires_at < $1 OR used_at IS NOT NULL", now,
+	); err != nil {
+		return err
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		"INSERT INTO password_reset_tokens (token_hash, user_id, expires_at) VALUES ($1, $2, $3)",
+		tokenHash, userID, expiresAt,
+	)
+	return err
+}
+
+// ResetPassword consumes a live reset token and sets the password of its user in a
+// single statement, so a token works only once. It returns the user id, or
+// sql.ErrNoRows if the token is unknown, used or expired.
+func (r *PasswordResetRepositoryImpl) ResetPassword(
+	ctx context.Context, tokenHash, hashedPassword string, now time.Time) (string, error) {
+	var userID string
+	err := r.db.QueryRowContext(ctx,
+		`WITH t AS (
+			UPDATE password_reset_tokens SET used_at = $3
+			WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $3
+			RETURNING user_id
+		)
+		UPDATE users u SET password = $2 FROM t WHERE u.id = t.user_id
+		RETURNING u.id`,
+		tokenHash, hashedPassword, now,
+	).Scan(&userID)
+	return userID, err
+}
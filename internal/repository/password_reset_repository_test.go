@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordResetRepository_CreateResetToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPasswordResetRepository(db)
+	now := time.Now()
+	expiresAt := now.Add(time.Hour)
+
+	mock.ExpectExec("DELETE FROM password_reset_tokens").
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO password_reset_tokens").
+		WithArgs("hash1", "user1", expiresAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.CreateResetToken(context.Background(), "user1", "hash1", expiresAt, now))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPasswordResetRepository_ResetPassword(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPasswordResetRepository(db)
+	now := time.Now()
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery("UPDATE password_reset_tokens SET used_at = \\$3").
+			WithArgs("hash1", "new-hash", now).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("user1"))
+
+		userID, err := repo.ResetPassword(context.Background(), "hash1", "new-hash", now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user1", userID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("used or expired", func(t *testing.T) {
+		mock.ExpectQuery("UPDATE password_reset_tokens").
+			WithArgs("hash1", "new-hash", now).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err := repo.ResetPassword(context.Background(), "hash1", "new-hash", now)
+
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -1,25 +1,59 @@
 package repository
 
 import (
-	"database/sql"
+	"context"
+	"encoding/json"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 
 	"pvz-service/internal/domain"
 )
 
-type ProductRepository struct {
-	db *sql.DB
+type ProductRepository interface {
+	AddProduct(
+		ctx context.Context,
+		receptionID, productType string,
+		attributes map[string]string,
+		idGenerator func() uuid.UUID,
+	) (string, error)
+	GetProductByID(ctx context.Context, id string) (domain.Product, error)
+	GetLastProduct(ctx context.Context, receptionID string) (domain.Product, error)
+	DeleteProduct(ctx context.Context, id string) error
+	ListProductsByReception(ctx context.Context, receptionID string, limit, offset int) ([]domain.Product, int, error)
+	ListProductsByReceptions(ctx context.Context, receptionIDs []string) (map[string][]domain.Product, error)
 }
 
-func NewProductRepository(db *sql.DB) *ProductRepository {
-	return &ProductRepository{db: db}
+type ProductListResponse struct {
+	Items      []domain.Product `json:"items"`
+	Total      int              `json:"total"`
+	Page       int              `json:"page"`
+	Limit      int              `json:"limit"`
+	TotalPages int              `json:"totalPages"`
 }
 
-func (r *ProductRepository) AddProduct(receptionID, productType string, idGenerator func() uuid.UUID) (string, error) {
+type ProductRepositoryImpl struct {
+	db DBTX
+}
+
+func NewProductRepository(db DBTX) *ProductRepositoryImpl {
+	return &ProductRepositoryImpl{db: db}
+}
+
+func (r *ProductRepositoryImpl) AddProduct(
+	ctx context.Context,
+	receptionID, productType string,
+	attributes map[string]string,
+	idGenerator func() uuid.UUID,
+) (string, error) {
+	encodedAttributes, err := encodeAttributes(attributes)
+	if err != nil {
+		return "", err
+	}
+
 	productID := idGenerator().String()
-	_, err := r.db.Exec(
-		"INSERT INTO products (id, reception_id, type) VALUES ($1, $2, $3)",
-		productID, receptionID, productType,
+	_, err = r.db.ExecContext(ctx,
+		"INSERT INTO products (id, reception_id, type, attributes) VALUES ($1, $2, $3, $4)",
+		productID, receptionID, productType, encodedAttributes,
 	)
 	if err != nil {
 		return "", err
@@ -27,35 +61,137 @@ func (r *ProductRepository) AddProduct(receptionID, productType string, idGenera
 	return productID, nil
 }
 
-func (r *ProductRepository) GetProductByID(id string) (domain.Product, error) {
+func (r *ProductRepositoryImpl) GetProductByID(ctx context.Context, id string) (domain.Product, error) {
 	var product domain.Product
-	err := r.db.QueryRow(
-		"SELECT id, created_at, type, reception_id FROM products WHERE id = $1",
+	var attributes []byte
+	err := r.db.QueryRowContext(ctx,
+		"SELECT id, created_at, type, reception_id, attributes FROM products WHERE id = $1",
 		id,
-	).Scan(&product.ID, &product.DateTime, &product.Type, &product.ReceptionId)
+	).Scan(&product.ID, &product.DateTime, &product.Type, &product.ReceptionId, &attributes)
 
 	if err != nil {
 		return domain.Product{}, err
 	}
+	if product.Attributes, err = decodeAttributes(attributes); err != nil {
+		return domain.Product{}, err
+	}
 	return product, nil
 }
 
-func (r *ProductRepository) GetLastProduct(receptionID string) (domain.Product, error) {
+func (r *ProductRepositoryImpl) GetLastProduct(ctx context.Context, receptionID string) (domain.Product, error) {
 	var product domain.Product
-	err := r.db.QueryRow(
-		`SELECT id, created_at, type, reception_id 
+	var attributes []byte
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id, created_at, type, reception_id, attributes 
 		 FROM products WHERE reception_id = $1 
 		 ORDER BY created_at DESC LIMIT 1`,
 		receptionID,
-	).Scan(&product.ID, &product.DateTime, &product.Type, &product.ReceptionId)
+	).Scan(&product.ID, &product.DateTime, &product.Type, &product.ReceptionId, &attributes)
 
 	if err != nil {
 		return domain.Product{}, err
 	}
+	if product.Attributes, err = decodeAttributes(attributes); err != nil {
+		return domain.Product{}, err
+	}
 	return product, nil
 }
 
-func (r *ProductRepository) DeleteProduct(id string) error {
-	_, err := r.db.Exec("DELETE FROM products WHERE id = $1", id)
+func (r *ProductRepositoryImpl) DeleteProduct(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM products WHERE id = $1", id)
 	return err
 }
+
+func (r *ProductRepositoryImpl) ListProductsByReception(
+	ctx context.Context, receptionID string, limit, offset int) ([]domain.Product, int, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM products WHERE reception_id = $1", receptionID).
+		Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, created_at, type, reception_id, attributes FROM products
+		WHERE reception_id = $1 ORDER BY created_at, id LIMIT $2 OFFSET $3`,
+		receptionID, limit, offset,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	products := []domain.Product{}
+	for rows.Next() {
+		var product domain.Product
+		var attributes []byte
+		if err := rows.Scan(
+			&product.ID, &product.DateTime, &product.Type, &product.ReceptionId, &attributes,
+		); err != nil {
+			return nil, 0, err
+		}
+		if product.Attributes, err = decodeAttributes(attributes); err != nil {
+			return nil, 0, err
+		}
+		products = append(products, product)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return products, total, nil
+}
+
+// ListProductsByReceptions loads all products of the given receptions in one query,
+// grouped by reception id and ordered by add time.
+func (r *ProductRepositoryImpl) ListProductsByReceptions(
+	ctx context.Context, receptionIDs []string) (map[string][]domain.Product, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, created_at, type, reception_id, attributes FROM products
+		WHERE reception_id = ANY($1) ORDER BY created_at, id`,
+		pq.Array(receptionIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string][]domain.Product)
+	for rows.Next() {
+		var product domain.Product
+		var attributes []byte
+		if err := rows.Scan(
+			&product.ID, &product.DateTime, &product.Type, &product.ReceptionId, &attributes,
+		); err != nil {
+			return nil, err
+		}
+		if product.Attributes, err = decodeAttributes(attributes); err != nil {
+			return nil, err
+		}
+		result[product.ReceptionId] = append(result[product.ReceptionId], product)
+	}
+
+	return result, rows.Err()
+}
+
+func encodeAttributes(attributes map[string]string) ([]byte, error) {
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+	return json.Marshal(attributes)
+}
+
+func decodeAttributes(raw []byte) (map[string]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	var attributes map[string]string
+	if err := json.Unmarshal(raw, &attributes); err != nil {
+		return nil, err
+	}
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+	return attributes, nil
+}
@@ -1,10 +1,13 @@
 package repository
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 
 	"pvz-service/internal/domain"
@@ -21,10 +24,10 @@ func TestProductRepository_AddProduct(t *testing.T) {
 	productID := uuid.NewString()
 
 	mock.ExpectExec("INSERT INTO products").
-		WithArgs(productID, receptionID, "электроника").
+		WithArgs(productID, receptionID, "электроника", []byte(`{"serial_number":"SN-1"}`)).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	id, err := repo.AddProduct(receptionID, "электроника", func() uuid.UUID {
+	id, err := repo.AddProduct(context.Background(), receptionID, "электроника", map[string]string{"serial_number": "SN-1"}, func() uuid.UUID {
 		return uuid.MustParse(productID)
 	})
 
@@ -46,17 +49,39 @@ func TestProductRepository_GetProductByID(t *testing.T) {
 		Type: "электроника",
 	}
 
-	mock.ExpectQuery("SELECT id, created_at, type, reception_id FROM products").
+	mock.ExpectQuery("SELECT id, created_at, type, reception_id, attributes FROM products").
 		WithArgs(productID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "type", "reception_id"}).
-			AddRow(expected.ID, expected.DateTime, expected.Type, expected.ReceptionId))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "type", "reception_id", "attributes"}).
+			AddRow(expected.ID, expected.DateTime, expected.Type, expected.ReceptionId, []byte(`{}`)))
 
-	product, err := repo.GetProductByID(productID)
+	product, err := repo.GetProductByID(context.Background(), productID)
 	assert.NoError(t, err)
 	assert.Equal(t, expected, product)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestProductRepository_GetLastProduct(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewProductRepository(db)
+
+	receptionID := uuid.NewString()
+	productID := uuid.NewString()
+
+	mock.ExpectQuery("SELECT id, created_at, type, reception_id, attributes FROM products WHERE reception_id").
+		WithArgs(receptionID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "type", "reception_id", "attributes"}).
+			AddRow(productID, time.Now(), "обувь", receptionID, []byte(`{"size":"42"}`)))
+
+	product, err := repo.GetLastProduct(context.Background(), receptionID)
+	assert.NoError(t, err)
+	assert.Equal(t, productID, product.ID)
+	assert.Equal(t, map[string]string{"size": "42"}, product.Attributes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestProductRepository_DeleteProduct(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -70,7 +95,80 @@ func TestProductRepository_DeleteProduct(t *testing.T) {
 		WithArgs(productID).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
-	err = repo.DeleteProduct(productID)
+	err = repo.DeleteProduct(context.Background(), productID)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_ListProductsByReception(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewProductRepository(db)
+	receptionID := uuid.New().String()
+	now := time.Now()
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM products WHERE reception_id = \\$1").
+			WithArgs(receptionID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+		mock.ExpectQuery("SELECT id, created_at, type, reception_id, attributes FROM products").
+			WithArgs(receptionID, 2, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "type", "reception_id", "attributes"}).
+				AddRow("prod3", now, "обувь", receptionID, []byte(`{"size":"42"}`)))
+
+		products, total, err := repo.ListProductsByReception(context.Background(), receptionID, 2, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, total)
+		assert.Equal(t, []domain.Product{{
+			ID:          "prod3",
+			DateTime:    now,
+			Type:        "обувь",
+			ReceptionId: receptionID,
+			Attributes:  map[string]string{"size": "42"},
+		}}, products)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		mock.ExpectQuery("SELECT COUNT").
+			WithArgs(receptionID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery("FROM products").
+			WithArgs(receptionID, 10, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "type", "reception_id", "attributes"}))
+
+		products, total, err := repo.ListProductsByReception(context.Background(), receptionID, 10, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, total)
+		assert.NotNil(t, products)
+		assert.Empty(t, products)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductRepository_ListProductsByReceptions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewProductRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery("FROM products WHERE reception_id = ANY\\(\\$1\\)").
+		WithArgs(pq.Array([]string{"rec1", "rec2"})).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "type", "reception_id", "attributes"}).
+			AddRow("prod1", now, "электроника", "rec1", []byte(`{}`)).
+			AddRow("prod2", now, "одежда", "rec1", []byte(`{"size":"M"}`)))
+
+	products, err := repo.ListProductsByReceptions(context.Background(), []string{"rec1", "rec2"})
+
 	assert.NoError(t, err)
+	assert.Len(t, products["rec1"], 2)
+	assert.Equal(t, map[string]string{"size": "M"}, products["rec1"][1].Attributes)
+	assert.Empty(t, products["rec2"])
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
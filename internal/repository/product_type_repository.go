@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	"pvz-service/internal/domain"
+)
+
+type ProductTypeRepository interface {
+	CreateProductType(ctx context.Context, productType domain.ProductType) (domain.ProductType, error)
+	GetProductTypeByCode(ctx context.Context, code string) (domain.ProductType, error)
+	ListProductTypes(ctx context.Context, onlyActive bool) ([]domain.ProductType, error)
+	UpdateProductType(
+		ctx context.Context, code string, displayName *string, requiredAttributes []string, isActive *bool,
+	) (domain.ProductType, error)
+	DeactivateProductType(ctx context.Context, code string) error
+}
+
+type ProductTypeRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewProductTypeRepository(db *sql.DB) *ProductTypeRepositoryImpl {
+	return &ProductTypeRepositoryImpl{db: db}
+}
+
+func (r *ProductTypeRepositoryImpl) CreateProductType(
+	ctx context.Context, productType domain.ProductType) (domain.ProductType, error) {
+	var created domain.ProductType
+	err := r.db.QueryRowContext(ctx,
+		`INSERT INTO product_types (code, display_name, required_attributes) VALUES ($1, $2, $3)
+		 RETURNING code, display_name, required_attributes, is_active, created_at`,
+		productType.Code, productType.DisplayName, pq.Array(productType.RequiredAttributes),
+	).Scan(&created.Code, &created.DisplayName, pq.Array(&created.RequiredAttributes),
+		&created.IsActive, &created.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ProductType{}, domain.ErrProductTypeAlreadyExists
+		}
+		return domain.ProductType{}, err
+	}
+	return created, nil
+}
+
+func (r *ProductTypeRepositoryImpl) GetProductTypeByCode(ctx context.Context, code string) (domain.ProductType, error) {
+	var productType domain.ProductType
+	err := r.db.QueryRowContext(ctx,
+		`SELECT code, display_name, required_attributes, is_active, created_at
+		 FROM product_types WHERE code = $1`,
+		code,
+	).Scan(&productType.Code, &productType.DisplayName, pq.Array(&productType.RequiredAttributes),
+		&productType.IsActive, &productType.CreatedAt)
+	return productType, err
+}
+
+func (r *ProductTypeRepositoryImpl) ListProductTypes(
+	ctx context.Context, onlyActive bool) ([]domain.ProductType, error) {
+	query := "SELECT code, display_name, required_attributes, is_active, created_at FROM product_types"
+	if onlyActive {
+		query += " WHERE is_active"
+	}
+	query += " ORDER BY code"
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	productTypes := []domain.ProductType{}
+	for rows.Next() {
+		var productType domain.ProductType
+		if err := rows.Scan(&productType.Code, &productType.DisplayName, pq.Array(&productType.RequiredAttributes),
+			&productType.IsActive, &productType.CreatedAt); err != nil {
+			return nil, err
+		}
+		productTypes = append(productTypes, productType)
+	}
+
+	return productTypes, rows.Err()
+}
+
+func (r *ProductTypeRepositoryImpl) UpdateProductType(
+	ctx context.Context, code string, displayName *string, requiredAttributes []string, isActive *bool,
+) (domain.ProductType, error) {
+	var attributes interface{}
+	if requiredAttributes != nil {
+		attributes = pq.Array(requiredAttributes)
+	}
+
+	var productType domain.ProductType
+	err := r.db.QueryRowContext(ctx,
+		`UPDATE product_types
+		 SET display_name = COALESCE($2, display_name),
+		     required_attributes = COALESCE($3, required_attributes),
+		     is_active = COALESCE($4, is_active)
+		 WHERE code = $1
+		 RETURNING code, display_name, required_attributes, is_active, created_at`,
+		code, displayName, attributes, isActive,
+	).Scan(&productType.Code, &productType.DisplayName, pq.Array(&productType.RequiredAttributes),
+		&productType.IsActive, &productType.CreatedAt)
+	return productType, err
+}
+
+func (r *ProductTypeRepositoryImpl) DeactivateProductType(ctx context.Context, code string) error {
+	res, err := r.db.ExecContext(ctx, "UPDATE product_types SET is_active = FALSE WHERE code = $1", code)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/domain"
+)
+
+func TestProductTypeRepository_CreateProductType(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewProductTypeRepository(db)
+	now := time.Now()
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery("INSERT INTO product_types").
+			WithArgs("обувь", "Обувь", pq.Array([]string{"size"})).
+			WillReturnRows(sqlmock.NewRows([]string{"code", "display_name", "required_attributes", "is_active", "created_at"}).
+				AddRow("обувь", "Обувь", "{size}", true, now))
+
+		productType, err := repo.CreateProductType(context.Background(), domain.ProductType{
+			Code:               "обувь",
+			DisplayName:        "Обувь",
+			RequiredAttributes: []string{"size"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"size"}, productType.RequiredAttributes)
+		assert.True(t, productType.IsActive)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("duplicate code", func(t *testing.T) {
+		mock.ExpectQuery("INSERT INTO product_types").
+			WillReturnError(&pq.Error{Code: "23505"})
+
+		_, err := repo.CreateProductType(context.Background(), domain.ProductType{Code: "обувь", DisplayName: "Обувь"})
+
+		assert.ErrorIs(t, err, domain.ErrProductTypeAlreadyExists)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductTypeRepository_GetProductTypeByCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewProductTypeRepository(db)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery("SELECT code, display_name, required_attributes, is_active, created_at FROM product_types WHERE code =").
+			WithArgs("электроника").
+			WillReturnRows(sqlmock.NewRows([]string{"code", "display_name", "required_attributes", "is_active", "created_at"}).
+				AddRow("электроника", "Электроника", "{serial_number}", true, time.Now()))
+
+		productType, err := repo.GetProductTypeByCode(context.Background(), "электроника")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"serial_number"}, productType.RequiredAttributes)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery("SELECT code, display_name, required_attributes, is_active, created_at FROM product_types WHERE code =").
+			WithArgs("мебель").
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.GetProductTypeByCode(context.Background(), "мебель")
+
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductTypeRepository_ListProductTypes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewProductTypeRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT code, display_name, required_attributes, is_active, created_at FROM product_types ORDER BY code").
+		WillReturnRows(sqlmock.NewRows([]string{"code", "display_name", "required_attributes", "is_active", "created_at"}).
+			AddRow("обувь", "Обувь", "{size}", true, now).
+			AddRow("одежда", "Одежда", "{}", false, now))
+
+	productTypes, err := repo.ListProductTypes(context.Background(), false)
+
+	assert.NoError(t, err)
+	assert.Len(t, productTypes, 2)
+	assert.Empty(t, productTypes[1].RequiredAttributes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductTypeRepository_DeactivateProductType(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewProductTypeRepository(db)
+
+	mock.ExpectExec("UPDATE product_types SET is_active = FALSE WHERE code =").
+		WithArgs("мебель").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, repo.DeactivateProductType(context.Background(), "мебель"), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
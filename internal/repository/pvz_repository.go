@@ -1,48 +1,74 @@
 package repository
 
 import (
-	"database/sql"
+	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"pvz-service/internal/domain"
-	"pvz-service/internal/utils"
 )
 
 type PVZRepository interface {
-	CreatePVZ(city string, idGenerator func() uuid.UUID) (domain.PVZ, error)
-	GetPVZByID(id string) (domain.PVZ, error)
-	ListPVZsWithRelations(startDate, endDate time.Time, limit, offset int) ([]PVZResponse, error)
+	CreatePVZ(ctx context.Context, city string, idGenerator func() uuid.UUID) (domain.PVZ, error)
+	GetPVZByID(ctx context.Context, id string) (domain.PVZ, error)
+	ListPVZs(ctx context.Context) ([]domain.PVZ, error)
+	ListPVZsWithRelations(
+		ctx context.Context, filter PVZFilter, sort PVZSort, limit, offset int) ([]PVZResponse, int, error)
+	ListPVZsWithRelationsAfter(ctx context.Context, filter PVZFilter, after *PVZKey, limit int) ([]PVZResponse, error)
 }
 
 type PVZRepositoryImpl struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewPVZRepository(db *sql.DB) *PVZRepositoryImpl {
+func NewPVZRepository(db DBTX) *PVZRepositoryImpl {
 	return &PVZRepositoryImpl{db: db}
 }
 
-func (r *PVZRepositoryImpl) CreatePVZ(city string, idGenerator func() uuid.UUID) (domain.PVZ, error) {
+func (r *PVZRepositoryImpl) CreatePVZ(
+	ctx context.Context, city string, idGenerator func() uuid.UUID) (domain.PVZ, error) {
 	pvzID := idGenerator().String()
-	_, err := r.db.Exec("INSERT INTO pvz (id, city) VALUES ($1, $2)", pvzID, city)
+	_, err := r.db.ExecContext(ctx, "INSERT INTO pvz (id, city) VALUES ($1, $2)", pvzID, city)
 	if err != nil {
 		return domain.PVZ{}, err
 	}
 
 	var pvz domain.PVZ
-	err = r.db.QueryRow("SELECT id, registration_date, city FROM pvz WHERE id = $1", pvzID).
+	err = r.db.QueryRowContext(ctx, "SELECT id, registration_date, city FROM pvz WHERE id = $1", pvzID).
 		Scan(&pvz.ID, &pvz.RegistrationDate, &pvz.City)
 	return pvz, err
 }
 
-func (r *PVZRepositoryImpl) GetPVZByID(id string) (domain.PVZ, error) {
+func (r *PVZRepositoryImpl) GetPVZByID(ctx context.Context, id string) (domain.PVZ, error) {
 	var pvz domain.PVZ
-	err := r.db.QueryRow("SELECT id, registration_date, city FROM pvz WHERE id = $1", id).
+	err := r.db.QueryRowContext(ctx, "SELECT id, registration_date, city FROM pvz WHERE id = $1", id).
 		Scan(&pvz.ID, &pvz.RegistrationDate, &pvz.City)
 	return pvz, err
 }
 
+// ListPVZs returns every PVZ without relations, in registration order.
+func (r *PVZRepositoryImpl) ListPVZs(ctx context.Context) ([]domain.PVZ, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT id, registration_date, city FROM pvz ORDER BY registration_date, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pvzs := []domain.PVZ{}
+	for rows.Next() {
+		var pvz domain.PVZ
+		if err := rows.Scan(&pvz.ID, &pvz.RegistrationDate, &pvz.City); err != nil {
+			return nil, err
+		}
+		pvzs = append(pvzs, pvz)
+	}
+
+	return pvzs, rows.Err()
+}
+
 type PVZResponse struct {
 	PVZ        domain.PVZ          `json:"pvz"`
 	Receptions []ReceptionResponse `json:"receptions"`
@@ -53,125 +79,299 @@ type ReceptionResponse struct {
 	Products  []domain.Product `json:"products"`
 }
 
+// PVZFilter narrows the PVZ listing. Reception-level fields (dates, status, product types)
+// select which receptions are attached to each PVZ and which ones product counts are taken over;
+// a PVZ without a matching reception is left out whenever any of them is set.
+type PVZFilter struct {
+	StartDate        time.Time
+	EndDate          time.Time
+	Cities           []string
+	ReceptionStatus  string
+	ProductTypes     []string
+	HasOpenReception *bool
+	MinProducts      *int
+	MaxProducts      *int
+}
+
+func (f PVZFilter) hasReceptionConditions() bool {
+	return !f.StartDate.IsZero() || !f.EndDate.IsZero() || f.ReceptionStatus != "" || len(f.ProductTypes) > 0
+}
+
+func (f PVZFilter) needsStats() bool {
+	return f.MinProducts != nil || f.MaxProducts != nil
+}
+
+const (
+	PVZSortRegistrationDate = "registrationDate"
+	PVZSortLastReception    = "lastReception"
+	PVZSortProductCount     = "productCount"
+)
+
+var pvzSortColumns = map[string]string{
+	PVZSortRegistrationDate: "p.registration_date",
+	PVZSortLastReception:    "stats.last_reception_at",
+	PVZSortProductCount:     "stats.product_count",
+}
+
+// IsValidPVZSortField reports whether field can be used as PVZSort.Field.
+func IsValidPVZSortField(field string) bool {
+	_, ok := pvzSortColumns[field]
+	return ok
+}
+
+// PVZSort orders the PVZ listing. An empty Field means registration date.
+type PVZSort struct {
+	Field string
+	Desc  bool
+}
+
+// PVZKey is the position of a PVZ in the (registration_date, id) listing order.
+type PVZKey struct {
+	RegistrationDate time.Time
+	ID               string
+}
+
+type PVZListResponse struct {
+	Items      []PVZResponse `json:"items"`
+	Total      int           `json:"total"`
+	Page       int           `json:"page"`
+	Limit      int           `json:"limit"`
+	TotalPages int           `json:"totalPages"`
+}
+
+type PVZCursorResponse struct {
+	Items      []PVZResponse `json:"items"`
+	Limit      int           `json:"limit"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+}
+
+// ListPVZsWithRelations paginates over PVZs and then loads receptions and products for the page.
 func (r *PVZRepositoryImpl) ListPVZsWithRelations(
-	startDate, endDate time.Time, limit, offset int) ([]PVZResponse, error) {
-	var rows *sql.Rows
-	var err error
-
-	query := `
-        SELECT 
-            p.id, p.registration_date, p.city,
-            r.id, r.created_at, r.pvz_id, r.status, r.closed_at,
-            pr.id, pr.created_at, pr.type, pr.reception_id
-        FROM pvz p
-        LEFT JOIN intakes r ON p.id = r.pvz_id
-        LEFT JOIN products pr ON r.id = pr.reception_id
-    `
-
-	if !startDate.IsZero() && !endDate.IsZero() {
-		query += " WHERE p.registration_date >= $1 AND p.registration_date <= $2"
-		query += " ORDER BY p.registration_date ASC"
-		query += " LIMIT $3 OFFSET $4"
-		rows, err = r.db.Query(query, startDate, endDate, limit, offset)
-	} else {
-		query += " ORDER BY p.registration_date ASC"
-		query += " LIMIT $1 OFFSET $2"
-		rows, err = r.db.Query(query, limit, offset)
+	ctx context.Context, filter PVZFilter, sort PVZSort, limit, offset int) ([]PVZResponse, int, error) {
+	field := sort.Field
+	if field == "" {
+		field = PVZSortRegistrationDate
+	}
+	column, ok := pvzSortColumns[field]
+	if !ok {
+		return nil, 0, fmt.Errorf("unknown sort field %q", sort.Field)
+	}
+
+	args := &queryArgs{}
+	from := pvzFromClause(filter, filter.needsStats() || field != PVZSortRegistrationDate, args)
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) "+from, args.values...).Scan(&total); err != nil {
+		return nil, 0, err
 	}
 
+	direction := "ASC"
+	if sort.Desc {
+		direction = "DESC"
+	}
+	orderBy := fmt.Sprintf(" ORDER BY %s %s NULLS LAST, p.id", column, direction)
+	if field == PVZSortRegistrationDate {
+		orderBy = fmt.Sprintf(" ORDER BY p.registration_date %s, p.id %s", direction, direction)
+	}
+
+	query := "SELECT p.id, p.registration_date, p.city " + from + orderBy +
+		fmt.Sprintf(" LIMIT %s OFFSET %s", args.add(limit), args.add(offset))
+	result, err := r.loadPVZPage(ctx, query, args.values, filter)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	defer rows.Close()
 
-	pvzMap := make(map[string]*PVZResponse)
-	receptionMap := make(map[string]*struct {
-		reception domain.Reception
-		products  []domain.Product
-	})
+	return result, total, nil
+}
 
-	for rows.Next() {
-		var (
-			pvzID, receptionID, productID sql.NullString
-			pvzRegDate                    sql.NullTime
-			pvzCity                       sql.NullString
-			receptionCreatedAt            sql.NullTime
-			receptionPvzID                sql.NullString
-			receptionStatus               sql.NullString
-			receptionClosedAt             sql.NullTime
-			productCreatedAt              sql.NullTime
-			productType                   sql.NullString
-			productReceptionID            sql.NullString
-		)
+// ListPVZsWithRelationsAfter returns up to limit PVZs that follow the after key in listing order.
+// Unlike offset pagination, concurrent inserts do not shift the rows a caller has not seen yet.
+func (r *PVZRepositoryImpl) ListPVZsWithRelationsAfter(
+	ctx context.Context, filter PVZFilter, after *PVZKey, limit int) ([]PVZResponse, error) {
+	args := &queryArgs{}
+	var keyset []string
+	if after != nil {
+		keyset = append(keyset, fmt.Sprintf("(p.registration_date, p.id) > (%s, %s)",
+			args.add(after.RegistrationDate), args.add(after.ID)))
+	}
+	from := pvzFromClause(filter, filter.needsStats(), args, keyset...)
 
-		if err := rows.Scan(
-			&pvzID, &pvzRegDate, &pvzCity,
-			&receptionID, &receptionCreatedAt, &receptionPvzID, &receptionStatus, &receptionClosedAt,
-			&productID, &productCreatedAt, &productType, &productReceptionID,
-		); err != nil {
-			return nil, err
+	query := "SELECT p.id, p.registration_date, p.city " + from +
+		" ORDER BY p.registration_date, p.id LIMIT " + args.add(limit)
+
+	return r.loadPVZPage(ctx, query, args.values, filter)
+}
+
+// pvzFromClause builds the FROM ... WHERE part shared by the count and page queries.
+// The stats join is only added when a filter or the sort order needs per-PVZ aggregates.
+func pvzFromClause(filter PVZFilter, withStats bool, args *queryArgs, extra ...string) string {
+	from := "FROM pvz p"
+	receptionWhere := receptionConditions("r", filter, args)
+
+	if withStats {
+		statsWhere := "r.pvz_id = p.id"
+		if receptionWhere != "" {
+			statsWhere += " AND " + receptionWhere
 		}
+		from += " LEFT JOIN LATERAL (SELECT COUNT(pr.id) AS product_count, MAX(r.created_at) AS last_reception_at" +
+			" FROM receptions r LEFT JOIN products pr ON pr.reception_id = r.id WHERE " + statsWhere + ") stats ON TRUE"
+	}
 
-		if pvzID.Valid {
-			if _, exists := pvzMap[pvzID.String]; !exists {
-				pvzMap[pvzID.String] = &PVZResponse{
-					PVZ: domain.PVZ{
-						ID:               pvzID.String,
-						RegistrationDate: pvzRegDate.Time,
-						City:             pvzCity.String,
-					},
-					Receptions: []ReceptionResponse{},
-				}
-			}
+	var conditions []string
+	if len(filter.Cities) > 0 {
+		conditions = append(conditions, "p.city = ANY("+args.add(pq.Array(filter.Cities))+")")
+	}
+	if receptionWhere != "" {
+		conditions = append(conditions,
+			"EXISTS (SELECT 1 FROM receptions r WHERE r.pvz_id = p.id AND "+receptionWhere+")")
+	}
+	if filter.HasOpenReception != nil {
+		open := "EXISTS (SELECT 1 FROM receptions o WHERE o.pvz_id = p.id AND o.status = 'in_progress')"
+		if !*filter.HasOpenReception {
+			open = "NOT " + open
 		}
+		conditions = append(conditions, open)
+	}
+	if filter.MinProducts != nil {
+		conditions = append(conditions, "stats.product_count >= "+args.add(*filter.MinProducts))
+	}
+	if filter.MaxProducts != nil {
+		conditions = append(conditions, "stats.product_count <= "+args.add(*filter.MaxProducts))
+	}
 
-		if receptionID.Valid && pvzID.Valid {
-			receptionKey := receptionID.String
-			if _, exists := receptionMap[receptionKey]; !exists {
-				receptionMap[receptionKey] = &struct {
-					reception domain.Reception
-					products  []domain.Product
-				}{
-					reception: domain.Reception{
-						ID:       receptionID.String,
-						DateTime: receptionCreatedAt.Time,
-						PvzId:    receptionPvzID.String,
-						Status:   receptionStatus.String,
-						ClosedAt: utils.NullableTime(receptionClosedAt),
-					},
-					products: []domain.Product{},
-				}
-			}
+	conditions = append(conditions, extra...)
+
+	if len(conditions) > 0 {
+		from += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	return from
+}
+
+// receptionConditions builds the reception-level part of the filter for the given receptions alias.
+func receptionConditions(alias string, filter PVZFilter, args *queryArgs) string {
+	if !filter.hasReceptionConditions() {
+		return ""
+	}
+
+	var conditions []string
+	if !filter.StartDate.IsZero() {
+		conditions = append(conditions, alias+".created_at >= "+args.add(filter.StartDate))
+	}
+	if !filter.EndDate.IsZero() {
+		conditions = append(conditions, alias+".created_at <= "+args.add(filter.EndDate))
+	}
+	if filter.ReceptionStatus != "" {
+		conditions = append(conditions, alias+".status = "+args.add(filter.ReceptionStatus))
+	}
+	if len(filter.ProductTypes) > 0 {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM products t WHERE t.reception_id = "+alias+
+			".id AND t.type = ANY("+args.add(pq.Array(filter.ProductTypes))+"))")
+	}
+
+	return strings.Join(conditions, " AND ")
+}
+
+type queryArgs struct {
+	values []interface{}
+}
+
+// add binds a value and returns its placeholder.
+func (a *queryArgs) add(value interface{}) string {
+	a.values = append(a.values, value)
+	return fmt.Sprintf("$%d", len(a.values))
+}
+
+func (r *PVZRepositoryImpl) loadPVZPage(
+	ctx context.Context, query string, args []interface{}, filter PVZFilter) ([]PVZResponse, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []PVZResponse{}
+	pvzIDs := []string{}
+	for rows.Next() {
+		var pvz domain.PVZ
+		if err := rows.Scan(&pvz.ID, &pvz.RegistrationDate, &pvz.City); err != nil {
+			return nil, err
 		}
+		result = append(result, PVZResponse{PVZ: pvz, Receptions: []ReceptionResponse{}})
+		pvzIDs = append(pvzIDs, pvz.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-		if productID.Valid && productReceptionID.Valid {
-			if reception, exists := receptionMap[productReceptionID.String]; exists {
-				reception.products = append(reception.products, domain.Product{
-					ID:          productID.String,
-					DateTime:    productCreatedAt.Time,
-					Type:        productType.String,
-					ReceptionId: productReceptionID.String,
-				})
-			}
+	if len(pvzIDs) == 0 {
+		return result, nil
+	}
+
+	receptions, err := r.listReceptionsWithProducts(ctx, pvzIDs, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		if items, ok := receptions[result[i].PVZ.ID]; ok {
+			result[i].Receptions = items
 		}
 	}
 
-	if err := rows.Err(); err != nil {
+	return result, nil
+}
+
+func (r *PVZRepositoryImpl) listReceptionsWithProducts(
+	ctx context.Context, pvzIDs []string, filter PVZFilter) (map[string][]ReceptionResponse, error) {
+	args := &queryArgs{}
+	query := "SELECT r.id, r.created_at, r.pvz_id, r.status, r.closed_at FROM receptions r WHERE r.pvz_id = ANY(" +
+		args.add(pq.Array(pvzIDs)) + ")"
+	if where := receptionConditions("r", filter, args); where != "" {
+		query += " AND " + where
+	}
+	query += " ORDER BY r.created_at, r.id"
+
+	rows, err := r.db.QueryContext(ctx, query, args.values...)
+	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	for _, reception := range receptionMap {
-		if pvz, exists := pvzMap[reception.reception.PvzId]; exists {
-			pvz.Receptions = append(pvz.Receptions, ReceptionResponse{
-				Reception: reception.reception,
-				Products:  reception.products,
-			})
+	var receptions []domain.Reception
+	receptionIDs := []string{}
+	for rows.Next() {
+		var reception domain.Reception
+		if err := rows.Scan(
+			&reception.ID, &reception.DateTime, &reception.PvzId, &reception.Status, &reception.ClosedAt,
+		); err != nil {
+			return nil, err
 		}
+		receptions = append(receptions, reception)
+		receptionIDs = append(receptionIDs, reception.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	result := make([]PVZResponse, 0, len(pvzMap))
-	for _, pvz := range pvzMap {
-		result = append(result, *pvz)
+	result := make(map[string][]ReceptionResponse)
+	if len(receptions) == 0 {
+		return result, nil
+	}
+
+	products, err := NewProductRepository(r.db).ListProductsByReceptions(ctx, receptionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, reception := range receptions {
+		items, ok := products[reception.ID]
+		if !ok {
+			items = []domain.Product{}
+		}
+		result[reception.PvzId] = append(result[reception.PvzId], ReceptionResponse{
+			Reception: reception,
+			Products:  items,
+		})
 	}
 
 	return result, nil
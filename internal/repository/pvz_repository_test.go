@@ -1,12 +1,15 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -29,7 +32,7 @@ func TestPVZRepository_CreatePVZ(t *testing.T) {
 			WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).
 				AddRow(pvzID, now, "Москва"))
 
-		pvz, err := repo.CreatePVZ("Москва", func() uuid.UUID {
+		pvz, err := repo.CreatePVZ(context.Background(), "Москва", func() uuid.UUID {
 			return uuid.MustParse(pvzID)
 		})
 
@@ -44,7 +47,7 @@ func TestPVZRepository_CreatePVZ(t *testing.T) {
 			WithArgs(pvzID, "Москва").
 			WillReturnError(sql.ErrConnDone)
 
-		_, err := repo.CreatePVZ("Москва", func() uuid.UUID {
+		_, err := repo.CreatePVZ(context.Background(), "Москва", func() uuid.UUID {
 			return uuid.MustParse(pvzID)
 		})
 
@@ -68,7 +71,7 @@ func TestPVZRepository_GetPVZByID(t *testing.T) {
 			WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).
 				AddRow(pvzID, now, "Москва"))
 
-		pvz, err := repo.GetPVZByID(pvzID)
+		pvz, err := repo.GetPVZByID(context.Background(), pvzID)
 
 		assert.NoError(t, err)
 		assert.Equal(t, pvzID, pvz.ID)
@@ -81,11 +84,48 @@ func TestPVZRepository_GetPVZByID(t *testing.T) {
 			WithArgs(pvzID).
 			WillReturnError(sql.ErrNoRows)
 
-		_, err := repo.GetPVZByID(pvzID)
+		_, err := repo.GetPVZByID(context.Background(), pvzID)
 
 		assert.Error(t, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("context deadline", func(t *testing.T) {
+		mock.ExpectQuery("SELECT id, registration_date, city FROM pvz WHERE id =").
+			WithArgs(pvzID).
+			WillDelayFor(time.Second).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).
+				AddRow(pvzID, now, "Москва"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := repo.GetPVZByID(ctx, pvzID)
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPVZRepository_ListPVZs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPVZRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT id, registration_date, city FROM pvz ORDER BY registration_date, id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).
+			AddRow("pvz1", now, "Москва").
+			AddRow("pvz2", now, "Казань"))
+
+	pvzs, err := repo.ListPVZs(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, pvzs, 2)
+	assert.Equal(t, "Казань", pvzs[1].City)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestPVZRepository_ListPVZsWithRelations(t *testing.T) {
@@ -97,57 +137,237 @@ func TestPVZRepository_ListPVZsWithRelations(t *testing.T) {
 	now := time.Now()
 
 	t.Run("success without date filter", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{
-			"id", "registration_date", "city",
-			"r.id", "r.created_at", "r.pvz_id", "r.status", "r.closed_at",
-			"pr.id", "pr.created_at", "pr.type", "pr.reception_id",
-		}).
-			AddRow(
-				"pvz1", now, "Москва",
-				"rec1", now, "pvz1", "in_progress", nil,
-				"prod1", now, "электроника", "rec1",
-			).
-			AddRow(
-				"pvz1", now, "Москва",
-				"rec1", now, "pvz1", "in_progress", nil,
-				"prod2", now, "одежда", "rec1",
-			).
-			AddRow(
-				"pvz2", now, "Санкт-Петербург",
-				"rec2", now, "pvz2", "closed", now,
-				nil, nil, nil, nil,
-			)
-
-		mock.ExpectQuery(`SELECT .* FROM pvz p`).
-			WillReturnRows(rows)
-
-		result, err := repo.ListPVZsWithRelations(time.Time{}, time.Time{}, 10, 0)
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM pvz p$`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+		mock.ExpectQuery(`SELECT p.id, p.registration_date, p.city FROM pvz p ORDER BY p.registration_date ASC, p.id ASC `+
+			`LIMIT \$1 OFFSET \$2`).
+			WithArgs(2, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).
+				AddRow("pvz1", now, "Москва").
+				AddRow("pvz2", now, "Санкт-Петербург"))
+
+		mock.ExpectQuery(`SELECT r.id, r.created_at, r.pvz_id, r.status, r.closed_at FROM receptions r ` +
+			`WHERE r.pvz_id = ANY\(\$1\) ORDER BY r.created_at, r.id`).
+			WithArgs(pq.Array([]string{"pvz1", "pvz2"})).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "pvz_id", "status", "closed_at"}).
+				AddRow("rec1", now, "pvz1", "close", now).
+				AddRow("rec2", now, "pvz1", "in_progress", nil))
+
+		mock.ExpectQuery(`SELECT id, created_at, type, reception_id, attributes FROM products`).
+			WithArgs(pq.Array([]string{"rec1", "rec2"})).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "type", "reception_id", "attributes"}).
+				AddRow("prod1", now, "электроника", "rec1", []byte(`{}`)).
+				AddRow("prod2", now, "одежда", "rec1", []byte(`{"size":"M"}`)))
+
+		result, total, err := repo.ListPVZsWithRelations(context.Background(), PVZFilter{}, PVZSort{}, 2, 0)
 
 		assert.NoError(t, err)
+		assert.Equal(t, 3, total)
 		assert.Len(t, result, 2)
 
-		foundPVZ1 := false
-		foundPVZ2 := false
-		for _, pvz := range result {
-			if pvz.PVZ.City == "Москва" {
-				foundPVZ1 = true
-				assert.Len(t, pvz.Receptions, 1)
-				assert.Len(t, pvz.Receptions[0].Products, 2)
-				assert.Equal(t, "rec1", pvz.Receptions[0].Reception.ID)
-				assert.Equal(t, "in_progress", pvz.Receptions[0].Reception.Status)
-			}
-			if pvz.PVZ.City == "Санкт-Петербург" {
-				foundPVZ2 = true
-				assert.Len(t, pvz.Receptions, 1)
-				assert.Len(t, pvz.Receptions[0].Products, 0)
-				assert.Equal(t, "rec2", pvz.Receptions[0].Reception.ID)
-				assert.Equal(t, "closed", pvz.Receptions[0].Reception.Status)
-			}
+		assert.Equal(t, "pvz1", result[0].PVZ.ID)
+		assert.Len(t, result[0].Receptions, 2)
+		assert.Equal(t, "rec1", result[0].Receptions[0].Reception.ID)
+		assert.Len(t, result[0].Receptions[0].Products, 2)
+		assert.Equal(t, "prod1", result[0].Receptions[0].Products[0].ID)
+		assert.Equal(t, map[string]string{"size": "M"}, result[0].Receptions[0].Products[1].Attributes)
+		assert.Equal(t, "rec2", result[0].Receptions[1].Reception.ID)
+		assert.Nil(t, result[0].Receptions[1].Reception.ClosedAt)
+		assert.NotNil(t, result[0].Receptions[1].Products)
+		assert.Empty(t, result[0].Receptions[1].Products)
+
+		assert.Equal(t, "pvz2", result[1].PVZ.ID)
+		assert.NotNil(t, result[1].Receptions)
+		assert.Empty(t, result[1].Receptions)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("date filter applies to receptions", func(t *testing.T) {
+		start := now.Add(-24 * time.Hour)
+		end := now
+
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM pvz p WHERE EXISTS \(SELECT 1 FROM receptions r `+
+			`WHERE r.pvz_id = p.id AND r.created_at >= \$1 AND r.created_at <= \$2\)`).
+			WithArgs(start, end).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+		mock.ExpectQuery(`SELECT p.id, p.registration_date, p.city FROM pvz p WHERE EXISTS .* `+
+			`ORDER BY p.registration_date ASC, p.id ASC LIMIT \$3 OFFSET \$4`).
+			WithArgs(start, end, 10, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).
+				AddRow("pvz1", now, "Москва"))
+
+		mock.ExpectQuery(`FROM receptions r WHERE r.pvz_id = ANY\(\$1\) AND r.created_at >= \$2 AND r.created_at <= \$3`).
+			WithArgs(pq.Array([]string{"pvz1"}), start, end).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "pvz_id", "status", "closed_at"}))
+
+		result, total, err := repo.ListPVZsWithRelations(
+			context.Background(), PVZFilter{StartDate: start, EndDate: end}, PVZSort{}, 10, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Len(t, result, 1)
+		assert.Empty(t, result[0].Receptions)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("filters and sort are pushed down", func(t *testing.T) {
+		hasOpen := false
+		minProducts, maxProducts := 1, 50
+		filter := PVZFilter{
+			Cities:           []string{"Москва", "Казань"},
+			ReceptionStatus:  "close",
+			ProductTypes:     []string{"обувь"},
+			HasOpenReception: &hasOpen,
+			MinProducts:      &minProducts,
+			MaxProducts:      &maxProducts,
 		}
 
-		assert.True(t, foundPVZ1)
-		assert.True(t, foundPVZ2)
+		where := `FROM pvz p LEFT JOIN LATERAL \(SELECT COUNT\(pr.id\) AS product_count, ` +
+			`MAX\(r.created_at\) AS last_reception_at FROM receptions r LEFT JOIN products pr ON pr.reception_id = r.id ` +
+			`WHERE r.pvz_id = p.id AND r.status = \$1 AND EXISTS \(SELECT 1 FROM products t WHERE t.reception_id = r.id ` +
+			`AND t.type = ANY\(\$2\)\)\) stats ON TRUE ` +
+			`WHERE p.city = ANY\(\$3\) AND EXISTS \(SELECT 1 FROM receptions r WHERE r.pvz_id = p.id AND r.status = \$1 .*\) ` +
+			`AND NOT EXISTS \(SELECT 1 FROM receptions o WHERE o.pvz_id = p.id AND o.status = 'in_progress'\) ` +
+			`AND stats.product_count >= \$4 AND stats.product_count <= \$5`
+		args := []driver.Value{"close", pq.Array([]string{"обувь"}), pq.Array([]string{"Москва", "Казань"}), 1, 50}
+
+		mock.ExpectQuery(`SELECT COUNT\(\*\) ` + where + `$`).
+			WithArgs(args...).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+		mock.ExpectQuery(`SELECT p.id, p.registration_date, p.city ` + where +
+			` ORDER BY stats.product_count DESC NULLS LAST, p.id LIMIT \$6 OFFSET \$7`).
+			WithArgs(append(args, 10, 0)...).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).
+				AddRow("pvz1", now, "Москва"))
 
+		mock.ExpectQuery(`FROM receptions r WHERE r.pvz_id = ANY\(\$1\) AND r.status = \$2 `+
+			`AND EXISTS \(SELECT 1 FROM products t WHERE t.reception_id = r.id AND t.type = ANY\(\$3\)\) `+
+			`ORDER BY r.created_at, r.id`).
+			WithArgs(pq.Array([]string{"pvz1"}), "close", pq.Array([]string{"обувь"})).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "pvz_id", "status", "closed_at"}).
+				AddRow("rec1", now, "pvz1", "close", now))
+
+		mock.ExpectQuery(`FROM products`).
+			WithArgs(pq.Array([]string{"rec1"})).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "type", "reception_id", "attributes"}).
+				AddRow("prod1", now, "обувь", "rec1", []byte(`{}`)))
+
+		result, total, err := repo.ListPVZsWithRelations(
+			context.Background(), filter, PVZSort{Field: PVZSortProductCount, Desc: true}, 10, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Len(t, result, 1)
+		assert.Len(t, result[0].Receptions, 1)
+		assert.Len(t, result[0].Receptions[0].Products, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("sort by last reception uses stats", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM pvz p LEFT JOIN LATERAL .* stats ON TRUE$`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery(`ORDER BY stats.last_reception_at ASC NULLS LAST, p.id LIMIT \$1 OFFSET \$2`).
+			WithArgs(10, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}))
+
+		_, _, err := repo.ListPVZsWithRelations(
+			context.Background(), PVZFilter{}, PVZSort{Field: PVZSortLastReception}, 10, 0)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("registration date descending", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM pvz p$`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery(`ORDER BY p.registration_date DESC, p.id DESC LIMIT \$1 OFFSET \$2`).
+			WithArgs(10, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}))
+
+		_, _, err := repo.ListPVZsWithRelations(
+			context.Background(), PVZFilter{}, PVZSort{Field: PVZSortRegistrationDate, Desc: true}, 10, 0)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown sort field", func(t *testing.T) {
+		_, _, err := repo.ListPVZsWithRelations(context.Background(), PVZFilter{}, PVZSort{Field: "city"}, 10, 0)
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("empty page skips relations", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM pvz p`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectQuery(`SELECT p.id, p.registration_date, p.city FROM pvz p`).
+			WithArgs(10, 20).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}))
+
+		result, total, err := repo.ListPVZsWithRelations(context.Background(), PVZFilter{}, PVZSort{}, 10, 20)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.NotNil(t, result)
+		assert.Empty(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("count error", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM pvz p`).
+			WillReturnError(sql.ErrConnDone)
+
+		_, _, err := repo.ListPVZsWithRelations(context.Background(), PVZFilter{}, PVZSort{}, 10, 0)
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPVZRepository_ListPVZsWithRelationsAfter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPVZRepository(db)
+	now := time.Now()
+
+	t.Run("first page", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT p.id, p.registration_date, p.city FROM pvz p ORDER BY p.registration_date, p.id LIMIT \$1$`).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}))
+
+		result, err := repo.ListPVZsWithRelationsAfter(context.Background(), PVZFilter{}, nil, 3)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("after key with date filter", func(t *testing.T) {
+		start := now.Add(-time.Hour)
+		after := &PVZKey{RegistrationDate: now, ID: "pvz1"}
+
+		mock.ExpectQuery(`FROM pvz p WHERE EXISTS \(.*r.created_at >= \$3\) `+
+			`AND \(p.registration_date, p.id\) > \(\$1, \$2\) ORDER BY p.registration_date, p.id LIMIT \$4`).
+			WithArgs(now, "pvz1", start, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).
+				AddRow("pvz2", now, "Казань"))
+
+		mock.ExpectQuery(`FROM receptions r WHERE r.pvz_id = ANY\(\$1\) AND r.created_at >= \$2`).
+			WithArgs(pq.Array([]string{"pvz2"}), start).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "pvz_id", "status", "closed_at"}))
+
+		result, err := repo.ListPVZsWithRelationsAfter(context.Background(), PVZFilter{StartDate: start}, after, 2)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, "pvz2", result[0].PVZ.ID)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
@@ -1,46 +1,88 @@
 package repository
 
 import (
-	"database/sql"
+	"context"
 	"github.com/google/uuid"
 	"time"
 
 	"pvz-service/internal/domain"
 )
 
+// openReceptionIndex is the partial unique index allowing one in_progress
+// reception per PVZ.
+const openReceptionIndex = "receptions_one_open_per_pvz"
+
+// receptionPVZForeignKey is the default name Postgres gives receptions.pvz_id REFERENCES pvz(id).
+const receptionPVZForeignKey = "receptions_pvz_id_fkey"
+
 type ReceptionRepository interface {
-	CreateReception(pvzID string, idGenerator func() uuid.UUID) (string, error)
-	GetReceptionByID(id string) (domain.Reception, error)
-	GetOpenReception(pvzID string) (domain.Reception, error)
-	CloseReception(id string, closeTime time.Time) error
-	HasOpenReception(pvzID string) (bool, error)
+	CreateReception(ctx context.Context, pvzID string, idGenerator func() uuid.UUID) (string, error)
+	GetReceptionByID(ctx context.Context, id string) (domain.Reception, error)
+	GetOpenReception(ctx context.Context, pvzID string) (domain.Reception, error)
+	GetOpenReceptionForUpdate(ctx context.Context, pvzID string) (domain.Reception, error)
+	CloseReception(ctx context.Context, id string, closeTime time.Time) error
+	ListReceptionsByPVZ(
+		ctx context.Context, pvzID string, filter ReceptionFilter, limit, offset int) ([]domain.Reception, int, error)
+}
+
+// ReceptionFilter narrows ListReceptionsByPVZ; zero values are not applied.
+type ReceptionFilter struct {
+	Status    string
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+type ReceptionWithProductsListResponse struct {
+	Items      []ReceptionResponse `json:"items"`
+	Total      int                 `json:"total"`
+	Page       int                 `json:"page"`
+	Limit      int                 `json:"limit"`
+	TotalPages int                 `json:"totalPages"`
+}
+
+type ReceptionListResponse struct {
+	Items      []domain.Reception `json:"items"`
+	Total      int                `json:"total"`
+	Page       int                `json:"page"`
+	Limit      int                `json:"limit"`
+	TotalPages int                `json:"totalPages"`
 }
 
 type ReceptionRepositoryImpl struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewReceptionRepository(db *sql.DB) *ReceptionRepositoryImpl {
+func NewReceptionRepository(db DBTX) *ReceptionRepositoryImpl {
 	return &ReceptionRepositoryImpl{db: db}
 }
 
-func (r *ReceptionRepositoryImpl) CreateReception(pvzID string, idGenerator func() uuid.UUID) (string, error) {
+func (r *ReceptionRepositoryImpl) CreateReception(
+	ctx context.Context, pvzID string, idGenerator func() uuid.UUID) (string, error) {
 	receptionID := idGenerator().String()
-	_, err := r.db.Exec("INSERT INTO receptions (id, pvz_id, status, created_at) VALUES ($1, $2, $3, $4)",
+	_, err := r.db.ExecContext(ctx, "INSERT INTO receptions (id, pvz_id, status, created_at) VALUES ($1, $2, $3, $4)",
 		receptionID, pvzID, "in_progress", time.Now())
-	return receptionID, err
+	if err != nil {
+		if isUniqueViolationOf(err, openReceptionIndex) {
+			return "", domain.ErrOpenReceptionExists
+		}
+		if isForeignKeyViolationOf(err, receptionPVZForeignKey) {
+			return "", domain.ErrPVZNotFound
+		}
+		return "", err
+	}
+	return receptionID, nil
 }
 
-func (r *ReceptionRepositoryImpl) GetReceptionByID(id string) (domain.Reception, error) {
+func (r *ReceptionRepositoryImpl) GetReceptionByID(ctx context.Context, id string) (domain.Reception, error) {
 	var reception domain.Reception
-	err := r.db.QueryRow("SELECT id, created_at, pvz_id, status, closed_at FROM receptions WHERE id = $1", id).
+	err := r.db.QueryRowContext(ctx, "SELECT id, created_at, pvz_id, status, closed_at FROM receptions WHERE id = $1", id).
 		Scan(&reception.ID, &reception.DateTime, &reception.PvzId, &reception.Status, &reception.ClosedAt)
 	return reception, err
 }
 
-func (r *ReceptionRepositoryImpl) GetOpenReception(pvzID string) (domain.Reception, error) {
+func (r *ReceptionRepositoryImpl) GetOpenReception(ctx context.Context, pvzID string) (domain.Reception, error) {
 	var reception domain.Reception
-	err := r.db.QueryRow(
+	err := r.db.QueryRowContext(ctx,
 		`SELECT id, created_at, pvz_id, status, closed_at
 			   FROM receptions
 			   WHERE pvz_id = $1 AND status = 'in_progress'`,
@@ -49,17 +91,67 @@ func (r *ReceptionRepositoryImpl) GetOpenReception(pvzID string) (domain.Recepti
 	return reception, err
 }
 
-func (r *ReceptionRepositoryImpl) CloseReception(id string, closeTime time.Time) error {
-	_, err := r.db.Exec("UPDATE receptions SET status = 'close', closed_at = $1 WHERE id = $2",
+// GetOpenReceptionForUpdate locks the open reception row until the surrounding
+// transaction ends, so it must be called through a UnitOfWork.
+func (r *ReceptionRepositoryImpl) GetOpenReceptionForUpdate(
+	ctx context.Context, pvzID string) (domain.Reception, error) {
+	var reception domain.Reception
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id, created_at, pvz_id, status, closed_at
+			   FROM receptions
+			   WHERE pvz_id = $1 AND status = 'in_progress'
+			   FOR UPDATE`,
+		pvzID).
+		Scan(&reception.ID, &reception.DateTime, &reception.PvzId, &reception.Status, &reception.ClosedAt)
+	return reception, err
+}
+
+func (r *ReceptionRepositoryImpl) CloseReception(ctx context.Context, id string, closeTime time.Time) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE receptions SET status = 'close', closed_at = $1 WHERE id = $2",
 		closeTime, id)
 	return err
 }
 
-func (r *ReceptionRepositoryImpl) HasOpenReception(pvzID string) (bool, error) {
-	var exists bool
-	err := r.db.QueryRow(
-		"SELECT EXISTS (SELECT 1 FROM receptions WHERE pvz_id = $1 AND status = 'in_progress')",
-		pvzID).
-		Scan(&exists)
-	return exists, err
+func (r *ReceptionRepositoryImpl) ListReceptionsByPVZ(
+	ctx context.Context, pvzID string, filter ReceptionFilter, limit, offset int) ([]domain.Reception, int, error) {
+	args := &queryArgs{}
+	where := " WHERE pvz_id = " + args.add(pvzID)
+	if filter.Status != "" {
+		where += " AND status = " + args.add(filter.Status)
+	}
+	if !filter.StartDate.IsZero() {
+		where += " AND created_at >= " + args.add(filter.StartDate)
+	}
+	if !filter.EndDate.IsZero() {
+		where += " AND created_at <= " + args.add(filter.EndDate)
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM receptions"+where, args.values...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT id, created_at, pvz_id, status, closed_at FROM receptions" + where +
+		" ORDER BY created_at, id LIMIT " + args.add(limit) + " OFFSET " + args.add(offset)
+	rows, err := r.db.QueryContext(ctx, query, args.values...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	receptions := []domain.Reception{}
+	for rows.Next() {
+		var reception domain.Reception
+		if err := rows.Scan(
+			&reception.ID, &reception.DateTime, &reception.PvzId, &reception.Status, &reception.ClosedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		receptions = append(receptions, reception)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return receptions, total, nil
 }
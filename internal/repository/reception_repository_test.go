@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"testing"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"pvz-service/internal/domain"
 )
@@ -29,13 +31,53 @@ func TestCreateReception(t *testing.T) {
 			WithArgs(expectedID.String(), pvzID, "in_progress", sqlmock.AnyArg()).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
-		id, err := repo.CreateReception(pvzID, func() uuid.UUID { return expectedID })
+		id, err := repo.CreateReception(context.Background(), pvzID, func() uuid.UUID { return expectedID })
 
 		assert.NoError(t, err)
 		assert.Equal(t, expectedID.String(), id)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
+	t.Run("open reception already exists", func(t *testing.T) {
+		pvzID := uuid.New().String()
+
+		mock.ExpectExec("INSERT INTO receptions").
+			WithArgs(sqlmock.AnyArg(), pvzID, "in_progress", sqlmock.AnyArg()).
+			WillReturnError(&pq.Error{Code: "23505", Constraint: "receptions_one_open_per_pvz"})
+
+		_, err := repo.CreateReception(context.Background(), pvzID, uuid.New)
+
+		assert.ErrorIs(t, err, domain.ErrOpenReceptionExists)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("pvz does not exist", func(t *testing.T) {
+		pvzID := uuid.New().String()
+
+		mock.ExpectExec("INSERT INTO receptions").
+			WithArgs(sqlmock.AnyArg(), pvzID, "in_progress", sqlmock.AnyArg()).
+			WillReturnError(&pq.Error{Code: "23503", Constraint: "receptions_pvz_id_fkey"})
+
+		_, err := repo.CreateReception(context.Background(), pvzID, uuid.New)
+
+		assert.ErrorIs(t, err, domain.ErrPVZNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("other unique violation", func(t *testing.T) {
+		pvzID := uuid.New().String()
+
+		mock.ExpectExec("INSERT INTO receptions").
+			WithArgs(sqlmock.AnyArg(), pvzID, "in_progress", sqlmock.AnyArg()).
+			WillReturnError(&pq.Error{Code: "23505", Constraint: "receptions_pkey"})
+
+		_, err := repo.CreateReception(context.Background(), pvzID, uuid.New)
+
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrOpenReceptionExists)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
 	t.Run("database error", func(t *testing.T) {
 		pvzID := uuid.New().String()
 		expectedError := errors.New("database error")
@@ -44,7 +86,7 @@ func TestCreateReception(t *testing.T) {
 			WithArgs(sqlmock.AnyArg(), pvzID, "in_progress", sqlmock.AnyArg()).
 			WillReturnError(expectedError)
 
-		_, err := repo.CreateReception(pvzID, uuid.New)
+		_, err := repo.CreateReception(context.Background(), pvzID, uuid.New)
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedError, err)
@@ -82,7 +124,7 @@ func TestGetReceptionByID(t *testing.T) {
 			WithArgs(receptionID).
 			WillReturnRows(rows)
 
-		reception, err := repo.GetReceptionByID(receptionID)
+		reception, err := repo.GetReceptionByID(context.Background(), receptionID)
 
 		assert.NoError(t, err)
 		assert.Equal(t, expectedReception, reception)
@@ -96,7 +138,7 @@ func TestGetReceptionByID(t *testing.T) {
 			WithArgs(receptionID).
 			WillReturnError(sql.ErrNoRows)
 
-		_, err := repo.GetReceptionByID(receptionID)
+		_, err := repo.GetReceptionByID(context.Background(), receptionID)
 
 		assert.Error(t, err)
 		assert.Equal(t, sql.ErrNoRows, err)
@@ -111,7 +153,7 @@ func TestGetReceptionByID(t *testing.T) {
 			WithArgs(receptionID).
 			WillReturnError(expectedError)
 
-		_, err := repo.GetReceptionByID(receptionID)
+		_, err := repo.GetReceptionByID(context.Background(), receptionID)
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedError, err)
@@ -148,7 +190,7 @@ func TestGetOpenReception(t *testing.T) {
 			WithArgs(pvzID).
 			WillReturnRows(rows)
 
-		reception, err := repo.GetOpenReception(pvzID)
+		reception, err := repo.GetOpenReception(context.Background(), pvzID)
 
 		assert.NoError(t, err)
 		assert.Equal(t, expectedReception, reception)
@@ -162,7 +204,7 @@ func TestGetOpenReception(t *testing.T) {
 			WithArgs(pvzID).
 			WillReturnError(sql.ErrNoRows)
 
-		_, err := repo.GetOpenReception(pvzID)
+		_, err := repo.GetOpenReception(context.Background(), pvzID)
 
 		assert.Error(t, err)
 		assert.Equal(t, sql.ErrNoRows, err)
@@ -170,6 +212,32 @@ func TestGetOpenReception(t *testing.T) {
 	})
 }
 
+func TestGetOpenReceptionForUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewReceptionRepository(db)
+
+	pvzID := uuid.New().String()
+	receptionID := uuid.New().String()
+
+	rows := sqlmock.NewRows([]string{"id", "created_at", "pvz_id", "status", "closed_at"}).
+		AddRow(receptionID, time.Now(), pvzID, "in_progress", nil)
+
+	mock.ExpectQuery("SELECT id, created_at, pvz_id, status, closed_at FROM receptions WHERE pvz_id = \\$1 AND status = 'in_progress' FOR UPDATE").
+		WithArgs(pvzID).
+		WillReturnRows(rows)
+
+	reception, err := repo.GetOpenReceptionForUpdate(context.Background(), pvzID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, receptionID, reception.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestCloseReception(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -187,7 +255,7 @@ func TestCloseReception(t *testing.T) {
 			WithArgs(closeTime, receptionID).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
-		err := repo.CloseReception(receptionID, closeTime)
+		err := repo.CloseReception(context.Background(), receptionID, closeTime)
 
 		assert.NoError(t, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -201,7 +269,7 @@ func TestCloseReception(t *testing.T) {
 			WithArgs(closeTime, receptionID).
 			WillReturnResult(sqlmock.NewResult(0, 0))
 
-		err := repo.CloseReception(receptionID, closeTime)
+		err := repo.CloseReception(context.Background(), receptionID, closeTime)
 
 		assert.NoError(t, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -216,7 +284,7 @@ func TestCloseReception(t *testing.T) {
 			WithArgs(closeTime, receptionID).
 			WillReturnError(expectedError)
 
-		err := repo.CloseReception(receptionID, closeTime)
+		err := repo.CloseReception(context.Background(), receptionID, closeTime)
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedError, err)
@@ -224,59 +292,64 @@ func TestCloseReception(t *testing.T) {
 	})
 }
 
-func TestHasOpenReception(t *testing.T) {
+func TestReceptionRepository_ListReceptionsByPVZ(t *testing.T) {
 	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
+	assert.NoError(t, err)
 	defer db.Close()
 
 	repo := NewReceptionRepository(db)
+	pvzID := uuid.New().String()
+	now := time.Now()
 
-	t.Run("has open reception", func(t *testing.T) {
-		pvzID := uuid.New().String()
-
-		rows := sqlmock.NewRows([]string{"exists"}).AddRow(true)
-
-		mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM receptions WHERE pvz_id = \\$1 AND status = 'in_progress'\\)").
+	t.Run("without filter", func(t *testing.T) {
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM receptions WHERE pvz_id = \\$1$").
 			WithArgs(pvzID).
-			WillReturnRows(rows)
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectQuery("SELECT id, created_at, pvz_id, status, closed_at FROM receptions WHERE pvz_id = \\$1 "+
+			"ORDER BY created_at, id LIMIT \\$2 OFFSET \\$3").
+			WithArgs(pvzID, 10, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "pvz_id", "status", "closed_at"}).
+				AddRow("rec1", now, pvzID, "close", now).
+				AddRow("rec2", now, pvzID, "in_progress", nil))
 
-		exists, err := repo.HasOpenReception(pvzID)
+		receptions, total, err := repo.ListReceptionsByPVZ(context.Background(), pvzID, ReceptionFilter{}, 10, 0)
 
 		assert.NoError(t, err)
-		assert.True(t, exists)
+		assert.Equal(t, 2, total)
+		assert.Len(t, receptions, 2)
+		assert.Equal(t, "rec1", receptions[0].ID)
+		assert.Nil(t, receptions[1].ClosedAt)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
-	t.Run("no open reception", func(t *testing.T) {
-		pvzID := uuid.New().String()
-
-		rows := sqlmock.NewRows([]string{"exists"}).AddRow(false)
+	t.Run("with filter", func(t *testing.T) {
+		filter := ReceptionFilter{Status: "close", StartDate: now.Add(-time.Hour), EndDate: now}
+		where := "WHERE pvz_id = \\$1 AND status = \\$2 AND created_at >= \\$3 AND created_at <= \\$4"
 
-		mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM receptions WHERE pvz_id = \\$1 AND status = 'in_progress'\\)").
-			WithArgs(pvzID).
-			WillReturnRows(rows)
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM receptions "+where).
+			WithArgs(pvzID, "close", filter.StartDate, filter.EndDate).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery("FROM receptions "+where+" ORDER BY created_at, id LIMIT \\$5 OFFSET \\$6").
+			WithArgs(pvzID, "close", filter.StartDate, filter.EndDate, 5, 5).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "pvz_id", "status", "closed_at"}))
 
-		exists, err := repo.HasOpenReception(pvzID)
+		receptions, total, err := repo.ListReceptionsByPVZ(context.Background(), pvzID, filter, 5, 5)
 
 		assert.NoError(t, err)
-		assert.False(t, exists)
+		assert.Equal(t, 0, total)
+		assert.NotNil(t, receptions)
+		assert.Empty(t, receptions)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
-	t.Run("database error", func(t *testing.T) {
-		pvzID := uuid.New().String()
-		expectedError := errors.New("database error")
-
-		mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM receptions WHERE pvz_id = \\$1 AND status = 'in_progress'\\)").
+	t.Run("count error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT COUNT").
 			WithArgs(pvzID).
-			WillReturnError(expectedError)
+			WillReturnError(sql.ErrConnDone)
 
-		_, err := repo.HasOpenReception(pvzID)
+		_, _, err := repo.ListReceptionsByPVZ(context.Background(), pvzID, ReceptionFilter{}, 10, 0)
 
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type SessionRepository interface {
+	CreateSession(ctx context.Context, userID, refreshTokenHash, accessJTI string, expiresAt time.Time) error
+	RotateSession(ctx context.Context, refreshTokenHash, newRefreshTokenHash, accessJTI string,
+		expiresAt, now time.Time) (string, string, error)
+	RevokeSessionByAccessJTI(ctx context.Context, accessJTI string, now time.Time) error
+	RevokeUserSessions(ctx context.Context, userID string, now time.Time) error
+	RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+type SessionRepositoryImpl struct {
+	db DBTX
+}
+
+func NewSessionRepository(db DBTX) *SessionRepositoryImpl {
+	return &SessionRepositoryImpl{db: db}
+}
+
+func (r *SessionRepositoryImpl) CreateSession(
+	ctx context.Context, userID, refreshTokenHash, accessJTI string, expiresAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO sessions (user_id, refresh_token_hash, access_jti, expires_at)
+		VALUES ($1, $2, $3, $4)`,
+		userID, refreshTokenHash, accessJTI, expiresAt,
+	)
+	return err
+}
+
+// RotateSession swaps the refresh token of a live session for a new one in a single
+// statement, so a refresh token can be exchanged only once. It returns the session's
+// user id and role, or sql.ErrNoRows if the token is unknown, revoked or expired, or
+// the user is disabled.
+func (r *SessionRepositoryImpl) RotateSession(ctx context.Context, refreshTokenHash, newRefreshTokenHash,
+	accessJTI string, expiresAt, now time.Time) (string, string, error) {
+	var userID, role string
+	err := r.db.QueryRowContext(ctx,
+		`WITH s AS (
+			UPDATE sessions SET refresh_token_hash = $2, access_jti = $3, expires_at = $4
+			WHERE refresh_token_hash = $1 AND revoked_at IS NULL AND expires_at > $5
+			RETURNING user_id
+		)
+		SELECT u.id, u.role FROM s JOIN users u ON u.id = s.user_id WHERE NOT u.disabled`,
+		refreshTokenHash, newRefreshTokenHash, accessJTI, expiresAt, now,
+	).Scan(&userID, &role)
+	return userID, role, err
+}
+
+func (r *SessionRepositoryImpl) RevokeSessionByAccessJTI(ctx context.Context, accessJTI string, now time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE sessions SET revoked_at = $2 WHERE access_jti = $1 AND revoked_at IS NULL",
+		accessJTI, now,
+	)
+	return err
+}
+
+func (r *SessionRepositoryImpl) RevokeUserSessions(ctx context.Context, userID string, now time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE sessions SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL",
+		userID, now,
+	)
+	return err
+}
+
+// RevokeToken adds an access token to the revocation list and drops entries
+// whose tokens have expired on their own.
+func (r *SessionRepositoryImpl) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM revoked_tokens WHERE expires_at < NOW()"); err != nil {
+		return err
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		"INSERT INTO revoked_tokens (jti, expires_at) VALUES ($1, $2) ON CONFLICT (jti) DO NOTHING",
+		jti, expiresAt,
+	)
+	return err
+}
+
+func (r *SessionRepositoryImpl) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var revoked bool
+	err := r.db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)", jti,
+	).Scan(&revoked)
+	return revoked, err
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionRepository_CreateSession(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSessionRepository(db)
+	expiresAt := time.Now().Add(time.Hour)
+
+	mock.ExpectExec("INSERT INTO sessions").
+		WithArgs("user1", "hash1", "jti1", expiresAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.CreateSession(context.Background(), "user1", "hash1", "jti1", expiresAt)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_RotateSession(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSessionRepository(db)
+	now := time.Now()
+	expiresAt := now.Add(time.Hour)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery("UPDATE sessions SET refresh_token_hash = \\$2").
+			WithArgs("old", "new", "jti2", expiresAt, now).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "role"}).AddRow("user1", "employee"))
+
+		userID, role, err := repo.RotateSession(context.Background(), "old", "new", "jti2", expiresAt, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user1", userID)
+		assert.Equal(t, "employee", role)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no live session", func(t *testing.T) {
+		mock.ExpectQuery("UPDATE sessions").
+			WithArgs("old", "new", "jti2", expiresAt, now).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "role"}))
+
+		_, _, err := repo.RotateSession(context.Background(), "old", "new", "jti2", expiresAt, now)
+
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSessionRepository_Revocation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSessionRepository(db)
+	now := time.Now()
+
+	mock.ExpectExec("UPDATE sessions SET revoked_at").
+		WithArgs("jti1", now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM revoked_tokens WHERE expires_at").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO revoked_tokens").
+		WithArgs("jti1", now.Add(time.Hour)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs("jti1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	assert.NoError(t, repo.RevokeSessionByAccessJTI(context.Background(), "jti1", now))
+	assert.NoError(t, repo.RevokeToken(context.Background(), "jti1", now.Add(time.Hour)))
+
+	revoked, err := repo.IsTokenRevoked(context.Background(), "jti1")
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_RevokeUserSessions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSessionRepository(db)
+	now := time.Now()
+
+	mock.ExpectExec("UPDATE sessions SET revoked_at = \\$2 WHERE user_id = \\$1").
+		WithArgs("user1", now).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	assert.NoError(t, repo.RevokeUserSessions(context.Background(), "user1", now))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
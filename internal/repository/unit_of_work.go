@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// DBTX is implemented by both *sql.DB and *sql.Tx, so repositories can run
+// either directly against the pool or inside a transaction.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type UnitOfWork interface {
+	PVZ() PVZRepository
+	Receptions() ReceptionRepository
+	Products() ProductRepository
+}
+
+type TxManager interface {
+	WithinTransaction(ctx context.Context, fn func(uow UnitOfWork) error) error
+}
+
+type TxManagerImpl struct {
+	db *sql.DB
+}
+
+func NewTxManager(db *sql.DB) *TxManagerImpl {
+	return &TxManagerImpl{db: db}
+}
+
+func (m *TxManagerImpl) WithinTransaction(ctx context.Context, fn func(uow UnitOfWork) error) (err error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(&unitOfWork{tx: tx}); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+type unitOfWork struct {
+	tx *sql.Tx
+}
+
+func (u *unitOfWork) PVZ() PVZRepository {
+	return NewPVZRepository(u.tx)
+}
+
+func (u *unitOfWork) Receptions() ReceptionRepository {
+	return NewReceptionRepository(u.tx)
+}
+
+func (u *unitOfWork) Products() ProductRepository {
+	return NewProductRepository(u.tx)
+}
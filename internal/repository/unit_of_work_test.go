@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxManager_WithinTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	manager := NewTxManager(db)
+	ctx := context.Background()
+
+	t.Run("commit on success", func(t *testing.T) {
+		pvzID := uuid.NewString()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE receptions SET status = 'close'").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM products").
+			WithArgs(pvzID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := manager.WithinTransaction(ctx, func(uow UnitOfWork) error {
+			if err := uow.Receptions().CloseReception(ctx, uuid.NewString(), time.Now()); err != nil {
+				return err
+			}
+			return uow.Products().DeleteProduct(ctx, pvzID)
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rollback on error", func(t *testing.T) {
+		expectedError := errors.New("no open reception")
+
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		err := manager.WithinTransaction(ctx, func(uow UnitOfWork) error {
+			return expectedError
+		})
+
+		assert.Equal(t, expectedError, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rollback on panic", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		assert.Panics(t, func() {
+			_ = manager.WithinTransaction(ctx, func(uow UnitOfWork) error {
+				panic("boom")
+			})
+		})
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("begin error", func(t *testing.T) {
+		mock.ExpectBegin().WillReturnError(errors.New("connection refused"))
+
+		err := manager.WithinTransaction(ctx, func(uow UnitOfWork) error {
+			t.Fatal("callback must not run without a transaction")
+			return nil
+		})
+
+		assert.ErrorContains(t, err, "begin transaction")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
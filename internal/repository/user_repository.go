@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"pvz-service/internal/domain"
+)
+
+type UserRepository interface {
+	ListUsers(ctx context.Context, filter UserFilter, limit, offset int) ([]domain.User, int, error)
+	GetUserByID(ctx context.Context, id string) (domain.User, error)
+	UpdateUser(ctx context.Context, id string, role *string, disabled *bool) (domain.User, error)
+	DeleteUser(ctx context.Context, id string) error
+}
+
+// UserFilter narrows ListUsers; zero values are not applied.
+type UserFilter struct {
+	Role     string
+	Disabled *bool
+}
+
+type UserListResponse struct {
+	Items      []domain.User `json:"items"`
+	Total      int           `json:"total"`
+	Page       int           `json:"page"`
+	Limit      int           `json:"limit"`
+	TotalPages int           `json:"totalPages"`
+}
+
+type UserRepositoryImpl struct {
+	db DBTX
+}
+
+func NewUserRepository(db DBTX) *UserRepositoryImpl {
+	return &UserRepositoryImpl{db: db}
+}
+
+func (r *UserRepositoryImpl) ListUsers(
+	ctx context.Context, filter UserFilter, limit, offset int) ([]domain.User, int, error) {
+	args := &queryArgs{}
+	where := " WHERE TRUE"
+	if filter.Role != "" {
+		where += " AND role = " + args.add(filter.Role)
+	}
+	if filter.Disabled != nil {
+		where += " AND disabled = " + args.add(*filter.Disabled)
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users"+where, args.values...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT id, email, role, disabled, created_at FROM users" + where +
+		" ORDER BY created_at, id LIMIT " + args.add(limit) + " OFFSET " + args.add(offset)
+	rows, err := r.db.QueryContext(ctx, query, args.values...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := []domain.User{}
+	for rows.Next() {
+		var user domain.User
+		if err := rows.Scan(&user.ID, &user.Email, &user.Role, &user.Disabled, &user.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		users = append(users, user)
+	}
+
+	return users, total, rows.Err()
+}
+
+func (r *UserRepositoryImpl) GetUserByID(ctx context.Context, id string) (domain.User, error) {
+	var user domain.User
+	err := r.db.QueryRowContext(ctx,
+		"SELECT id, email, role, disabled, created_at FROM users WHERE id = $1",
+		id,
+	).Scan(&user.ID, &user.Email, &user.Role, &user.Disabled, &user.CreatedAt)
+	return user, err
+}
+
+// UpdateUser changes the role and/or disabled flag; nil values are left as is.
+// PVZ assignments are dropped when the user stops being an employee. It returns
+// sql.ErrNoRows if the user does not exist.
+func (r *UserRepositoryImpl) UpdateUser(
+	ctx context.Context, id string, role *string, disabled *bool) (domain.User, error) {
+	var user domain.User
+	err := r.db.QueryRowContext(ctx,
+		`WITH updated AS (
+			UPDATE users SET role = COALESCE($2, role), disabled = COALESCE($3, disabled)
+			WHERE id = $1
+			RETURNING id, email, role, disabled, created_at
+		), unassigned AS (
+			DELETE FROM user_pvz_assignments a USING updated u
+			WHERE a.user_id = u.id AND u.role <> 'employee'
+		)
+		SELECT id, email, role, disabled, created_at FROM updated`,
+		id, role, disabled,
+	).Scan(&user.ID, &user.Email, &user.Role, &user.Disabled, &user.CreatedAt)
+	return user, err
+}
+
+// DeleteUser returns sql.ErrNoRows if the user does not exist. Sessions and PVZ
+// assignments are removed by the foreign keys.
+func (r *UserRepositoryImpl) DeleteUser(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM users WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
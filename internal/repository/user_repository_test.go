@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var userColumns = []string{"id", "email", "role", "disabled", "created_at"}
+
+func TestUserRepository_ListUsers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+	disabled := false
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users WHERE TRUE AND role = \\$1 AND disabled = \\$2").
+		WithArgs("employee", false).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("SELECT id, email, role, disabled, created_at FROM users WHERE TRUE AND role = \\$1 "+
+		"AND disabled = \\$2 ORDER BY created_at, id LIMIT \\$3 OFFSET \\$4").
+		WithArgs("employee", false, 10, 0).
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow("user1", "e@example.com", "employee", false, now))
+
+	users, total, err := repo.ListUsers(context.Background(), UserFilter{Role: "employee", Disabled: &disabled}, 10, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Len(t, users, 1)
+	assert.Equal(t, "e@example.com", users[0].Email)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_UpdateUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+	role := "moderator"
+	now := time.Now()
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery("UPDATE users SET role = COALESCE\\(\\$2, role\\)").
+			WithArgs("user1", &role, nil).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow("user1", "e@example.com", "moderator", false, now))
+
+		user, err := repo.UpdateUser(context.Background(), "user1", &role, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "moderator", user.Role)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery("UPDATE users").
+			WithArgs("missing", &role, nil).
+			WillReturnRows(sqlmock.NewRows(userColumns))
+
+		_, err := repo.UpdateUser(context.Background(), "missing", &role, nil)
+
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUserRepository_DeleteUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	mock.ExpectExec("DELETE FROM users WHERE id = \\$1").
+		WithArgs("user1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM users WHERE id = \\$1").
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.DeleteUser(context.Background(), "user1"))
+	assert.ErrorIs(t, repo.DeleteUser(context.Background(), "missing"), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/repository"
+)
+
+// apiKeyPrefix marks API keys so they can be told apart from other secrets, e.g.
+// by secret scanners. The prefix and the next few characters are kept in clear to
+// identify the key in listings.
+const (
+	apiKeyPrefix        = "pvz_"
+	apiKeyDisplayLength = len(apiKeyPrefix) + 8
+)
+
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, actorID, name, role string, pvzIDs []string,
+		expiresAt *time.Time) (domain.APIKey, string, error)
+	ListAPIKeys(ctx context.Context) ([]domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string) error
+	AuthenticateAPIKey(ctx context.Context, key string) (domain.APIKey, error)
+}
+
+type APIKeyServiceImpl struct {
+	apiKeyRepo repository.APIKeyRepository
+}
+
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository) *APIKeyServiceImpl {
+	return &APIKeyServiceImpl{apiKeyRepo: apiKeyRepo}
+}
+
+// CreateAPIKey issues a key for the employee or moderator role and returns it
+// together with the plain key, which is not stored and can't be shown again.
+func (p *APIKeyServiceImpl) CreateAPIKey(ctx context.Context, actorID, name, role string, pvzIDs []string,
+	expiresAt *time.Time) (domain.APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return domain.APIKey{}, "", errors.New("name is required")
+	}
+
+	if role != domain.RoleEmployee && role != domain.RoleModerator {
+		return domain.APIKey{}, "", errors.New("invalid role")
+	}
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return domain.APIKey{}, "", errors.New("expiresAt must be in the future")
+	}
+
+	seen := make(map[string]bool, len(pvzIDs))
+	uniquePVZIDs := make([]string, 0, len(pvzIDs))
+	for _, pvzID := range pvzIDs {
+		if _, err := uuid.Parse(pvzID); err != nil {
+			return domain.APIKey{}, "", errors.New("invalid pvzId")
+		}
+		if !seen[pvzID] {
+			seen[pvzID] = true
+			uniquePVZIDs = append(uniquePVZIDs, pvzID)
+		}
+	}
+
+	secret, err := newSecretToken()
+	if err != nil {
+		return domain.APIKey{}, "", internalError(ctx, "failed to create api key", err)
+	}
+	plainKey := apiKeyPrefix + secret
+
+	key := domain.APIKey{
+		Name:      name,
+		Prefix:    plainKey[:apiKeyDisplayLength],
+		Role:      role,
+		PVZIDs:    uniquePVZIDs,
+		ExpiresAt: expiresAt,
+	}
+	if _, err := uuid.Parse(actorID); err == nil {
+		key.CreatedBy = &actorID
+	}
+
+	created, err := p.apiKeyRepo.CreateAPIKey(ctx, key, hashSecretToken(plainKey))
+	if err != nil {
+		if errors.Is(err, domain.ErrPVZNotFound) {
+			return domain.APIKey{}, "", err
+		}
+		return domain.APIKey{}, "", internalError(ctx, "failed to create api key", err)
+	}
+
+	return created, plainKey, nil
+}
+
+func (p *APIKeyServiceImpl) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	keys, err := p.apiKeyRepo.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, internalError(ctx, "database error", err)
+	}
+	return keys, nil
+}
+
+func (p *APIKeyServiceImpl) RevokeAPIKey(ctx context.Context, id string) error {
+	if err := p.apiKeyRepo.RevokeAPIKey(ctx, id, time.Now()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrAPIKeyNotFound
+		}
+		return internalError(ctx, "database error", err)
+	}
+	return nil
+}
+
+// AuthenticateAPIKey returns the live key matching the plain key and records its use.
+func (p *APIKeyServiceImpl) AuthenticateAPIKey(ctx context.Context, key string) (domain.APIKey, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return domain.APIKey{}, domain.ErrInvalidAPIKey
+	}
+
+	apiKey, err := p.apiKeyRepo.UseAPIKey(ctx, hashSecretToken(key), time.Now())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.APIKey{}, domain.ErrInvalidAPIKey
+		}
+		return domain.APIKey{}, internalError(ctx, "database error", err)
+	}
+	return apiKey, nil
+}
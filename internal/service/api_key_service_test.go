@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/domain"
+)
+
+type MockAPIKeyRepo struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepo) CreateAPIKey(ctx context.Context, key domain.APIKey, keyHash string) (domain.APIKey, error) {
+	args := m.Called(key, keyHash)
+	return args.Get(0).(domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepo) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepo) RevokeAPIKey(ctx context.Context, id string, now time.Time) error {
+	args := m.Called(id, now)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepo) UseAPIKey(ctx context.Context, keyHash string, now time.Time) (domain.APIKey, error) {
+	args := m.Called(keyHash, now)
+	return args.Get(0).(domain.APIKey), args.Error(1)
+}
+
+func TestAPIKeyService_CreateAPIKey(t *testing.T) {
+	const pvzID = "11111111-1111-1111-1111-111111111111"
+	const actorID = "22222222-2222-2222-2222-222222222222"
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(MockAPIKeyRepo)
+		processor := NewAPIKeyService(mockRepo)
+
+		mockRepo.On("CreateAPIKey", mock.AnythingOfType("domain.APIKey"), mock.AnythingOfType("string")).
+			Return(domain.APIKey{ID: "key1", Role: "employee"}, nil)
+
+		created, plainKey, err := processor.CreateAPIKey(
+			context.Background(), actorID, " import script ", "employee", []string{pvzID, pvzID}, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "key1", created.ID)
+		assert.True(t, strings.HasPrefix(plainKey, "pvz_"))
+
+		stored := mockRepo.Calls[0].Arguments.Get(0).(domain.APIKey)
+		assert.Equal(t, "import script", stored.Name)
+		assert.Equal(t, []string{pvzID}, stored.PVZIDs)
+		assert.Equal(t, plainKey[:len(stored.Prefix)], stored.Prefix)
+		assert.Equal(t, actorID, *stored.CreatedBy)
+		assert.Equal(t, hashSecretToken(plainKey), mockRepo.Calls[0].Arguments.String(1))
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		processor := NewAPIKeyService(new(MockAPIKeyRepo))
+		past := time.Now().Add(-time.Hour)
+
+		_, _, err := processor.CreateAPIKey(context.Background(), actorID, "", "employee", nil, nil)
+		assert.EqualError(t, err, "name is required")
+
+		_, _, err = processor.CreateAPIKey(context.Background(), actorID, "key", "admin", nil, nil)
+		assert.EqualError(t, err, "invalid role")
+
+		_, _, err = processor.CreateAPIKey(context.Background(), actorID, "key", "employee", []string{"bad"}, nil)
+		assert.EqualError(t, err, "invalid pvzId")
+
+		_, _, err = processor.CreateAPIKey(context.Background(), actorID, "key", "employee", nil, &past)
+		assert.EqualError(t, err, "expiresAt must be in the future")
+	})
+
+	t.Run("unknown pvz", func(t *testing.T) {
+		mockRepo := new(MockAPIKeyRepo)
+		processor := NewAPIKeyService(mockRepo)
+
+		mockRepo.On("CreateAPIKey", mock.Anything, mock.Anything).Return(domain.APIKey{}, domain.ErrPVZNotFound)
+
+		_, _, err := processor.CreateAPIKey(context.Background(), actorID, "key", "employee", []string{pvzID}, nil)
+
+		assert.ErrorIs(t, err, domain.ErrPVZNotFound)
+	})
+}
+
+func TestAPIKeyService_RevokeAPIKey(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepo)
+	processor := NewAPIKeyService(mockRepo)
+
+	mockRepo.On("RevokeAPIKey", "key1", mock.AnythingOfType("time.Time")).Return(nil)
+	mockRepo.On("RevokeAPIKey", "missing", mock.AnythingOfType("time.Time")).Return(sql.ErrNoRows)
+
+	assert.NoError(t, processor.RevokeAPIKey(context.Background(), "key1"))
+	assert.ErrorIs(t, processor.RevokeAPIKey(context.Background(), "missing"), domain.ErrAPIKeyNotFound)
+}
+
+func TestAPIKeyService_AuthenticateAPIKey(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepo)
+	processor := NewAPIKeyService(mockRepo)
+
+	mockRepo.On("UseAPIKey", hashSecretToken("pvz_live"), mock.AnythingOfType("time.Time")).
+		Return(domain.APIKey{ID: "key1", Role: "moderator"}, nil)
+	mockRepo.On("UseAPIKey", hashSecretToken("pvz_revoked"), mock.AnythingOfType("time.Time")).
+		Return(domain.APIKey{}, sql.ErrNoRows)
+
+	key, err := processor.AuthenticateAPIKey(context.Background(), "pvz_live")
+	assert.NoError(t, err)
+	assert.Equal(t, "moderator", key.Role)
+
+	_, err = processor.AuthenticateAPIKey(context.Background(), "pvz_revoked")
+	assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+
+	_, err = processor.AuthenticateAPIKey(context.Background(), "no-prefix")
+	assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+	mockRepo.AssertNumberOfCalls(t, "UseAPIKey", 2)
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/repository"
+)
+
+// AssignmentService manages which PVZs an employee may operate on.
+type AssignmentService interface {
+	AssignPVZ(ctx context.Context, userID, pvzID string) error
+	UnassignPVZ(ctx context.Context, userID, pvzID string) error
+	ListAssignedPVZs(ctx context.Context, userID string) ([]string, error)
+	IsAssigned(ctx context.Context, userID, pvzID string) (bool, error)
+}
+
+type AssignmentServiceImpl struct {
+	assignmentRepo repository.AssignmentRepository
+	authRepo       repository.AuthRepository
+	pvzRepo        repository.PVZRepository
+}
+
+func NewAssignmentService(
+	assignmentRepo repository.AssignmentRepository,
+	authRepo repository.AuthRepository,
+	pvzRepo repository.PVZRepository,
+) *AssignmentServiceImpl {
+	return &AssignmentServiceImpl{
+		assignmentRepo: assignmentRepo,
+		authRepo:       authRepo,
+		pvzRepo:        pvzRepo,
+	}
+}
+
+func (p *AssignmentServiceImpl) AssignPVZ(ctx context.Context, userID, pvzID string) error {
+	_, role, err := p.authRepo.FindUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+		return internalError(ctx, "database error", err)
+	}
+	if role != "employee" {
+		return domain.ErrUserNotEmployee
+	}
+
+	if _, err := p.pvzRepo.GetPVZByID(ctx, pvzID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrPVZNotFound
+		}
+		return internalError(ctx, "database error", err)
+	}
+
+	if err := p.assignmentRepo.AssignPVZ(ctx, userID, pvzID); err != nil {
+		return internalError(ctx, "failed to assign pvz", err)
+	}
+	return nil
+}
+
+func (p *AssignmentServiceImpl) UnassignPVZ(ctx context.Context, userID, pvzID string) error {
+	if err := p.assignmentRepo.UnassignPVZ(ctx, userID, pvzID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrAssignmentNotFound
+		}
+		return internalError(ctx, "failed to unassign pvz", err)
+	}
+	return nil
+}
+
+func (p *AssignmentServiceImpl) ListAssignedPVZs(ctx context.Context, userID string) ([]string, error) {
+	if _, _, err := p.authRepo.FindUserByID(ctx, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, internalError(ctx, "database error", err)
+	}
+
+	pvzIDs, err := p.assignmentRepo.ListUserPVZs(ctx, userID)
+	if err != nil {
+		return nil, internalError(ctx, "database error", err)
+	}
+	return pvzIDs, nil
+}
+
+func (p *AssignmentServiceImpl) IsAssigned(ctx context.Context, userID, pvzID string) (bool, error) {
+	assigned, err := p.assignmentRepo.IsAssigned(ctx, userID, pvzID)
+	if err != nil {
+		return false, internalError(ctx, "database error", err)
+	}
+	return assigned, nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/domain"
+)
+
+type MockAssignmentRepo struct {
+	mock.Mock
+}
+
+func (m *MockAssignmentRepo) AssignPVZ(ctx context.Context, userID, pvzID string) error {
+	args := m.Called(userID, pvzID)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentRepo) UnassignPVZ(ctx context.Context, userID, pvzID string) error {
+	args := m.Called(userID, pvzID)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentRepo) ListUserPVZs(ctx context.Context, userID string) ([]string, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) IsAssigned(ctx context.Context, userID, pvzID string) (bool, error) {
+	args := m.Called(userID, pvzID)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestAssignmentService_AssignPVZ(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		assignmentRepo, authRepo, pvzRepo := new(MockAssignmentRepo), new(MockAuthRepository), new(MockPVZRepo)
+		processor := NewAssignmentService(assignmentRepo, authRepo, pvzRepo)
+
+		authRepo.On("FindUserByID", "user1").Return("e@example.com", "employee", nil)
+		pvzRepo.On("GetPVZByID", "pvz1").Return(domain.PVZ{ID: "pvz1"}, nil)
+		assignmentRepo.On("AssignPVZ", "user1", "pvz1").Return(nil)
+
+		err := processor.AssignPVZ(context.Background(), "user1", "pvz1")
+
+		assert.NoError(t, err)
+		assignmentRepo.AssertExpectations(t)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		assignmentRepo, authRepo, pvzRepo := new(MockAssignmentRepo), new(MockAuthRepository), new(MockPVZRepo)
+		processor := NewAssignmentService(assignmentRepo, authRepo, pvzRepo)
+
+		authRepo.On("FindUserByID", "user1").Return("", "", sql.ErrNoRows)
+
+		err := processor.AssignPVZ(context.Background(), "user1", "pvz1")
+
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+		assignmentRepo.AssertNotCalled(t, "AssignPVZ", mock.Anything, mock.Anything)
+	})
+
+	t.Run("moderator cannot be assigned", func(t *testing.T) {
+		assignmentRepo, authRepo, pvzRepo := new(MockAssignmentRepo), new(MockAuthRepository), new(MockPVZRepo)
+		processor := NewAssignmentService(assignmentRepo, authRepo, pvzRepo)
+
+		authRepo.On("FindUserByID", "user1").Return("m@example.com", "moderator", nil)
+
+		err := processor.AssignPVZ(context.Background(), "user1", "pvz1")
+
+		assert.ErrorIs(t, err, domain.ErrUserNotEmployee)
+	})
+
+	t.Run("pvz not found", func(t *testing.T) {
+		assignmentRepo, authRepo, pvzRepo := new(MockAssignmentRepo), new(MockAuthRepository), new(MockPVZRepo)
+		processor := NewAssignmentService(assignmentRepo, authRepo, pvzRepo)
+
+		authRepo.On("FindUserByID", "user1").Return("e@example.com", "employee", nil)
+		pvzRepo.On("GetPVZByID", "pvz1").Return(domain.PVZ{}, sql.ErrNoRows)
+
+		err := processor.AssignPVZ(context.Background(), "user1", "pvz1")
+
+		assert.ErrorIs(t, err, domain.ErrPVZNotFound)
+	})
+}
+
+func TestAssignmentService_UnassignPVZ(t *testing.T) {
+	assignmentRepo := new(MockAssignmentRepo)
+	processor := NewAssignmentService(assignmentRepo, new(MockAuthRepository), new(MockPVZRepo))
+
+	assignmentRepo.On("UnassignPVZ", "user1", "pvz1").Return(nil)
+	assignmentRepo.On("UnassignPVZ", "user1", "pvz2").Return(sql.ErrNoRows)
+
+	assert.NoError(t, processor.UnassignPVZ(context.Background(), "user1", "pvz1"))
+	assert.ErrorIs(t, processor.UnassignPVZ(context.Background(), "user1", "pvz2"), domain.ErrAssignmentNotFound)
+}
+
+func TestAssignmentService_ListAssignedPVZs(t *testing.T) {
+	assignmentRepo, authRepo := new(MockAssignmentRepo), new(MockAuthRepository)
+	processor := NewAssignmentService(assignmentRepo, authRepo, new(MockPVZRepo))
+
+	authRepo.On("FindUserByID", "user1").Return("e@example.com", "employee", nil)
+	authRepo.On("FindUserByID", "missing").Return("", "", sql.ErrNoRows)
+	assignmentRepo.On("ListUserPVZs", "user1").Return([]string{"pvz1"}, nil)
+
+	pvzIDs, err := processor.ListAssignedPVZs(context.Background(), "user1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pvz1"}, pvzIDs)
+
+	_, err = processor.ListAssignedPVZs(context.Background(), "missing")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+}
+
+func TestAssignmentService_IsAssigned(t *testing.T) {
+	assignmentRepo := new(MockAssignmentRepo)
+	processor := NewAssignmentService(assignmentRepo, new(MockAuthRepository), new(MockPVZRepo))
+
+	assignmentRepo.On("IsAssigned", "user1", "pvz1").Return(true, nil)
+	assignmentRepo.On("IsAssigned", "user1", "pvz2").Return(false, errors.New("connection refused"))
+
+	assigned, err := processor.IsAssigned(context.Background(), "user1", "pvz1")
+	assert.NoError(t, err)
+	assert.True(t, assigned)
+
+	_, err = processor.IsAssigned(context.Background(), "user1", "pvz2")
+	assert.EqualError(t, err, "database error")
+}
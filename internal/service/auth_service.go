@@ -1,31 +1,82 @@
 package service
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"golang.org/x/crypto/bcrypt"
+	"time"
 
+	"pvz-service/internal/domain"
 	"pvz-service/internal/repository"
 )
 
 type AuthService interface {
-	Register(email, password, role string) (string, error)
-	Login(email, password string) (string, string, error)
-	DummyLogin(role string) (string, error)
+	Register(ctx context.Context, email, password, role string) (string, error)
+	CreateUser(ctx context.Context, email, password, role string) (string, error)
+	Login(ctx context.Context, email, password, clientIP string) (string, string, error)
+	DummyLogin(ctx context.Context, role string) (string, error)
 	HashPassword(password string) (string, error)
 	ComparePassword(hashedPassword, password string) error
+	StartSession(ctx context.Context, userID, accessJTI string) (string, error)
+	RefreshSession(ctx context.Context, refreshToken, accessJTI string) (string, string, string, error)
+	Logout(ctx context.Context, accessJTI string, accessExpiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	IsUserDisabled(ctx context.Context, userID string) (bool, error)
+}
+
+// LoginGuard limits failed logins. Check and Failure return a *domain.LockoutError
+// while logins are blocked.
+type LoginGuard interface {
+	Check(ctx context.Context, email, ip string) error
+	Failure(ctx context.Context, email, ip string) error
+	Success(ctx context.Context, email string) error
+}
+
+// PasswordPolicy validates new passwords and returns an error wrapping
+// domain.ErrWeakPassword when one is rejected.
+type PasswordPolicy interface {
+	Validate(password string) error
 }
 
 type AuthServiceImpl struct {
-	authRepo repository.AuthRepository
+	authRepo        repository.AuthRepository
+	sessionRepo     repository.SessionRepository
+	loginGuard      LoginGuard
+	passwordPolicy  PasswordPolicy
+	refreshTokenTTL time.Duration
+	allowSignup     bool
 }
 
-func NewAuthService(authRepo repository.AuthRepository) AuthService {
-	return &AuthServiceImpl{authRepo: authRepo}
+// NewAuthService builds the auth service. allowSignup controls self-registration,
+// which is limited to the employee role. A nil loginGuard disables attempt limiting,
+// a nil passwordPolicy accepts any password.
+func NewAuthService(
+	authRepo repository.AuthRepository,
+	sessionRepo repository.SessionRepository,
+	loginGuard LoginGuard,
+	passwordPolicy PasswordPolicy,
+	refreshTokenTTL time.Duration,
+	allowSignup bool,
+) AuthService {
+	return &AuthServiceImpl{
+		authRepo:        authRepo,
+		sessionRepo:     sessionRepo,
+		loginGuard:      loginGuard,
+		passwordPolicy:  passwordPolicy,
+		refreshTokenTTL: refreshTokenTTL,
+		allowSignup:     allowSignup,
+	}
 }
 
+const bcryptCost = 14
+
 func (p *AuthServiceImpl) HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcryptCost)
 	return string(bytes), err
 }
 
@@ -33,46 +84,223 @@ func (p *AuthServiceImpl) ComparePassword(hashedPassword, password string) error
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 }
 
-func (p *AuthServiceImpl) Register(email, password, role string) (string, error) {
-	if role != "employee" && role != "moderator" {
+func (p *AuthServiceImpl) Register(ctx context.Context, email, password, role string) (string, error) {
+	if !p.allowSignup {
+		return "", errors.New("registration is disabled")
+	}
+
+	if role != domain.RoleEmployee {
 		return "", errors.New("invalid role")
 	}
 
+	return p.CreateUser(ctx, email, password, role)
+}
+
+// CreateUser creates an account with any role, bypassing the self-registration
+// restrictions. It is meant for operators, not for the public API.
+func (p *AuthServiceImpl) CreateUser(ctx context.Context, email, password, role string) (string, error) {
+	if !domain.IsValidRole(role) {
+		return "", errors.New("invalid role")
+	}
+
+	email, err := domain.NormalizeEmail(email)
+	if err != nil {
+		return "", err
+	}
+
+	if p.passwordPolicy != nil {
+		if err := p.passwordPolicy.Validate(password); err != nil {
+			return "", err
+		}
+	}
+
 	hashedPassword, err := p.HashPassword(password)
 	if err != nil {
-		return "", errors.New("failed to process password")
+		return "", internalError(ctx, "failed to process password", err)
 	}
 
-	return p.authRepo.CreateUser(email, hashedPassword, role)
+	userID, err := p.authRepo.CreateUser(ctx, email, hashedPassword, role)
+	if err != nil {
+		if errors.Is(err, domain.ErrEmailAlreadyExists) {
+			return "", err
+		}
+		return "", internalError(ctx, "failed to create user", err)
+	}
+	return userID, nil
 }
 
-func (p *AuthServiceImpl) Login(email, password string) (string, string, error) {
-	userID, hashedPassword, role, err := p.authRepo.FindUserByEmail(email)
+// Login checks the credentials. Blocked emails and client IPs are rejected before
+// the password is hashed.
+func (p *AuthServiceImpl) Login(ctx context.Context, email, password, clientIP string) (string, string, error) {
+	if normalized, err := domain.NormalizeEmail(email); err == nil {
+		email = normalized
+	}
+
+	if p.loginGuard != nil {
+		if err := p.loginGuard.Check(ctx, email, clientIP); err != nil {
+			return "", "", loginGuardError(ctx, err)
+		}
+	}
+
+	userID, hashedPassword, role, err := p.authRepo.FindUserByEmail(ctx, email)
+	if err == nil {
+		err = p.ComparePassword(hashedPassword, password)
+	}
 	if err != nil {
+		if p.loginGuard != nil {
+			if err := p.loginGuard.Failure(ctx, email, clientIP); err != nil {
+				return "", "", loginGuardError(ctx, err)
+			}
+		}
 		return "", "", errors.New("invalid email or password")
 	}
 
-	if err := p.ComparePassword(hashedPassword, password); err != nil {
-		return "", "", errors.New("invalid email or password")
+	if p.loginGuard != nil {
+		if err := p.loginGuard.Success(ctx, email); err != nil {
+			return "", "", internalError(ctx, "database error", err)
+		}
+	}
+
+	disabled, err := p.authRepo.IsUserDisabled(ctx, userID)
+	if err != nil {
+		return "", "", internalError(ctx, "database error", err)
+	}
+	if disabled {
+		return "", "", domain.ErrUserDisabled
 	}
 
 	return userID, role, nil
 }
 
-func (p *AuthServiceImpl) DummyLogin(role string) (string, error) {
-	if role != "employee" && role != "moderator" {
+// DummyLogin signs in as the dummy account of an employee or moderator, creating
+// it on first use. It never returns a real user's account, and admin is not offered.
+func (p *AuthServiceImpl) DummyLogin(ctx context.Context, role string) (string, error) {
+	if role != domain.RoleEmployee && role != domain.RoleModerator {
 		return "", errors.New("invalid role")
 	}
 
-	userID, err := p.authRepo.FindUserByRole(role)
+	email := "dummy-" + role + "@example.com"
+	userID, _, _, err := p.authRepo.FindUserByEmail(ctx, email)
 	if errors.Is(err, sql.ErrNoRows) {
-		hashedPassword, err := p.HashPassword("password")
+		// Dummy users only ever sign in through DummyLogin, so their password is
+		// random and never handed out.
+		password, err := newSecretToken()
+		if err != nil {
+			return "", internalError(ctx, "failed to create dummy user", err)
+		}
+		hashedPassword, err := p.HashPassword(password)
 		if err != nil {
-			return "", errors.New("failed to create dummy user")
+			return "", internalError(ctx, "failed to create dummy user", err)
+		}
+
+		userID, err = p.authRepo.CreateUser(ctx, email, hashedPassword, role)
+		if err != nil {
+			return "", internalError(ctx, "failed to create dummy user", err)
+		}
+		return userID, nil
+	}
+	if err != nil {
+		return "", internalError(ctx, "database error", err)
+	}
+
+	return userID, nil
+}
+
+// StartSession opens a session bound to the given access token id and returns
+// its refresh token. Only a hash of the refresh token is stored.
+func (p *AuthServiceImpl) StartSession(ctx context.Context, userID, accessJTI string) (string, error) {
+	refreshToken, err := newSecretToken()
+	if err != nil {
+		return "", internalError(ctx, "failed to create session", err)
+	}
+
+	expiresAt := time.Now().Add(p.refreshTokenTTL)
+	if err := p.sessionRepo.CreateSession(ctx, userID, hashSecretToken(refreshToken), accessJTI, expiresAt); err != nil {
+		return "", internalError(ctx, "failed to create session", err)
+	}
+
+	return refreshToken, nil
+}
+
+// RefreshSession exchanges a refresh token for a new one bound to accessJTI and
+// returns the session's user id, role and the new refresh token.
+func (p *AuthServiceImpl) RefreshSession(
+	ctx context.Context, refreshToken, accessJTI string) (string, string, string, error) {
+	if refreshToken == "" {
+		return "", "", "", errors.New("invalid refresh token")
+	}
+
+	newToken, err := newSecretToken()
+	if err != nil {
+		return "", "", "", internalError(ctx, "failed to refresh session", err)
+	}
+
+	now := time.Now()
+	userID, role, err := p.sessionRepo.RotateSession(ctx,
+		hashSecretToken(refreshToken), hashSecretToken(newToken), accessJTI, now.Add(p.refreshTokenTTL), now)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", "", "", errors.New("invalid refresh token")
 		}
+		return "", "", "", internalError(ctx, "database error", err)
+	}
 
-		return p.authRepo.CreateUser("dummy@example.com", hashedPassword, role)
+	return userID, role, newToken, nil
+}
+
+// Logout revokes the session the access token belongs to and the access token itself.
+func (p *AuthServiceImpl) Logout(ctx context.Context, accessJTI string, accessExpiresAt time.Time) error {
+	if err := p.sessionRepo.RevokeSessionByAccessJTI(ctx, accessJTI, time.Now()); err != nil {
+		return internalError(ctx, "database error", err)
+	}
+
+	if err := p.sessionRepo.RevokeToken(ctx, accessJTI, accessExpiresAt); err != nil {
+		return internalError(ctx, "database error", err)
+	}
+
+	return nil
+}
+
+func (p *AuthServiceImpl) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	revoked, err := p.sessionRepo.IsTokenRevoked(ctx, jti)
+	if err != nil {
+		return false, internalError(ctx, "database error", err)
+	}
+	return revoked, nil
+}
+
+// IsUserDisabled reports whether the user's tokens must be rejected. Deleted users
+// count as disabled.
+func (p *AuthServiceImpl) IsUserDisabled(ctx context.Context, userID string) (bool, error) {
+	disabled, err := p.authRepo.IsUserDisabled(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return true, nil
+		}
+		return false, internalError(ctx, "database error", err)
+	}
+	return disabled, nil
+}
+
+func loginGuardError(ctx context.Context, err error) error {
+	var lockout *domain.LockoutError
+	if errors.As(err, &lockout) {
+		return err
 	}
+	return internalError(ctx, "database error", err)
+}
+
+// newSecretToken returns a random token for refresh and password reset tokens.
+// Only its hashSecretToken hash is stored.
+func newSecretToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
 
-	return userID, err
+func hashSecretToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
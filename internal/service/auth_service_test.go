@@ -1,40 +1,95 @@
 package service
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/domain"
 )
 
 type MockAuthRepository struct {
 	mock.Mock
 }
 
-func (m *MockAuthRepository) CreateUser(email, hashedPassword, role string) (string, error) {
+func (m *MockAuthRepository) CreateUser(ctx context.Context, email, hashedPassword, role string) (string, error) {
 	args := m.Called(email, hashedPassword, role)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockAuthRepository) FindUserByEmail(email string) (string, string, string, error) {
+func (m *MockAuthRepository) FindUserByEmail(ctx context.Context, email string) (string, string, string, error) {
 	args := m.Called(email)
 	return args.String(0), args.String(1), args.String(2), args.Error(3)
 }
 
-func (m *MockAuthRepository) FindUserByRole(role string) (string, error) {
-	args := m.Called(role)
+func (m *MockAuthRepository) FindUserByID(ctx context.Context, userID string) (string, string, error) {
+	args := m.Called(userID)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockAuthRepository) IsUserDisabled(ctx context.Context, userID string) (bool, error) {
+	args := m.Called(userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAuthRepository) GetPasswordHash(ctx context.Context, userID string) (string, error) {
+	args := m.Called(userID)
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockAuthRepository) UpdatePassword(ctx context.Context, userID, hashedPassword string) error {
+	args := m.Called(userID, hashedPassword)
+	return args.Error(0)
+}
+
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) CreateSession(
+	ctx context.Context, userID, refreshTokenHash, accessJTI string, expiresAt time.Time) error {
+	args := m.Called(userID, refreshTokenHash, accessJTI, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) RotateSession(ctx context.Context, refreshTokenHash, newRefreshTokenHash,
+	accessJTI string, expiresAt, now time.Time) (string, string, error) {
+	args := m.Called(refreshTokenHash, newRefreshTokenHash, accessJTI, expiresAt, now)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockSessionRepository) RevokeSessionByAccessJTI(ctx context.Context, accessJTI string, now time.Time) error {
+	args := m.Called(accessJTI, now)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) RevokeUserSessions(ctx context.Context, userID string, now time.Time) error {
+	args := m.Called(userID, now)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	args := m.Called(jti, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	args := m.Called(jti)
+	return args.Bool(0), args.Error(1)
+}
+
 func TestAuthProcessor_Register_Success(t *testing.T) {
 	mockRepo := new(MockAuthRepository)
-	processor := NewAuthService(mockRepo)
+	processor := NewAuthService(mockRepo, nil, nil, nil, time.Hour, true)
 
 	mockRepo.On("CreateUser", "test@example.com", mock.Anything, "employee").Return("user123", nil)
 
-	userID, err := processor.Register("test@example.com", "password", "employee")
+	userID, err := processor.Register(context.Background(), " Test@Example.com ", "password", "employee")
 	assert.NoError(t, err)
 	assert.Equal(t, "user123", userID)
 	mockRepo.AssertExpectations(t)
@@ -42,33 +97,111 @@ func TestAuthProcessor_Register_Success(t *testing.T) {
 
 func TestAuthProcessor_Register_InvalidRole(t *testing.T) {
 	mockRepo := new(MockAuthRepository)
-	processor := NewAuthService(mockRepo)
+	processor := NewAuthService(mockRepo, nil, nil, nil, time.Hour, true)
 
-	_, err := processor.Register("test@example.com", "password", "invalid")
+	_, err := processor.Register(context.Background(), "test@example.com", "password", "invalid")
 	assert.Error(t, err)
 	assert.Equal(t, "invalid role", err.Error())
 }
 
+func TestAuthProcessor_Register_ModeratorRejected(t *testing.T) {
+	mockRepo := new(MockAuthRepository)
+	processor := NewAuthService(mockRepo, nil, nil, nil, time.Hour, true)
+
+	_, err := processor.Register(context.Background(), "test@example.com", "password", "moderator")
+	assert.EqualError(t, err, "invalid role")
+	mockRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthProcessor_Register_Disabled(t *testing.T) {
+	mockRepo := new(MockAuthRepository)
+	processor := NewAuthService(mockRepo, nil, nil, nil, time.Hour, false)
+
+	_, err := processor.Register(context.Background(), "test@example.com", "password", "employee")
+	assert.EqualError(t, err, "registration is disabled")
+	mockRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthProcessor_CreateUser_AnyRoleWhenSignupDisabled(t *testing.T) {
+	mockRepo := new(MockAuthRepository)
+	processor := NewAuthService(mockRepo, nil, nil, lengthPolicy(8), time.Hour, false)
+
+	mockRepo.On("CreateUser", "admin@example.com", mock.Anything, "admin").Return("user123", nil)
+
+	userID, err := processor.CreateUser(context.Background(), "Admin@Example.com", "password", "admin")
+	assert.NoError(t, err)
+	assert.Equal(t, "user123", userID)
+
+	_, err = processor.CreateUser(context.Background(), "admin@example.com", "short", "admin")
+	assert.ErrorIs(t, err, domain.ErrWeakPassword)
+
+	_, err = processor.CreateUser(context.Background(), "admin@example.com", "password", "root")
+	assert.EqualError(t, err, "invalid role")
+	mockRepo.AssertExpectations(t)
+}
+
+type lengthPolicy int
+
+func (l lengthPolicy) Validate(password string) error {
+	if len(password) < int(l) {
+		return domain.ErrWeakPassword
+	}
+	return nil
+}
+
+func TestAuthProcessor_Register_InvalidEmail(t *testing.T) {
+	mockRepo := new(MockAuthRepository)
+	processor := NewAuthService(mockRepo, nil, nil, nil, time.Hour, true)
+
+	for _, email := range []string{"", "not-an-email", "Name <test@example.com>"} {
+		_, err := processor.Register(context.Background(), email, "password", "employee")
+		assert.ErrorIs(t, err, domain.ErrInvalidEmail, email)
+	}
+	mockRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthProcessor_Register_WeakPassword(t *testing.T) {
+	mockRepo := new(MockAuthRepository)
+	processor := NewAuthService(mockRepo, nil, nil, lengthPolicy(12), time.Hour, true)
+
+	_, err := processor.Register(context.Background(), "test@example.com", "password", "employee")
+	assert.ErrorIs(t, err, domain.ErrWeakPassword)
+	mockRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestAuthProcessor_Register_EmailExists(t *testing.T) {
 	mockRepo := new(MockAuthRepository)
-	processor := NewAuthService(mockRepo)
+	processor := NewAuthService(mockRepo, nil, nil, nil, time.Hour, true)
 
-	mockRepo.On("CreateUser", "exists@example.com", mock.Anything, "employee").Return("", errors.New("email already exists"))
+	mockRepo.On("CreateUser", "exists@example.com", mock.Anything, "employee").Return("", domain.ErrEmailAlreadyExists)
 
-	_, err := processor.Register("exists@example.com", "password", "employee")
+	_, err := processor.Register(context.Background(), "exists@example.com", "password", "employee")
 	assert.Error(t, err)
 	assert.Equal(t, "email already exists", err.Error())
 	mockRepo.AssertExpectations(t)
 }
 
+func TestAuthProcessor_CreateUser_RepositoryError(t *testing.T) {
+	mockRepo := new(MockAuthRepository)
+	processor := NewAuthService(mockRepo, nil, nil, nil, time.Hour, true)
+
+	mockRepo.On("CreateUser", "new@example.com", mock.Anything, "admin").
+		Return("", errors.New("pq: connection reset by peer"))
+
+	_, err := processor.CreateUser(context.Background(), "new@example.com", "password", "admin")
+	assert.EqualError(t, err, "failed to create user")
+	assert.ErrorIs(t, err, domain.ErrInternal)
+}
+
 func TestAuthProcessor_Login_Success(t *testing.T) {
 	mockRepo := new(MockAuthRepository)
-	processor := NewAuthService(mockRepo)
+	processor := NewAuthService(mockRepo, nil, nil, nil, time.Hour, true)
 
 	hashedPassword, _ := processor.HashPassword("password")
 	mockRepo.On("FindUserByEmail", "test@example.com").Return("user123", hashedPassword, "employee", nil)
+	mockRepo.On("IsUserDisabled", "user123").Return(false, nil)
 
-	userID, role, err := processor.Login("test@example.com", "password")
+	userID, role, err := processor.Login(context.Background(), "test@example.com", "password", "10.0.0.1")
 	assert.NoError(t, err)
 	assert.Equal(t, "user123", userID)
 	assert.Equal(t, "employee", role)
@@ -77,36 +210,117 @@ func TestAuthProcessor_Login_Success(t *testing.T) {
 
 func TestAuthProcessor_Login_InvalidPassword(t *testing.T) {
 	mockRepo := new(MockAuthRepository)
-	processor := NewAuthService(mockRepo)
+	processor := NewAuthService(mockRepo, nil, nil, nil, time.Hour, true)
 
 	hashedPassword, _ := processor.HashPassword("password")
 	mockRepo.On("FindUserByEmail", "test@example.com").Return("user123", hashedPassword, "employee", nil)
 
-	_, _, err := processor.Login("test@example.com", "wrong")
+	_, _, err := processor.Login(context.Background(), "test@example.com", "wrong", "10.0.0.1")
 	assert.Error(t, err)
 	assert.Equal(t, "invalid email or password", err.Error())
 	mockRepo.AssertExpectations(t)
 }
 
+func TestAuthProcessor_Login_DisabledUser(t *testing.T) {
+	mockRepo := new(MockAuthRepository)
+	processor := NewAuthService(mockRepo, nil, nil, nil, time.Hour, true)
+
+	hashedPassword, _ := processor.HashPassword("password")
+	mockRepo.On("FindUserByEmail", "test@example.com").Return("user123", hashedPassword, "employee", nil)
+	mockRepo.On("IsUserDisabled", "user123").Return(true, nil)
+
+	_, _, err := processor.Login(context.Background(), "test@example.com", "password", "10.0.0.1")
+	assert.ErrorIs(t, err, domain.ErrUserDisabled)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestAuthProcessor_Login_UserNotFound(t *testing.T) {
 	mockRepo := new(MockAuthRepository)
-	processor := NewAuthService(mockRepo)
+	processor := NewAuthService(mockRepo, nil, nil, nil, time.Hour, true)
 
 	mockRepo.On("FindUserByEmail", "nonexistent@example.com").Return("", "", "", sql.ErrNoRows)
 
-	_, _, err := processor.Login("nonexistent@example.com", "password")
+	_, _, err := processor.Login(context.Background(), "nonexistent@example.com", "password", "10.0.0.1")
 	assert.Error(t, err)
 	assert.Equal(t, "invalid email or password", err.Error())
 	mockRepo.AssertExpectations(t)
 }
 
+type MockLoginGuard struct {
+	mock.Mock
+}
+
+func (m *MockLoginGuard) Check(ctx context.Context, email, ip string) error {
+	return m.Called(email, ip).Error(0)
+}
+
+func (m *MockLoginGuard) Failure(ctx context.Context, email, ip string) error {
+	return m.Called(email, ip).Error(0)
+}
+
+func (m *MockLoginGuard) Success(ctx context.Context, email string) error {
+	return m.Called(email).Error(0)
+}
+
+func TestAuthProcessor_Login_Locked(t *testing.T) {
+	mockRepo := new(MockAuthRepository)
+	mockGuard := new(MockLoginGuard)
+	processor := NewAuthService(mockRepo, nil, mockGuard, nil, time.Hour, true)
+
+	lockout := &domain.LockoutError{Err: domain.ErrAccountLocked, RetryAfter: time.Minute}
+	mockGuard.On("Check", "test@example.com", "10.0.0.1").Return(lockout)
+
+	_, _, err := processor.Login(context.Background(), "test@example.com", "password", "10.0.0.1")
+
+	assert.ErrorIs(t, err, domain.ErrAccountLocked)
+	mockRepo.AssertNotCalled(t, "FindUserByEmail", mock.Anything)
+}
+
+func TestAuthProcessor_Login_FailureRecorded(t *testing.T) {
+	mockRepo := new(MockAuthRepository)
+	mockGuard := new(MockLoginGuard)
+	processor := NewAuthService(mockRepo, nil, mockGuard, nil, time.Hour, true)
+
+	mockGuard.On("Check", "test@example.com", "10.0.0.1").Return(nil)
+	mockRepo.On("FindUserByEmail", "test@example.com").Return("", "", "", sql.ErrNoRows).Once()
+	mockGuard.On("Failure", "test@example.com", "10.0.0.1").Return(nil).Once()
+
+	_, _, err := processor.Login(context.Background(), "test@example.com", "password", "10.0.0.1")
+	assert.EqualError(t, err, "invalid email or password")
+
+	lockout := &domain.LockoutError{Err: domain.ErrAccountLocked, RetryAfter: time.Minute}
+	mockRepo.On("FindUserByEmail", "test@example.com").Return("", "", "", sql.ErrNoRows).Once()
+	mockGuard.On("Failure", "test@example.com", "10.0.0.1").Return(lockout).Once()
+
+	_, _, err = processor.Login(context.Background(), "test@example.com", "password", "10.0.0.1")
+	assert.ErrorIs(t, err, domain.ErrAccountLocked)
+	mockGuard.AssertExpectations(t)
+}
+
+func TestAuthProcessor_Login_SuccessResetsGuard(t *testing.T) {
+	mockRepo := new(MockAuthRepository)
+	mockGuard := new(MockLoginGuard)
+	processor := NewAuthService(mockRepo, nil, mockGuard, nil, time.Hour, true)
+
+	hashedPassword, _ := processor.HashPassword("password")
+	mockGuard.On("Check", "test@example.com", "10.0.0.1").Return(nil)
+	mockRepo.On("FindUserByEmail", "test@example.com").Return("user123", hashedPassword, "employee", nil)
+	mockGuard.On("Success", "test@example.com").Return(nil)
+	mockRepo.On("IsUserDisabled", "user123").Return(false, nil)
+
+	_, _, err := processor.Login(context.Background(), "test@example.com", "password", "10.0.0.1")
+
+	assert.NoError(t, err)
+	mockGuard.AssertExpectations(t)
+}
+
 func TestAuthProcessor_DummyLogin_Success(t *testing.T) {
 	mockRepo := new(MockAuthRepository)
-	processor := NewAuthService(mockRepo)
+	processor := NewAuthService(mockRepo, nil, nil, nil, time.Hour, true)
 
-	mockRepo.On("FindUserByRole", "employee").Return("user123", nil)
+	mockRepo.On("FindUserByEmail", "dummy-employee@example.com").Return("user123", "hash", "employee", nil)
 
-	userID, err := processor.DummyLogin("employee")
+	userID, err := processor.DummyLogin(context.Background(), "employee")
 	assert.NoError(t, err)
 	assert.Equal(t, "user123", userID)
 	mockRepo.AssertExpectations(t)
@@ -114,28 +328,43 @@ func TestAuthProcessor_DummyLogin_Success(t *testing.T) {
 
 func TestAuthProcessor_DummyLogin_CreateNewUser(t *testing.T) {
 	mockRepo := new(MockAuthRepository)
-	processor := NewAuthService(mockRepo)
+	processor := NewAuthService(mockRepo, nil, nil, nil, time.Hour, true)
 
-	mockRepo.On("FindUserByRole", "employee").Return("", sql.ErrNoRows)
-	mockRepo.On("CreateUser", "dummy@example.com", mock.Anything, "employee").Return("newuser123", nil)
+	mockRepo.On("FindUserByEmail", "dummy-employee@example.com").Return("", "", "", sql.ErrNoRows)
+	mockRepo.On("CreateUser", "dummy-employee@example.com", mock.Anything, "employee").Return("newuser123", nil)
 
-	userID, err := processor.DummyLogin("employee")
+	userID, err := processor.DummyLogin(context.Background(), "employee")
 	assert.NoError(t, err)
 	assert.Equal(t, "newuser123", userID)
 	mockRepo.AssertExpectations(t)
 }
 
-func TestAuthProcessor_DummyLogin_InvalidRole(t *testing.T) {
+func TestAuthProcessor_DummyLogin_RepositoryError(t *testing.T) {
 	mockRepo := new(MockAuthRepository)
-	processor := NewAuthService(mockRepo)
+	processor := NewAuthService(mockRepo, nil, nil, nil, time.Hour, true)
 
-	_, err := processor.DummyLogin("invalid")
-	assert.Error(t, err)
-	assert.Equal(t, "invalid role", err.Error())
+	mockRepo.On("FindUserByEmail", "dummy-moderator@example.com").
+		Return("", "", "", errors.New("pq: connection reset by peer"))
+
+	_, err := processor.DummyLogin(context.Background(), "moderator")
+	assert.EqualError(t, err, "database error")
+	assert.ErrorIs(t, err, domain.ErrInternal)
+}
+
+func TestAuthProcessor_DummyLogin_InvalidRole(t *testing.T) {
+	mockRepo := new(MockAuthRepository)
+	processor := NewAuthService(mockRepo, nil, nil, nil, time.Hour, true)
+
+	for _, role := range []string{"invalid", "admin"} {
+		_, err := processor.DummyLogin(context.Background(), role)
+		assert.EqualError(t, err, "invalid role")
+	}
+	mockRepo.AssertNotCalled(t, "FindUserByEmail", mock.Anything)
+	mockRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestHashAndComparePassword(t *testing.T) {
-	processor := NewAuthService(nil)
+	processor := NewAuthService(nil, nil, nil, nil, time.Hour, true)
 	password := "testpassword123"
 
 	hashed, err := processor.HashPassword(password)
@@ -148,3 +377,110 @@ func TestHashAndComparePassword(t *testing.T) {
 	err = processor.ComparePassword(hashed, "wrongpassword")
 	assert.Error(t, err)
 }
+
+func TestAuthProcessor_StartSession(t *testing.T) {
+	mockSessions := new(MockSessionRepository)
+	processor := NewAuthService(new(MockAuthRepository), mockSessions, nil, nil, time.Hour, true)
+
+	var storedHash string
+	mockSessions.On("CreateSession", "user123", mock.AnythingOfType("string"), "jti1", mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) { storedHash = args.String(1) }).
+		Return(nil)
+
+	refreshToken, err := processor.StartSession(context.Background(), "user123", "jti1")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, refreshToken)
+	assert.Equal(t, hashSecretToken(refreshToken), storedHash)
+	assert.NotEqual(t, refreshToken, storedHash)
+	expiresAt := mockSessions.Calls[0].Arguments.Get(3).(time.Time)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+}
+
+func TestAuthProcessor_RefreshSession(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockSessions := new(MockSessionRepository)
+		processor := NewAuthService(new(MockAuthRepository), mockSessions, nil, nil, time.Hour, true)
+
+		mockSessions.On("RotateSession", hashSecretToken("old"), mock.AnythingOfType("string"), "jti2",
+			mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
+			Return("user123", "employee", nil)
+
+		userID, role, newToken, err := processor.RefreshSession(context.Background(), "old", "jti2")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user123", userID)
+		assert.Equal(t, "employee", role)
+		assert.NotEqual(t, "old", newToken)
+		assert.Equal(t, hashSecretToken(newToken), mockSessions.Calls[0].Arguments.String(1))
+	})
+
+	t.Run("unknown or used token", func(t *testing.T) {
+		mockSessions := new(MockSessionRepository)
+		processor := NewAuthService(new(MockAuthRepository), mockSessions, nil, nil, time.Hour, true)
+
+		mockSessions.On("RotateSession", hashSecretToken("old"), mock.Anything, "jti2", mock.Anything, mock.Anything).
+			Return("", "", sql.ErrNoRows)
+
+		_, _, _, err := processor.RefreshSession(context.Background(), "old", "jti2")
+
+		assert.EqualError(t, err, "invalid refresh token")
+	})
+
+	t.Run("empty token", func(t *testing.T) {
+		processor := NewAuthService(new(MockAuthRepository), new(MockSessionRepository), nil, nil, time.Hour, true)
+
+		_, _, _, err := processor.RefreshSession(context.Background(), "", "jti2")
+
+		assert.EqualError(t, err, "invalid refresh token")
+	})
+}
+
+func TestAuthProcessor_Logout(t *testing.T) {
+	mockSessions := new(MockSessionRepository)
+	processor := NewAuthService(new(MockAuthRepository), mockSessions, nil, nil, time.Hour, true)
+	expiresAt := time.Now().Add(time.Hour)
+
+	mockSessions.On("RevokeSessionByAccessJTI", "jti1", mock.AnythingOfType("time.Time")).Return(nil)
+	mockSessions.On("RevokeToken", "jti1", expiresAt).Return(nil)
+
+	err := processor.Logout(context.Background(), "jti1", expiresAt)
+
+	assert.NoError(t, err)
+	mockSessions.AssertExpectations(t)
+}
+
+func TestAuthProcessor_IsTokenRevoked(t *testing.T) {
+	mockSessions := new(MockSessionRepository)
+	processor := NewAuthService(new(MockAuthRepository), mockSessions, nil, nil, time.Hour, true)
+
+	mockSessions.On("IsTokenRevoked", "jti1").Return(true, nil)
+	mockSessions.On("IsTokenRevoked", "jti2").Return(false, errors.New("connection reset"))
+
+	revoked, err := processor.IsTokenRevoked(context.Background(), "jti1")
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	_, err = processor.IsTokenRevoked(context.Background(), "jti2")
+	assert.EqualError(t, err, "database error")
+}
+
+func TestAuthProcessor_IsUserDisabled(t *testing.T) {
+	mockRepo := new(MockAuthRepository)
+	processor := NewAuthService(mockRepo, nil, nil, nil, time.Hour, true)
+
+	mockRepo.On("IsUserDisabled", "active").Return(false, nil)
+	mockRepo.On("IsUserDisabled", "deleted").Return(false, sql.ErrNoRows)
+	mockRepo.On("IsUserDisabled", "broken").Return(false, errors.New("connection refused"))
+
+	disabled, err := processor.IsUserDisabled(context.Background(), "active")
+	assert.NoError(t, err)
+	assert.False(t, disabled)
+
+	disabled, err = processor.IsUserDisabled(context.Background(), "deleted")
+	assert.NoError(t, err)
+	assert.True(t, disabled)
+
+	_, err = processor.IsUserDisabled(context.Background(), "broken")
+	assert.EqualError(t, err, "database error")
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/repository"
+)
+
+type CityService interface {
+	CreateCity(ctx context.Context, name string) (domain.City, error)
+	ListCities(ctx context.Context, includeInactive bool) ([]domain.City, error)
+	UpdateCity(ctx context.Context, id string, name *string, isActive *bool) (domain.City, error)
+	DeactivateCity(ctx context.Context, id string) error
+}
+
+type CityServiceImpl struct {
+	cityRepo repository.CityRepository
+}
+
+func NewCityService(cityRepo repository.CityRepository) *CityServiceImpl {
+	return &CityServiceImpl{cityRepo: cityRepo}
+}
+
+func (p *CityServiceImpl) CreateCity(ctx context.Context, name string) (domain.City, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return domain.City{}, errors.New("city name is required")
+	}
+
+	city, err := p.cityRepo.CreateCity(ctx, name, uuid.New)
+	if err != nil {
+		if errors.Is(err, domain.ErrCityAlreadyExists) {
+			return domain.City{}, err
+		}
+		return domain.City{}, internalError(ctx, "failed to create city", err)
+	}
+
+	return city, nil
+}
+
+func (p *CityServiceImpl) ListCities(ctx context.Context, includeInactive bool) ([]domain.City, error) {
+	cities, err := p.cityRepo.ListCities(ctx, !includeInactive)
+	if err != nil {
+		return nil, internalError(ctx, "database error", err)
+	}
+	return cities, nil
+}
+
+func (p *CityServiceImpl) UpdateCity(
+	ctx context.Context, id string, name *string, isActive *bool) (domain.City, error) {
+	if name != nil {
+		trimmed := strings.TrimSpace(*name)
+		if trimmed == "" {
+			return domain.City{}, errors.New("city name is required")
+		}
+		name = &trimmed
+	}
+
+	city, err := p.cityRepo.UpdateCity(ctx, id, name, isActive)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return domain.City{}, domain.ErrCityNotFound
+		case errors.Is(err, domain.ErrCityAlreadyExists):
+			return domain.City{}, err
+		}
+		return domain.City{}, internalError(ctx, "database error", err)
+	}
+
+	return city, nil
+}
+
+func (p *CityServiceImpl) DeactivateCity(ctx context.Context, id string) error {
+	if err := p.cityRepo.DeactivateCity(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrCityNotFound
+		}
+		return internalError(ctx, "database error", err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/domain"
+)
+
+type MockCityRepo struct {
+	mock.Mock
+}
+
+func (m *MockCityRepo) CreateCity(ctx context.Context, name string, idGenerator func() uuid.UUID) (domain.City, error) {
+	args := m.Called(name, idGenerator)
+	return args.Get(0).(domain.City), args.Error(1)
+}
+
+func (m *MockCityRepo) GetCityByID(ctx context.Context, id string) (domain.City, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.City), args.Error(1)
+}
+
+func (m *MockCityRepo) GetCityByName(ctx context.Context, name string) (domain.City, error) {
+	args := m.Called(name)
+	return args.Get(0).(domain.City), args.Error(1)
+}
+
+func (m *MockCityRepo) ListCities(ctx context.Context, onlyActive bool) ([]domain.City, error) {
+	args := m.Called(onlyActive)
+	return args.Get(0).([]domain.City), args.Error(1)
+}
+
+func (m *MockCityRepo) UpdateCity(ctx context.Context, id string, name *string, isActive *bool) (domain.City, error) {
+	args := m.Called(id, name, isActive)
+	return args.Get(0).(domain.City), args.Error(1)
+}
+
+func (m *MockCityRepo) DeactivateCity(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func TestCityService_CreateCity(t *testing.T) {
+	mockRepo := new(MockCityRepo)
+	processor := NewCityService(mockRepo)
+
+	t.Run("success", func(t *testing.T) {
+		expected := domain.City{ID: uuid.NewString(), Name: "Новосибирск", IsActive: true}
+		mockRepo.On("CreateCity", "Новосибирск", mock.AnythingOfType("func() uuid.UUID")).
+			Return(expected, nil)
+
+		city, err := processor.CreateCity(context.Background(), "  Новосибирск ")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, city)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("empty name", func(t *testing.T) {
+		_, err := processor.CreateCity(context.Background(), "   ")
+		assert.EqualError(t, err, "city name is required")
+	})
+
+	t.Run("already exists", func(t *testing.T) {
+		mockRepo.On("CreateCity", "Москва", mock.AnythingOfType("func() uuid.UUID")).
+			Return(domain.City{}, domain.ErrCityAlreadyExists)
+
+		_, err := processor.CreateCity(context.Background(), "Москва")
+		assert.ErrorIs(t, err, domain.ErrCityAlreadyExists)
+	})
+}
+
+func TestCityService_ListCities(t *testing.T) {
+	mockRepo := new(MockCityRepo)
+	processor := NewCityService(mockRepo)
+
+	t.Run("only active by default", func(t *testing.T) {
+		expected := []domain.City{{Name: "Казань", IsActive: true}}
+		mockRepo.On("ListCities", true).Return(expected, nil)
+
+		cities, err := processor.ListCities(context.Background(), false)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, cities)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo.On("ListCities", false).Return([]domain.City(nil), errors.New("db error"))
+
+		_, err := processor.ListCities(context.Background(), true)
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+func TestCityService_UpdateCity(t *testing.T) {
+	mockRepo := new(MockCityRepo)
+	processor := NewCityService(mockRepo)
+
+	t.Run("not found", func(t *testing.T) {
+		cityID := uuid.NewString()
+		isActive := true
+		mockRepo.On("UpdateCity", cityID, (*string)(nil), &isActive).Return(domain.City{}, sql.ErrNoRows)
+
+		_, err := processor.UpdateCity(context.Background(), cityID, nil, &isActive)
+		assert.ErrorIs(t, err, domain.ErrCityNotFound)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("blank name", func(t *testing.T) {
+		name := " "
+		_, err := processor.UpdateCity(context.Background(), uuid.NewString(), &name, nil)
+		assert.EqualError(t, err, "city name is required")
+	})
+}
+
+func TestCityService_DeactivateCity(t *testing.T) {
+	mockRepo := new(MockCityRepo)
+	processor := NewCityService(mockRepo)
+
+	t.Run("success", func(t *testing.T) {
+		cityID := uuid.NewString()
+		mockRepo.On("DeactivateCity", cityID).Return(nil)
+
+		assert.NoError(t, processor.DeactivateCity(context.Background(), cityID))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		cityID := uuid.NewString()
+		mockRepo.On("DeactivateCity", cityID).Return(sql.ErrNoRows)
+
+		assert.ErrorIs(t, processor.DeactivateCity(context.Background(), cityID), domain.ErrCityNotFound)
+	})
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/logging"
+	"pvz-service/internal/repository"
+)
+
+// internalError logs err with the request's fields and returns a
+// domain.InternalError carrying msg, which is all the client gets to see.
+func internalError(ctx context.Context, msg string, err error) error {
+	logging.FromContext(ctx).ErrorContext(ctx, msg, "error", err)
+	return &domain.InternalError{Msg: msg}
+}
+
+// withinTransaction runs fn through txManager. Errors from fn are already
+// client-facing and are returned as is; failing to begin or commit the
+// transaction is reported as msg.
+func withinTransaction(
+	ctx context.Context, txManager repository.TxManager, msg string, fn func(uow repository.UnitOfWork) error,
+) error {
+	var fnErr error
+	err := txManager.WithinTransaction(ctx, func(uow repository.UnitOfWork) error {
+		fnErr = fn(uow)
+		return fnErr
+	})
+	if err != nil && fnErr == nil {
+		return internalError(ctx, msg, err)
+	}
+	return err
+}
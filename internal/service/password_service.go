@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/notify"
+	"pvz-service/internal/repository"
+)
+
+type PasswordService interface {
+	ChangePassword(ctx context.Context, userID, currentPassword, newPassword string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+}
+
+type PasswordServiceImpl struct {
+	authRepo       repository.AuthRepository
+	sessionRepo    repository.SessionRepository
+	resetRepo      repository.PasswordResetRepository
+	passwordPolicy PasswordPolicy
+	notifier       notify.Notifier
+	resetTokenTTL  time.Duration
+}
+
+func NewPasswordService(
+	authRepo repository.AuthRepository,
+	sessionRepo repository.SessionRepository,
+	resetRepo repository.PasswordResetRepository,
+	passwordPolicy PasswordPolicy,
+	notifier notify.Notifier,
+	resetTokenTTL time.Duration,
+) *PasswordServiceImpl {
+	return &PasswordServiceImpl{
+		authRepo:       authRepo,
+		sessionRepo:    sessionRepo,
+		resetRepo:      resetRepo,
+		passwordPolicy: passwordPolicy,
+		notifier:       notifier,
+		resetTokenTTL:  resetTokenTTL,
+	}
+}
+
+// ChangePassword sets a new password after checking the current one. All sessions
+// of the user are revoked, so other devices have to log in again once their access
+// tokens expire.
+func (p *PasswordServiceImpl) ChangePassword(ctx context.Context, userID, currentPassword, newPassword string) error {
+	hashedPassword, err := p.authRepo.GetPasswordHash(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+		return internalError(ctx, "database error", err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(currentPassword)); err != nil {
+		return domain.ErrInvalidCurrentPassword
+	}
+
+	newHash, err := p.hashNewPassword(ctx, newPassword)
+	if err != nil {
+		return err
+	}
+
+	if err := p.authRepo.UpdatePassword(ctx, userID, newHash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+		return internalError(ctx, "database error", err)
+	}
+
+	return p.revokeSessions(ctx, userID)
+}
+
+// RequestPasswordReset sends a reset token to the email if it belongs to an active
+// user. It succeeds for unknown emails too, so the response does not reveal which
+// emails are registered.
+func (p *PasswordServiceImpl) RequestPasswordReset(ctx context.Context, email string) error {
+	email, err := domain.NormalizeEmail(email)
+	if err != nil {
+		return err
+	}
+
+	userID, _, _, err := p.authRepo.FindUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return internalError(ctx, "database error", err)
+	}
+
+	disabled, err := p.authRepo.IsUserDisabled(ctx, userID)
+	if err != nil {
+		return internalError(ctx, "database error", err)
+	}
+	if disabled {
+		return nil
+	}
+
+	token, err := newSecretToken()
+	if err != nil {
+		return internalError(ctx, "failed to create reset token", err)
+	}
+
+	now := time.Now()
+	err = p.resetRepo.CreateResetToken(ctx, userID, hashSecretToken(token), now.Add(p.resetTokenTTL), now)
+	if err != nil {
+		return internalError(ctx, "database error", err)
+	}
+
+	if err := p.notifier.SendPasswordReset(ctx, email, token); err != nil {
+		return internalError(ctx, "failed to send reset token", err)
+	}
+	return nil
+}
+
+// ResetPassword sets a new password using a reset token. The token works once and
+// all sessions of the user are revoked.
+func (p *PasswordServiceImpl) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if token == "" {
+		return domain.ErrInvalidResetToken
+	}
+
+	newHash, err := p.hashNewPassword(ctx, newPassword)
+	if err != nil {
+		return err
+	}
+
+	userID, err := p.resetRepo.ResetPassword(ctx, hashSecretToken(token), newHash, time.Now())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrInvalidResetToken
+		}
+		return internalError(ctx, "database error", err)
+	}
+
+	return p.revokeSessions(ctx, userID)
+}
+
+func (p *PasswordServiceImpl) hashNewPassword(ctx context.Context, password string) (string, error) {
+	if p.passwordPolicy != nil {
+		if err := p.passwordPolicy.Validate(password); err != nil {
+			return "", err
+		}
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcryptCost)
+	if err != nil {
+		return "", internalError(ctx, "failed to process password", err)
+	}
+	return string(hash), nil
+}
+
+func (p *PasswordServiceImpl) revokeSessions(ctx context.Context, userID string) error {
+	if err := p.sessionRepo.RevokeUserSessions(ctx, userID, time.Now()); err != nil {
+		return internalError(ctx, "database error", err)
+	}
+	return nil
+}
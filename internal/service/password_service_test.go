@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/domain"
+)
+
+type MockPasswordResetRepo struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetRepo) CreateResetToken(
+	ctx context.Context, userID, tokenHash string, expiresAt, now time.Time) error {
+	args := m.Called(userID, tokenHash, expiresAt, now)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetRepo) ResetPassword(
+	ctx context.Context, tokenHash, hashedPassword string, now time.Time) (string, error) {
+	args := m.Called(tokenHash, hashedPassword, now)
+	return args.String(0), args.Error(1)
+}
+
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) SendPasswordReset(ctx context.Context, email, token string) error {
+	args := m.Called(email, token)
+	return args.Error(0)
+}
+
+func TestPasswordService_ChangePassword(t *testing.T) {
+	hashedPassword, _ := NewAuthService(nil, nil, nil, nil, time.Hour, true).HashPassword("old-password")
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(MockAuthRepository)
+		mockSessions := new(MockSessionRepository)
+		processor := NewPasswordService(mockRepo, mockSessions, nil, lengthPolicy(8), nil, time.Hour)
+
+		mockRepo.On("GetPasswordHash", "user1").Return(hashedPassword, nil)
+		mockRepo.On("UpdatePassword", "user1", mock.AnythingOfType("string")).Return(nil)
+		mockSessions.On("RevokeUserSessions", "user1", mock.AnythingOfType("time.Time")).Return(nil)
+
+		err := processor.ChangePassword(context.Background(), "user1", "old-password", "new-password")
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockSessions.AssertExpectations(t)
+	})
+
+	t.Run("wrong current password", func(t *testing.T) {
+		mockRepo := new(MockAuthRepository)
+		processor := NewPasswordService(mockRepo, nil, nil, lengthPolicy(8), nil, time.Hour)
+
+		mockRepo.On("GetPasswordHash", "user1").Return(hashedPassword, nil)
+
+		err := processor.ChangePassword(context.Background(), "user1", "wrong", "new-password")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidCurrentPassword)
+		mockRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+	})
+
+	t.Run("weak new password", func(t *testing.T) {
+		mockRepo := new(MockAuthRepository)
+		processor := NewPasswordService(mockRepo, nil, nil, lengthPolicy(8), nil, time.Hour)
+
+		mockRepo.On("GetPasswordHash", "user1").Return(hashedPassword, nil)
+
+		err := processor.ChangePassword(context.Background(), "user1", "old-password", "short")
+
+		assert.ErrorIs(t, err, domain.ErrWeakPassword)
+	})
+}
+
+func TestPasswordService_RequestPasswordReset(t *testing.T) {
+	t.Run("known email", func(t *testing.T) {
+		mockRepo := new(MockAuthRepository)
+		mockResets := new(MockPasswordResetRepo)
+		mockNotifier := new(MockNotifier)
+		processor := NewPasswordService(mockRepo, nil, mockResets, nil, mockNotifier, time.Hour)
+
+		mockRepo.On("FindUserByEmail", "user@example.com").Return("user1", "hash", "employee", nil)
+		mockRepo.On("IsUserDisabled", "user1").Return(false, nil)
+		mockResets.On("CreateResetToken", "user1", mock.AnythingOfType("string"), mock.Anything, mock.Anything).
+			Return(nil)
+		mockNotifier.On("SendPasswordReset", "user@example.com", mock.AnythingOfType("string")).Return(nil)
+
+		err := processor.RequestPasswordReset(context.Background(), "User@Example.com")
+
+		assert.NoError(t, err)
+		sentToken := mockNotifier.Calls[0].Arguments.String(1)
+		assert.Equal(t, hashSecretToken(sentToken), mockResets.Calls[0].Arguments.String(1))
+	})
+
+	t.Run("unknown email", func(t *testing.T) {
+		mockRepo := new(MockAuthRepository)
+		mockNotifier := new(MockNotifier)
+		processor := NewPasswordService(mockRepo, nil, nil, nil, mockNotifier, time.Hour)
+
+		mockRepo.On("FindUserByEmail", "nobody@example.com").Return("", "", "", sql.ErrNoRows)
+
+		assert.NoError(t, processor.RequestPasswordReset(context.Background(), "nobody@example.com"))
+		mockNotifier.AssertNotCalled(t, "SendPasswordReset", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid email", func(t *testing.T) {
+		processor := NewPasswordService(nil, nil, nil, nil, nil, time.Hour)
+
+		assert.ErrorIs(t, processor.RequestPasswordReset(context.Background(), "nope"), domain.ErrInvalidEmail)
+	})
+}
+
+func TestPasswordService_ResetPassword(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockSessions := new(MockSessionRepository)
+		mockResets := new(MockPasswordResetRepo)
+		processor := NewPasswordService(nil, mockSessions, mockResets, lengthPolicy(8), nil, time.Hour)
+
+		mockResets.On("ResetPassword", hashSecretToken("token1"), mock.AnythingOfType("string"), mock.Anything).
+			Return("user1", nil)
+		mockSessions.On("RevokeUserSessions", "user1", mock.AnythingOfType("time.Time")).Return(nil)
+
+		assert.NoError(t, processor.ResetPassword(context.Background(), "token1", "new-password"))
+		mockSessions.AssertExpectations(t)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		mockResets := new(MockPasswordResetRepo)
+		processor := NewPasswordService(nil, nil, mockResets, lengthPolicy(8), nil, time.Hour)
+
+		mockResets.On("ResetPassword", hashSecretToken("used"), mock.AnythingOfType("string"), mock.Anything).
+			Return("", sql.ErrNoRows)
+
+		err := processor.ResetPassword(context.Background(), "used", "new-password")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidResetToken)
+	})
+
+	t.Run("weak password", func(t *testing.T) {
+		processor := NewPasswordService(nil, nil, nil, lengthPolicy(8), nil, time.Hour)
+
+		err := processor.ResetPassword(context.Background(), "token1", "short")
+
+		assert.ErrorIs(t, err, domain.ErrWeakPassword)
+	})
+}
@@ -1,82 +1,134 @@
 package service
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/google/uuid"
+	"strings"
 
 	"pvz-service/internal/domain"
+	"pvz-service/internal/events"
+	"pvz-service/internal/repository"
 )
 
-type ProductService interface {
-	AddProduct(receptionID string, productType string, idGenerator func() uuid.UUID) (string, error)
-	GetProductByID(id string) (domain.Product, error)
-	GetLastProduct(receptionID string) (domain.Product, error)
-	DeleteProduct(id string) error
-}
-
-type ReceptionRepository interface {
-	GetOpenReception(pvzID string) (domain.Reception, error)
-}
-
 type ProductServiceImpl struct {
-	productRepo   ProductService
-	receptionRepo ReceptionRepository
+	txManager       repository.TxManager
+	productTypeRepo repository.ProductTypeRepository
+	events          events.Publisher
 }
 
 func NewProductService(
-	productRepo ProductService,
-	receptionRepo ReceptionRepository,
+	txManager repository.TxManager,
+	productTypeRepo repository.ProductTypeRepository,
+	publisher events.Publisher,
 ) *ProductServiceImpl {
 	return &ProductServiceImpl{
-		productRepo:   productRepo,
-		receptionRepo: receptionRepo,
+		txManager:       txManager,
+		productTypeRepo: productTypeRepo,
+		events:          publisher,
 	}
 }
 
-func (p *ProductServiceImpl) AddProduct(pvzID, productType string) (domain.Product, error) {
-	allowedTypes := map[string]bool{
-		"электроника": true,
-		"одежда":      true,
-		"обувь":       true,
+func (p *ProductServiceImpl) AddProduct(
+	ctx context.Context, pvzID, productType string, attributes map[string]string) (domain.Product, error) {
+	catalogType, err := p.productTypeRepo.GetProductTypeByCode(ctx, productType)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Product{}, errors.New("invalid product type")
+		}
+		return domain.Product{}, internalError(ctx, "database error", err)
 	}
 
-	if !allowedTypes[productType] {
+	if !catalogType.IsActive {
 		return domain.Product{}, errors.New("invalid product type")
 	}
 
-	reception, err := p.receptionRepo.GetOpenReception(pvzID)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return domain.Product{}, errors.New("no open reception for this PVZ")
-		}
-		return domain.Product{}, errors.New("database error")
+	if err := validateProductAttributes(catalogType, attributes); err != nil {
+		return domain.Product{}, err
 	}
 
-	productID, err := p.productRepo.AddProduct(reception.ID, productType, uuid.New)
+	var product domain.Product
+	err = withinTransaction(ctx, p.txManager, "failed to add product", func(uow repository.UnitOfWork) error {
+		reception, err := uow.Receptions().GetOpenReceptionForUpdate(ctx, pvzID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrNoOpenReception
+			}
+			return internalError(ctx, "database error", err)
+		}
+
+		productID, err := uow.Products().AddProduct(ctx, reception.ID, productType, attributes, uuid.New)
+		if err != nil {
+			return internalError(ctx, "failed to add product", err)
+		}
+
+		product, err = uow.Products().GetProductByID(ctx, productID)
+		if err != nil {
+			return internalError(ctx, "database error", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return domain.Product{}, errors.New("failed to add product")
+		return domain.Product{}, err
 	}
 
-	return p.productRepo.GetProductByID(productID)
+	publish(p.events, events.Event{
+		Type: events.ProductAdded, PVZID: pvzID, ReceptionID: product.ReceptionId, ProductID: product.ID,
+	})
+	return product, nil
 }
 
-func (p *ProductServiceImpl) DeleteLastProduct(pvzID string) error {
-	reception, err := p.receptionRepo.GetOpenReception(pvzID)
+func (p *ProductServiceImpl) DeleteLastProduct(ctx context.Context, pvzID string) error {
+	var product domain.Product
+	err := withinTransaction(ctx, p.txManager, "failed to delete product", func(uow repository.UnitOfWork) error {
+		reception, err := uow.Receptions().GetOpenReceptionForUpdate(ctx, pvzID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrNoOpenReception
+			}
+			return internalError(ctx, "database error", err)
+		}
+
+		product, err = uow.Products().GetLastProduct(ctx, reception.ID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrNoProductsToDelete
+			}
+			return internalError(ctx, "database error", err)
+		}
+
+		if err := uow.Products().DeleteProduct(ctx, product.ID); err != nil {
+			return internalError(ctx, "failed to delete product", err)
+		}
+		return nil
+	})
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return errors.New("no open reception for this PVZ")
+		return err
+	}
+
+	publish(p.events, events.Event{
+		Type: events.ProductDeleted, PVZID: pvzID, ReceptionID: product.ReceptionId, ProductID: product.ID,
+	})
+	return nil
+}
+
+func validateProductAttributes(productType domain.ProductType, attributes map[string]string) error {
+	allowed := make(map[string]bool, len(productType.RequiredAttributes))
+	for _, name := range productType.RequiredAttributes {
+		allowed[name] = true
+		if strings.TrimSpace(attributes[name]) == "" {
+			return fmt.Errorf("%w: missing required attribute %q", domain.ErrInvalidProductAttributes, name)
 		}
-		return errors.New("database error")
 	}
 
-	product, err := p.productRepo.GetLastProduct(reception.ID)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return errors.New("no products to delete in this reception")
+	for name := range attributes {
+		if !allowed[name] {
+			return fmt.Errorf("%w: unknown attribute %q for type %q",
+				domain.ErrInvalidProductAttributes, name, productType.Code)
 		}
-		return errors.New("database error")
 	}
 
-	return p.productRepo.DeleteProduct(product.ID)
+	return nil
 }
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"database/sql"
 	"testing"
 
 	"github.com/google/uuid"
@@ -8,83 +10,197 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	"pvz-service/internal/domain"
+	"pvz-service/internal/events"
+	"pvz-service/internal/repository"
 )
 
 type MockProductRepo struct {
 	mock.Mock
 }
 
-func (m *MockProductRepo) AddProduct(receptionID, productType string, idGenerator func() uuid.UUID) (string, error) {
-	args := m.Called(receptionID, productType, idGenerator)
+func (m *MockProductRepo) AddProduct(
+	ctx context.Context, receptionID, productType string, attributes map[string]string, idGenerator func() uuid.UUID) (string, error) {
+	args := m.Called(receptionID, productType, attributes, idGenerator)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockProductRepo) GetProductByID(id string) (domain.Product, error) {
+func (m *MockProductRepo) GetProductByID(ctx context.Context, id string) (domain.Product, error) {
 	args := m.Called(id)
 	return args.Get(0).(domain.Product), args.Error(1)
 }
 
-func (m *MockProductRepo) GetLastProduct(receptionID string) (domain.Product, error) {
+func (m *MockProductRepo) GetLastProduct(ctx context.Context, receptionID string) (domain.Product, error) {
 	args := m.Called(receptionID)
 	return args.Get(0).(domain.Product), args.Error(1)
 }
 
-func (m *MockProductRepo) DeleteProduct(id string) error {
+func (m *MockProductRepo) DeleteProduct(ctx context.Context, id string) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-type MockReceptionRepo struct {
-	mock.Mock
+func (m *MockProductRepo) ListProductsByReception(
+	ctx context.Context, receptionID string, limit, offset int) ([]domain.Product, int, error) {
+	args := m.Called(receptionID, limit, offset)
+	return args.Get(0).([]domain.Product), args.Int(1), args.Error(2)
+}
+
+func (m *MockProductRepo) ListProductsByReceptions(
+	ctx context.Context, receptionIDs []string) (map[string][]domain.Product, error) {
+	args := m.Called(receptionIDs)
+	return args.Get(0).(map[string][]domain.Product), args.Error(1)
+}
+
+type MockUnitOfWork struct {
+	pvzRepo       repository.PVZRepository
+	receptionRepo repository.ReceptionRepository
+	productRepo   repository.ProductRepository
 }
 
-func (m *MockReceptionRepo) GetOpenReception(pvzID string) (domain.Reception, error) {
-	args := m.Called(pvzID)
-	return args.Get(0).(domain.Reception), args.Error(1)
+func (u *MockUnitOfWork) PVZ() repository.PVZRepository {
+	return u.pvzRepo
+}
+
+func (u *MockUnitOfWork) Receptions() repository.ReceptionRepository {
+	return u.receptionRepo
+}
+
+func (u *MockUnitOfWork) Products() repository.ProductRepository {
+	return u.productRepo
+}
+
+type MockTxManager struct {
+	uow repository.UnitOfWork
+}
+
+func (m *MockTxManager) WithinTransaction(ctx context.Context, fn func(uow repository.UnitOfWork) error) error {
+	return fn(m.uow)
+}
+
+type recordingPublisher struct {
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(event events.Event) {
+	p.events = append(p.events, event)
 }
 
 func TestProductProcessor_AddProduct_Success(t *testing.T) {
 	mockProductRepo := new(MockProductRepo)
-	mockReceptionRepo := new(MockReceptionRepo)
-	processor := NewProductService(mockProductRepo, mockReceptionRepo)
+	mockReceptionRepo := new(MockReceptionRepository)
+	mockProductTypeRepo := new(MockProductTypeRepo)
+	txManager := &MockTxManager{uow: &MockUnitOfWork{receptionRepo: mockReceptionRepo, productRepo: mockProductRepo}}
+	publisher := &recordingPublisher{}
+	processor := NewProductService(txManager, mockProductTypeRepo, publisher)
 
 	pvzID := uuid.NewString()
 	receptionID := uuid.NewString()
 	productID := uuid.NewString()
 
-	mockReceptionRepo.On("GetOpenReception", pvzID).Return(
+	attributes := map[string]string{"serial_number": "SN-1"}
+
+	mockProductTypeRepo.On("GetProductTypeByCode", "электроника").Return(domain.ProductType{
+		Code: "электроника", RequiredAttributes: []string{"serial_number"}, IsActive: true}, nil)
+
+	mockReceptionRepo.On("GetOpenReceptionForUpdate", pvzID).Return(
 		domain.Reception{ID: receptionID}, nil)
 
-	mockProductRepo.On("AddProduct", receptionID, "электроника", mock.AnythingOfType("func() uuid.UUID")).
+	mockProductRepo.On("AddProduct", receptionID, "электроника", attributes, mock.AnythingOfType("func() uuid.UUID")).
 		Return(productID, nil)
 
 	mockProductRepo.On("GetProductByID", productID).Return(
-		domain.Product{ID: productID, Type: "электроника"}, nil)
+		domain.Product{ID: productID, Type: "электроника", ReceptionId: receptionID, Attributes: attributes}, nil)
 
-	product, err := processor.AddProduct(pvzID, "электроника")
+	product, err := processor.AddProduct(context.Background(), pvzID, "электроника", attributes)
 	assert.NoError(t, err)
 	assert.Equal(t, "электроника", product.Type)
+	assert.Equal(t, "SN-1", product.Attributes["serial_number"])
+	assert.Equal(t, []events.Event{{
+		Type: events.ProductAdded, PVZID: pvzID, ReceptionID: receptionID, ProductID: productID,
+	}}, publisher.events)
 	mockProductRepo.AssertExpectations(t)
 	mockReceptionRepo.AssertExpectations(t)
+	mockProductTypeRepo.AssertExpectations(t)
+}
+
+func TestProductProcessor_AddProduct_NoOpenReception(t *testing.T) {
+	mockProductRepo := new(MockProductRepo)
+	mockReceptionRepo := new(MockReceptionRepository)
+	mockProductTypeRepo := new(MockProductTypeRepo)
+	txManager := &MockTxManager{uow: &MockUnitOfWork{receptionRepo: mockReceptionRepo, productRepo: mockProductRepo}}
+	processor := NewProductService(txManager, mockProductTypeRepo, nil)
+
+	pvzID := uuid.NewString()
+
+	mockProductTypeRepo.On("GetProductTypeByCode", "одежда").
+		Return(domain.ProductType{Code: "одежда", IsActive: true}, nil)
+	mockReceptionRepo.On("GetOpenReceptionForUpdate", pvzID).Return(domain.Reception{}, sql.ErrNoRows)
+
+	_, err := processor.AddProduct(context.Background(), pvzID, "одежда", nil)
+	assert.ErrorIs(t, err, domain.ErrNoOpenReception)
+	mockProductRepo.AssertNotCalled(t, "AddProduct", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProductProcessor_AddProduct_InvalidType(t *testing.T) {
+	mockProductTypeRepo := new(MockProductTypeRepo)
+	processor := NewProductService(&MockTxManager{}, mockProductTypeRepo, nil)
+
+	t.Run("unknown type", func(t *testing.T) {
+		mockProductTypeRepo.On("GetProductTypeByCode", "мебель").Return(domain.ProductType{}, sql.ErrNoRows)
+
+		_, err := processor.AddProduct(context.Background(), uuid.NewString(), "мебель", nil)
+		assert.EqualError(t, err, "invalid product type")
+	})
+
+	t.Run("inactive type", func(t *testing.T) {
+		mockProductTypeRepo.On("GetProductTypeByCode", "одежда").
+			Return(domain.ProductType{Code: "одежда", IsActive: false}, nil)
+
+		_, err := processor.AddProduct(context.Background(), uuid.NewString(), "одежда", nil)
+		assert.EqualError(t, err, "invalid product type")
+	})
+}
+
+func TestProductProcessor_AddProduct_InvalidAttributes(t *testing.T) {
+	mockProductTypeRepo := new(MockProductTypeRepo)
+	processor := NewProductService(&MockTxManager{}, mockProductTypeRepo, nil)
+
+	mockProductTypeRepo.On("GetProductTypeByCode", "обувь").Return(domain.ProductType{
+		Code: "обувь", RequiredAttributes: []string{"size"}, IsActive: true}, nil)
+
+	t.Run("missing required attribute", func(t *testing.T) {
+		_, err := processor.AddProduct(context.Background(), uuid.NewString(), "обувь", map[string]string{"size": " "})
+		assert.ErrorIs(t, err, domain.ErrInvalidProductAttributes)
+	})
+
+	t.Run("unknown attribute", func(t *testing.T) {
+		_, err := processor.AddProduct(context.Background(), uuid.NewString(), "обувь", map[string]string{"size": "42", "color": "red"})
+		assert.ErrorIs(t, err, domain.ErrInvalidProductAttributes)
+	})
 }
 
 func TestProductProcessor_DeleteLastProduct_Success(t *testing.T) {
 	mockProductRepo := new(MockProductRepo)
-	mockReceptionRepo := new(MockReceptionRepo)
-	processor := NewProductService(mockProductRepo, mockReceptionRepo)
+	mockReceptionRepo := new(MockReceptionRepository)
+	txManager := &MockTxManager{uow: &MockUnitOfWork{receptionRepo: mockReceptionRepo, productRepo: mockProductRepo}}
+	publisher := &recordingPublisher{}
+	processor := NewProductService(txManager, new(MockProductTypeRepo), publisher)
 
 	pvzID := uuid.NewString()
 	receptionID := uuid.NewString()
 	productID := uuid.NewString()
 
-	mockReceptionRepo.On("GetOpenReception", pvzID).Return(
+	mockReceptionRepo.On("GetOpenReceptionForUpdate", pvzID).Return(
 		domain.Reception{ID: receptionID}, nil)
 	mockProductRepo.On("GetLastProduct", receptionID).Return(
-		domain.Product{ID: productID}, nil)
+		domain.Product{ID: productID, ReceptionId: receptionID}, nil)
 	mockProductRepo.On("DeleteProduct", productID).Return(nil)
 
-	err := processor.DeleteLastProduct(pvzID)
+	err := processor.DeleteLastProduct(context.Background(), pvzID)
 	assert.NoError(t, err)
+	assert.Equal(t, []events.Event{{
+		Type: events.ProductDeleted, PVZID: pvzID, ReceptionID: receptionID, ProductID: productID,
+	}}, publisher.events)
 	mockProductRepo.AssertExpectations(t)
 	mockReceptionRepo.AssertExpectations(t)
 }
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/repository"
+)
+
+type ProductTypeService interface {
+	CreateProductType(
+		ctx context.Context, code, displayName string, requiredAttributes []string) (domain.ProductType, error)
+	ListProductTypes(ctx context.Context, includeInactive bool) ([]domain.ProductType, error)
+	UpdateProductType(
+		ctx context.Context, code string, displayName *string, requiredAttributes []string, isActive *bool,
+	) (domain.ProductType, error)
+	DeactivateProductType(ctx context.Context, code string) error
+}
+
+type ProductTypeServiceImpl struct {
+	productTypeRepo repository.ProductTypeRepository
+}
+
+func NewProductTypeService(productTypeRepo repository.ProductTypeRepository) *ProductTypeServiceImpl {
+	return &ProductTypeServiceImpl{productTypeRepo: productTypeRepo}
+}
+
+func (p *ProductTypeServiceImpl) CreateProductType(
+	ctx context.Context, code, displayName string, requiredAttributes []string) (domain.ProductType, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return domain.ProductType{}, errors.New("product type code is required")
+	}
+
+	displayName = strings.TrimSpace(displayName)
+	if displayName == "" {
+		return domain.ProductType{}, errors.New("display name is required")
+	}
+
+	attributes, err := normalizeAttributeNames(requiredAttributes)
+	if err != nil {
+		return domain.ProductType{}, err
+	}
+
+	productType, err := p.productTypeRepo.CreateProductType(ctx, domain.ProductType{
+		Code:               code,
+		DisplayName:        displayName,
+		RequiredAttributes: attributes,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrProductTypeAlreadyExists) {
+			return domain.ProductType{}, err
+		}
+		return domain.ProductType{}, internalError(ctx, "failed to create product type", err)
+	}
+
+	return productType, nil
+}
+
+func (p *ProductTypeServiceImpl) ListProductTypes(
+	ctx context.Context, includeInactive bool) ([]domain.ProductType, error) {
+	productTypes, err := p.productTypeRepo.ListProductTypes(ctx, !includeInactive)
+	if err != nil {
+		return nil, internalError(ctx, "database error", err)
+	}
+	return productTypes, nil
+}
+
+func (p *ProductTypeServiceImpl) UpdateProductType(
+	ctx context.Context, code string, displayName *string, requiredAttributes []string, isActive *bool,
+) (domain.ProductType, error) {
+	if displayName != nil {
+		trimmed := strings.TrimSpace(*displayName)
+		if trimmed == "" {
+			return domain.ProductType{}, errors.New("display name is required")
+		}
+		displayName = &trimmed
+	}
+
+	if requiredAttributes != nil {
+		attributes, err := normalizeAttributeNames(requiredAttributes)
+		if err != nil {
+			return domain.ProductType{}, err
+		}
+		requiredAttributes = attributes
+	}
+
+	productType, err := p.productTypeRepo.UpdateProductType(ctx, code, displayName, requiredAttributes, isActive)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ProductType{}, domain.ErrProductTypeNotFound
+		}
+		return domain.ProductType{}, internalError(ctx, "database error", err)
+	}
+
+	return productType, nil
+}
+
+func (p *ProductTypeServiceImpl) DeactivateProductType(ctx context.Context, code string) error {
+	if err := p.productTypeRepo.DeactivateProductType(ctx, code); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrProductTypeNotFound
+		}
+		return internalError(ctx, "database error", err)
+	}
+	return nil
+}
+
+func normalizeAttributeNames(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, errors.New("attribute name must not be empty")
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/domain"
+)
+
+type MockProductTypeRepo struct {
+	mock.Mock
+}
+
+func (m *MockProductTypeRepo) CreateProductType(ctx context.Context, productType domain.ProductType) (domain.ProductType, error) {
+	args := m.Called(productType)
+	return args.Get(0).(domain.ProductType), args.Error(1)
+}
+
+func (m *MockProductTypeRepo) GetProductTypeByCode(ctx context.Context, code string) (domain.ProductType, error) {
+	args := m.Called(code)
+	return args.Get(0).(domain.ProductType), args.Error(1)
+}
+
+func (m *MockProductTypeRepo) ListProductTypes(ctx context.Context, onlyActive bool) ([]domain.ProductType, error) {
+	args := m.Called(onlyActive)
+	return args.Get(0).([]domain.ProductType), args.Error(1)
+}
+
+func (m *MockProductTypeRepo) UpdateProductType(
+	ctx context.Context, code string, displayName *string, requiredAttributes []string, isActive *bool) (domain.ProductType, error) {
+	args := m.Called(code, displayName, requiredAttributes, isActive)
+	return args.Get(0).(domain.ProductType), args.Error(1)
+}
+
+func (m *MockProductTypeRepo) DeactivateProductType(ctx context.Context, code string) error {
+	args := m.Called(code)
+	return args.Error(0)
+}
+
+func TestProductTypeService_CreateProductType(t *testing.T) {
+	mockRepo := new(MockProductTypeRepo)
+	processor := NewProductTypeService(mockRepo)
+
+	t.Run("success", func(t *testing.T) {
+		expected := domain.ProductType{
+			Code:               "обувь",
+			DisplayName:        "Обувь",
+			RequiredAttributes: []string{"size"},
+			IsActive:           true,
+		}
+		mockRepo.On("CreateProductType", domain.ProductType{
+			Code:               "обувь",
+			DisplayName:        "Обувь",
+			RequiredAttributes: []string{"size"},
+		}).Return(expected, nil)
+
+		productType, err := processor.CreateProductType(context.Background(), " обувь ", "Обувь", []string{"size", " size"})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, productType)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("missing code", func(t *testing.T) {
+		_, err := processor.CreateProductType(context.Background(), "", "Обувь", nil)
+		assert.EqualError(t, err, "product type code is required")
+	})
+
+	t.Run("empty attribute name", func(t *testing.T) {
+		_, err := processor.CreateProductType(context.Background(), "мебель", "Мебель", []string{""})
+		assert.EqualError(t, err, "attribute name must not be empty")
+	})
+
+	t.Run("already exists", func(t *testing.T) {
+		mockRepo.On("CreateProductType", mock.MatchedBy(func(pt domain.ProductType) bool {
+			return pt.Code == "одежда"
+		})).Return(domain.ProductType{}, domain.ErrProductTypeAlreadyExists)
+
+		_, err := processor.CreateProductType(context.Background(), "одежда", "Одежда", nil)
+		assert.ErrorIs(t, err, domain.ErrProductTypeAlreadyExists)
+	})
+}
+
+func TestProductTypeService_UpdateProductType(t *testing.T) {
+	mockRepo := new(MockProductTypeRepo)
+	processor := NewProductTypeService(mockRepo)
+
+	t.Run("success", func(t *testing.T) {
+		attributes := []string{"serial_number"}
+		mockRepo.On("UpdateProductType", "электроника", (*string)(nil), attributes, (*bool)(nil)).
+			Return(domain.ProductType{Code: "электроника", RequiredAttributes: attributes}, nil)
+
+		productType, err := processor.UpdateProductType(context.Background(), "электроника", nil, attributes, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, attributes, productType.RequiredAttributes)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		isActive := true
+		mockRepo.On("UpdateProductType", "мебель", (*string)(nil), []string(nil), &isActive).
+			Return(domain.ProductType{}, sql.ErrNoRows)
+
+		_, err := processor.UpdateProductType(context.Background(), "мебель", nil, nil, &isActive)
+		assert.ErrorIs(t, err, domain.ErrProductTypeNotFound)
+	})
+}
+
+func TestProductTypeService_DeactivateProductType(t *testing.T) {
+	mockRepo := new(MockProductTypeRepo)
+	processor := NewProductTypeService(mockRepo)
+
+	mockRepo.On("DeactivateProductType", "обувь").Return(nil)
+	mockRepo.On("DeactivateProductType", "мебель").Return(sql.ErrNoRows)
+	mockRepo.On("DeactivateProductType", "одежда").Return(errors.New("db error"))
+
+	assert.NoError(t, processor.DeactivateProductType(context.Background(), "обувь"))
+	assert.ErrorIs(t, processor.DeactivateProductType(context.Background(), "мебель"), domain.ErrProductTypeNotFound)
+	assert.EqualError(t, processor.DeactivateProductType(context.Background(), "одежда"), "database error")
+}
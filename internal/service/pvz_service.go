@@ -1,6 +1,10 @@
 package service
 
 import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"time"
 
@@ -10,63 +14,187 @@ import (
 )
 
 type PVZService interface {
-	CreatePVZ(city string) (domain.PVZ, error)
-	GetPVZByID(id string) (domain.PVZ, error)
-	ListPVZsWithRelations(startDate, endDate string, page, limit int) ([]repository.PVZResponse, error)
+	CreatePVZ(ctx context.Context, city string) (domain.PVZ, error)
+	GetPVZByID(ctx context.Context, id string) (domain.PVZ, error)
+	ListPVZs(ctx context.Context) ([]domain.PVZ, error)
+	ListPVZsWithRelations(ctx context.Context, filter repository.PVZFilter, sort repository.PVZSort,
+		page, limit int) (repository.PVZListResponse, error)
+	ListPVZsByCursor(
+		ctx context.Context, filter repository.PVZFilter, cursor string, limit int) (repository.PVZCursorResponse, error)
 }
 
+// MaxCursorPageLimit caps a single cursor page; cursor mode is meant for full scans,
+// so it allows larger pages than page/limit mode.
+const MaxCursorPageLimit = 1000
+
 type PVZServiceImpl struct {
-	pvzRepo repository.PVZRepository
+	pvzRepo  repository.PVZRepository
+	cityRepo repository.CityRepository
 }
 
-func NewPVZService(pvzRepo repository.PVZRepository) *PVZServiceImpl {
-	return &PVZServiceImpl{pvzRepo: pvzRepo}
+func NewPVZService(pvzRepo repository.PVZRepository, cityRepo repository.CityRepository) *PVZServiceImpl {
+	return &PVZServiceImpl{
+		pvzRepo:  pvzRepo,
+		cityRepo: cityRepo,
+	}
 }
 
-func (p *PVZServiceImpl) CreatePVZ(city string) (domain.PVZ, error) {
-	allowedCities := map[string]bool{
-		"Москва":          true,
-		"Санкт-Петербург": true,
-		"Казань":          true,
+func (p *PVZServiceImpl) CreatePVZ(ctx context.Context, city string) (domain.PVZ, error) {
+	catalogCity, err := p.cityRepo.GetCityByName(ctx, city)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.PVZ{}, errors.New("invalid city")
+		}
+		return domain.PVZ{}, internalError(ctx, "database error", err)
 	}
 
-	if !allowedCities[city] {
+	if !catalogCity.IsActive {
 		return domain.PVZ{}, errors.New("invalid city")
 	}
 
-	return p.pvzRepo.CreatePVZ(city, uuid.New)
+	pvz, err := p.pvzRepo.CreatePVZ(ctx, city, uuid.New)
+	if err != nil {
+		return domain.PVZ{}, internalError(ctx, "failed to create pvz", err)
+	}
+	return pvz, nil
 }
 
-func (p *PVZServiceImpl) GetPVZByID(id string) (domain.PVZ, error) {
-	return p.pvzRepo.GetPVZByID(id)
+func (p *PVZServiceImpl) GetPVZByID(ctx context.Context, id string) (domain.PVZ, error) {
+	pvz, err := p.pvzRepo.GetPVZByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.PVZ{}, domain.ErrPVZNotFound
+		}
+		return domain.PVZ{}, internalError(ctx, "database error", err)
+	}
+	return pvz, nil
 }
 
-func (p *PVZServiceImpl) ListPVZsWithRelations(startDate, endDate string, page, limit int) ([]repository.PVZResponse, error) {
-	var start, end time.Time
-	var err error
+func (p *PVZServiceImpl) ListPVZs(ctx context.Context) ([]domain.PVZ, error) {
+	pvzs, err := p.pvzRepo.ListPVZs(ctx)
+	if err != nil {
+		return nil, internalError(ctx, "database error", err)
+	}
+	return pvzs, nil
+}
 
-	if startDate != "" {
-		start, err = time.Parse(time.RFC3339, startDate)
-		if err != nil {
-			return nil, errors.New("invalid start date format")
-		}
+func (p *PVZServiceImpl) ListPVZsWithRelations(ctx context.Context, filter repository.PVZFilter,
+	sort repository.PVZSort, page, limit int) (repository.PVZListResponse, error) {
+	if err := validatePVZFilter(filter); err != nil {
+		return repository.PVZListResponse{}, err
 	}
 
-	if endDate != "" {
-		end, err = time.Parse(time.RFC3339, endDate)
-		if err != nil {
-			return nil, errors.New("invalid end date format")
-		}
+	if sort.Field != "" && !repository.IsValidPVZSortField(sort.Field) {
+		return repository.PVZListResponse{}, errors.New("invalid sort field")
 	}
 
 	if page < 1 {
-		return nil, errors.New("invalid page number")
+		return repository.PVZListResponse{}, errors.New("invalid page number")
 	}
 
 	if limit < 1 || limit > 30 {
-		return nil, errors.New("invalid limit")
+		return repository.PVZListResponse{}, errors.New("invalid limit")
 	}
 
 	offset := (page - 1) * limit
-	return p.pvzRepo.ListPVZsWithRelations(start, end, limit, offset)
+	items, total, err := p.pvzRepo.ListPVZsWithRelations(ctx, filter, sort, limit, offset)
+	if err != nil {
+		return repository.PVZListResponse{}, internalError(ctx, "database error", err)
+	}
+
+	return repository.PVZListResponse{
+		Items:      items,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (total + limit - 1) / limit,
+	}, nil
+}
+
+// ListPVZsByCursor walks PVZs in (registration_date, id) order. An empty cursor starts from the
+// beginning; NextCursor is empty once the last page has been returned.
+func (p *PVZServiceImpl) ListPVZsByCursor(
+	ctx context.Context, filter repository.PVZFilter, cursor string, limit int) (repository.PVZCursorResponse, error) {
+	if err := validatePVZFilter(filter); err != nil {
+		return repository.PVZCursorResponse{}, err
+	}
+
+	if limit < 1 || limit > MaxCursorPageLimit {
+		return repository.PVZCursorResponse{}, errors.New("invalid limit")
+	}
+
+	var after *repository.PVZKey
+	if cursor != "" {
+		key, err := decodePVZCursor(cursor)
+		if err != nil {
+			return repository.PVZCursorResponse{}, errors.New("invalid cursor")
+		}
+		after = &key
+	}
+
+	items, err := p.pvzRepo.ListPVZsWithRelationsAfter(ctx, filter, after, limit+1)
+	if err != nil {
+		return repository.PVZCursorResponse{}, internalError(ctx, "database error", err)
+	}
+
+	result := repository.PVZCursorResponse{Items: items, Limit: limit}
+	if len(items) > limit {
+		result.Items = items[:limit]
+		last := result.Items[limit-1].PVZ
+		result.NextCursor = encodePVZCursor(repository.PVZKey{RegistrationDate: last.RegistrationDate, ID: last.ID})
+	}
+
+	return result, nil
+}
+
+func validatePVZFilter(filter repository.PVZFilter) error {
+	if !filter.StartDate.IsZero() && !filter.EndDate.IsZero() && filter.StartDate.After(filter.EndDate) {
+		return errors.New("start date must not be after end date")
+	}
+
+	switch filter.ReceptionStatus {
+	case "", "in_progress", "close":
+	default:
+		return errors.New("invalid reception status")
+	}
+
+	if (filter.MinProducts != nil && *filter.MinProducts < 0) || (filter.MaxProducts != nil && *filter.MaxProducts < 0) {
+		return errors.New("product count bounds must not be negative")
+	}
+
+	if filter.MinProducts != nil && filter.MaxProducts != nil && *filter.MinProducts > *filter.MaxProducts {
+		return errors.New("minProducts must not be greater than maxProducts")
+	}
+
+	return nil
+}
+
+type pvzCursor struct {
+	RegistrationDate time.Time `json:"d"`
+	ID               string    `json:"id"`
+}
+
+func encodePVZCursor(key repository.PVZKey) string {
+	raw, _ := json.Marshal(pvzCursor{RegistrationDate: key.RegistrationDate, ID: key.ID})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodePVZCursor(cursor string) (repository.PVZKey, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return repository.PVZKey{}, err
+	}
+
+	var decoded pvzCursor
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return repository.PVZKey{}, err
+	}
+	if decoded.ID == "" || decoded.RegistrationDate.IsZero() {
+		return repository.PVZKey{}, errors.New("incomplete cursor")
+	}
+	if _, err := uuid.Parse(decoded.ID); err != nil {
+		return repository.PVZKey{}, err
+	}
+
+	return repository.PVZKey{RegistrationDate: decoded.RegistrationDate, ID: decoded.ID}, nil
 }
@@ -1,6 +1,10 @@
 package service
 
 import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
 	"testing"
 	"time"
 
@@ -16,24 +20,39 @@ type MockPVZRepo struct {
 	mock.Mock
 }
 
-func (m *MockPVZRepo) CreatePVZ(city string, idGenerator func() uuid.UUID) (domain.PVZ, error) {
+func (m *MockPVZRepo) CreatePVZ(ctx context.Context, city string, idGenerator func() uuid.UUID) (domain.PVZ, error) {
 	args := m.Called(city, idGenerator)
 	return args.Get(0).(domain.PVZ), args.Error(1)
 }
 
-func (m *MockPVZRepo) GetPVZByID(id string) (domain.PVZ, error) {
+func (m *MockPVZRepo) GetPVZByID(ctx context.Context, id string) (domain.PVZ, error) {
 	args := m.Called(id)
 	return args.Get(0).(domain.PVZ), args.Error(1)
 }
 
-func (m *MockPVZRepo) ListPVZsWithRelations(startDate, endDate time.Time, limit, offset int) ([]repository.PVZResponse, error) {
-	args := m.Called(startDate, endDate, limit, offset)
+func (m *MockPVZRepo) ListPVZsWithRelations(
+	ctx context.Context, filter repository.PVZFilter, sort repository.PVZSort, limit, offset int,
+) ([]repository.PVZResponse, int, error) {
+	args := m.Called(filter, sort, limit, offset)
+	return args.Get(0).([]repository.PVZResponse), args.Int(1), args.Error(2)
+}
+
+func (m *MockPVZRepo) ListPVZsWithRelationsAfter(
+	ctx context.Context, filter repository.PVZFilter, after *repository.PVZKey, limit int,
+) ([]repository.PVZResponse, error) {
+	args := m.Called(filter, after, limit)
 	return args.Get(0).([]repository.PVZResponse), args.Error(1)
 }
 
+func (m *MockPVZRepo) ListPVZs(ctx context.Context) ([]domain.PVZ, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.PVZ), args.Error(1)
+}
+
 func TestPVZProcessor_CreatePVZ(t *testing.T) {
 	mockRepo := new(MockPVZRepo)
-	processor := NewPVZService(mockRepo)
+	mockCityRepo := new(MockCityRepo)
+	processor := NewPVZService(mockRepo, mockCityRepo)
 
 	t.Run("success", func(t *testing.T) {
 		expectedPVZ := domain.PVZ{
@@ -41,10 +60,11 @@ func TestPVZProcessor_CreatePVZ(t *testing.T) {
 			City: "Москва",
 		}
 
+		mockCityRepo.On("GetCityByName", "Москва").Return(domain.City{Name: "Москва", IsActive: true}, nil)
 		mockRepo.On("CreatePVZ", "Москва", mock.AnythingOfType("func() uuid.UUID")).
 			Return(expectedPVZ, nil)
 
-		pvz, err := processor.CreatePVZ("Москва")
+		pvz, err := processor.CreatePVZ(context.Background(), "Москва")
 
 		assert.NoError(t, err)
 		assert.Equal(t, "Москва", pvz.City)
@@ -52,15 +72,37 @@ func TestPVZProcessor_CreatePVZ(t *testing.T) {
 	})
 
 	t.Run("invalid city", func(t *testing.T) {
-		_, err := processor.CreatePVZ("Нью-Йорк")
+		mockCityRepo.On("GetCityByName", "Нью-Йорк").Return(domain.City{}, sql.ErrNoRows)
+
+		_, err := processor.CreatePVZ(context.Background(), "Нью-Йорк")
 		assert.Error(t, err)
 		assert.Equal(t, "invalid city", err.Error())
 	})
+
+	t.Run("inactive city", func(t *testing.T) {
+		mockCityRepo.On("GetCityByName", "Казань").Return(domain.City{Name: "Казань", IsActive: false}, nil)
+
+		_, err := processor.CreatePVZ(context.Background(), "Казань")
+		assert.Error(t, err)
+		assert.Equal(t, "invalid city", err.Error())
+		mockRepo.AssertNotCalled(t, "CreatePVZ", "Казань", mock.Anything)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockCityRepo.On("GetCityByName", "Санкт-Петербург").
+			Return(domain.City{Name: "Санкт-Петербург", IsActive: true}, nil)
+		mockRepo.On("CreatePVZ", "Санкт-Петербург", mock.AnythingOfType("func() uuid.UUID")).
+			Return(domain.PVZ{}, errors.New("pq: connection reset by peer"))
+
+		_, err := processor.CreatePVZ(context.Background(), "Санкт-Петербург")
+		assert.EqualError(t, err, "failed to create pvz")
+		assert.ErrorIs(t, err, domain.ErrInternal)
+	})
 }
 
 func TestPVZProcessor_GetPVZByID(t *testing.T) {
 	mockRepo := new(MockPVZRepo)
-	processor := NewPVZService(mockRepo)
+	processor := NewPVZService(mockRepo, new(MockCityRepo))
 
 	t.Run("success", func(t *testing.T) {
 		expectedPVZ := domain.PVZ{
@@ -70,17 +112,25 @@ func TestPVZProcessor_GetPVZByID(t *testing.T) {
 
 		mockRepo.On("GetPVZByID", "test-id").Return(expectedPVZ, nil)
 
-		pvz, err := processor.GetPVZByID("test-id")
+		pvz, err := processor.GetPVZByID(context.Background(), "test-id")
 
 		assert.NoError(t, err)
 		assert.Equal(t, "Москва", pvz.City)
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockRepo.On("GetPVZByID", "missing").Return(domain.PVZ{}, sql.ErrNoRows)
+
+		_, err := processor.GetPVZByID(context.Background(), "missing")
+
+		assert.ErrorIs(t, err, domain.ErrPVZNotFound)
+	})
 }
 
 func TestPVZProcessor_ListPVZsWithRelations(t *testing.T) {
 	mockRepo := new(MockPVZRepo)
-	processor := NewPVZService(mockRepo)
+	processor := NewPVZService(mockRepo, new(MockCityRepo))
 
 	t.Run("success", func(t *testing.T) {
 		expected := []repository.PVZResponse{
@@ -92,23 +142,147 @@ func TestPVZProcessor_ListPVZsWithRelations(t *testing.T) {
 			},
 		}
 
-		mockRepo.On("ListPVZsWithRelations", time.Time{}, time.Time{}, 10, 0).
-			Return(expected, nil)
+		mockRepo.On("ListPVZsWithRelations", repository.PVZFilter{}, repository.PVZSort{}, 10, 0).
+			Return(expected, 21, nil)
 
-		result, err := processor.ListPVZsWithRelations("", "", 1, 10)
+		result, err := processor.ListPVZsWithRelations(
+			context.Background(), repository.PVZFilter{}, repository.PVZSort{}, 1, 10)
 
 		assert.NoError(t, err)
-		assert.Len(t, result, 1)
+		assert.Len(t, result.Items, 1)
+		assert.Equal(t, 21, result.Total)
+		assert.Equal(t, 1, result.Page)
+		assert.Equal(t, 10, result.Limit)
+		assert.Equal(t, 3, result.TotalPages)
 		mockRepo.AssertExpectations(t)
 	})
 
-	t.Run("invalid date format", func(t *testing.T) {
-		_, err := processor.ListPVZsWithRelations("invalid", "", 1, 10)
-		assert.Error(t, err)
+	t.Run("offset from page", func(t *testing.T) {
+		filter := repository.PVZFilter{
+			StartDate: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2025, 4, 20, 0, 0, 0, 0, time.UTC),
+			Cities:    []string{"Москва"},
+		}
+		sort := repository.PVZSort{Field: repository.PVZSortLastReception, Desc: true}
+		mockRepo.On("ListPVZsWithRelations", filter, sort, 5, 10).
+			Return([]repository.PVZResponse{}, 0, nil)
+
+		result, err := processor.ListPVZsWithRelations(context.Background(), filter, sort, 3, 5)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Items)
+		assert.Equal(t, 0, result.TotalPages)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("start after end", func(t *testing.T) {
+		filter := repository.PVZFilter{
+			StartDate: time.Date(2025, 4, 20, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+		}
+		_, err := processor.ListPVZsWithRelations(context.Background(), filter, repository.PVZSort{}, 1, 10)
+		assert.EqualError(t, err, "start date must not be after end date")
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo.On("ListPVZsWithRelations", repository.PVZFilter{}, repository.PVZSort{}, 10, 10).
+			Return([]repository.PVZResponse(nil), 0, sql.ErrConnDone)
+
+		_, err := processor.ListPVZsWithRelations(
+			context.Background(), repository.PVZFilter{}, repository.PVZSort{}, 2, 10)
+		assert.EqualError(t, err, "database error")
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		minProducts, maxProducts := 10, 1
+		filters := []repository.PVZFilter{
+			{ReceptionStatus: "open"},
+			{MinProducts: &minProducts, MaxProducts: &maxProducts},
+		}
+		for _, filter := range filters {
+			_, err := processor.ListPVZsWithRelations(context.Background(), filter, repository.PVZSort{}, 1, 10)
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("invalid sort field", func(t *testing.T) {
+		_, err := processor.ListPVZsWithRelations(
+			context.Background(), repository.PVZFilter{}, repository.PVZSort{Field: "city"}, 1, 10)
+		assert.EqualError(t, err, "invalid sort field")
 	})
 
 	t.Run("invalid pagination", func(t *testing.T) {
-		_, err := processor.ListPVZsWithRelations("", "", 0, 10)
+		_, err := processor.ListPVZsWithRelations(
+			context.Background(), repository.PVZFilter{}, repository.PVZSort{}, 0, 10)
 		assert.Error(t, err)
 	})
 }
+
+func TestPVZProcessor_ListPVZsByCursor(t *testing.T) {
+	regDate := time.Date(2025, 4, 1, 10, 0, 0, 123000, time.UTC)
+	pvz2 := uuid.NewString()
+	page := []repository.PVZResponse{
+		{PVZ: domain.PVZ{ID: uuid.NewString(), RegistrationDate: regDate}},
+		{PVZ: domain.PVZ{ID: pvz2, RegistrationDate: regDate}},
+		{PVZ: domain.PVZ{ID: uuid.NewString(), RegistrationDate: regDate.Add(time.Second)}},
+	}
+
+	t.Run("first page returns next cursor", func(t *testing.T) {
+		mockRepo := new(MockPVZRepo)
+		processor := NewPVZService(mockRepo, new(MockCityRepo))
+		mockRepo.On("ListPVZsWithRelationsAfter", repository.PVZFilter{}, (*repository.PVZKey)(nil), 3).
+			Return(page, nil)
+
+		result, err := processor.ListPVZsByCursor(context.Background(), repository.PVZFilter{}, "", 2)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Items, 2)
+		assert.NotEmpty(t, result.NextCursor)
+
+		key, err := decodePVZCursor(result.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, pvz2, key.ID)
+		assert.True(t, regDate.Equal(key.RegistrationDate))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("cursor is passed as key", func(t *testing.T) {
+		mockRepo := new(MockPVZRepo)
+		processor := NewPVZService(mockRepo, new(MockCityRepo))
+		cursor := encodePVZCursor(repository.PVZKey{RegistrationDate: regDate, ID: pvz2})
+		mockRepo.On("ListPVZsWithRelationsAfter", repository.PVZFilter{},
+			mock.MatchedBy(func(key *repository.PVZKey) bool {
+				return key != nil && key.ID == pvz2 && key.RegistrationDate.Equal(regDate)
+			}), 3).
+			Return(page[2:], nil)
+
+		result, err := processor.ListPVZsByCursor(context.Background(), repository.PVZFilter{}, cursor, 2)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Items, 1)
+		assert.Empty(t, result.NextCursor)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		processor := NewPVZService(new(MockPVZRepo), new(MockCityRepo))
+
+		_, err := processor.ListPVZsByCursor(context.Background(), repository.PVZFilter{}, "not-a-cursor!", 10)
+		assert.EqualError(t, err, "invalid cursor")
+	})
+
+	t.Run("cursor with malformed id", func(t *testing.T) {
+		processor := NewPVZService(new(MockPVZRepo), new(MockCityRepo))
+		cursor := base64.RawURLEncoding.EncodeToString([]byte(`{"d":"2024-01-01T00:00:00Z","id":"x"}`))
+
+		_, err := processor.ListPVZsByCursor(context.Background(), repository.PVZFilter{}, cursor, 10)
+		assert.EqualError(t, err, "invalid cursor")
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		processor := NewPVZService(new(MockPVZRepo), new(MockCityRepo))
+
+		_, err := processor.ListPVZsByCursor(context.Background(), repository.PVZFilter{}, "", MaxCursorPageLimit+1)
+		assert.EqualError(t, err, "invalid limit")
+	})
+}
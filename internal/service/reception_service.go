@@ -1,60 +1,247 @@
 package service
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"github.com/google/uuid"
 	"time"
 
 	"pvz-service/internal/domain"
+	"pvz-service/internal/events"
 	"pvz-service/internal/repository"
 )
 
 type ReceptionService interface {
-	CreateReception(pvzID string) (domain.Reception, error)
-	CloseLastReception(pvzID string) (domain.Reception, error)
+	CreateReception(ctx context.Context, pvzID string) (domain.Reception, error)
+	CloseLastReception(ctx context.Context, pvzID string) (domain.Reception, error)
+	GetReceptionByID(ctx context.Context, id string) (domain.Reception, error)
+	ListReceptionsByPVZ(ctx context.Context, pvzID string, filter repository.ReceptionFilter,
+		page, limit int) (repository.ReceptionListResponse, error)
+	ListReceptionProducts(
+		ctx context.Context, receptionID string, page, limit int) (repository.ProductListResponse, error)
+	GetReceptionWithProducts(ctx context.Context, id string) (repository.ReceptionResponse, error)
+	ListReceptionsWithProducts(ctx context.Context, pvzID string, filter repository.ReceptionFilter,
+		page, limit int) (repository.ReceptionWithProductsListResponse, error)
 }
 
 type ReceptionServiceImpl struct {
 	receptionRepo repository.ReceptionRepository
+	productRepo   repository.ProductRepository
+	pvzRepo       repository.PVZRepository
+	txManager     repository.TxManager
+	events        events.Publisher
 }
 
-func NewReceptionService(receptionRepo repository.ReceptionRepository) *ReceptionServiceImpl {
-	return &ReceptionServiceImpl{receptionRepo: receptionRepo}
+func NewReceptionService(
+	receptionRepo repository.ReceptionRepository,
+	productRepo repository.ProductRepository,
+	pvzRepo repository.PVZRepository,
+	txManager repository.TxManager,
+	publisher events.Publisher,
+) *ReceptionServiceImpl {
+	return &ReceptionServiceImpl{
+		receptionRepo: receptionRepo,
+		productRepo:   productRepo,
+		pvzRepo:       pvzRepo,
+		txManager:     txManager,
+		events:        publisher,
+	}
 }
 
-func (p *ReceptionServiceImpl) CreateReception(pvzID string) (domain.Reception, error) {
-	hasOpen, err := p.receptionRepo.HasOpenReception(pvzID)
+func (p *ReceptionServiceImpl) CreateReception(ctx context.Context, pvzID string) (domain.Reception, error) {
+	receptionID, err := p.receptionRepo.CreateReception(ctx, pvzID, uuid.New)
 	if err != nil {
-		return domain.Reception{}, errors.New("database error")
+		if errors.Is(err, domain.ErrOpenReceptionExists) || errors.Is(err, domain.ErrPVZNotFound) {
+			return domain.Reception{}, err
+		}
+		return domain.Reception{}, internalError(ctx, "failed to create reception", err)
 	}
-	if hasOpen {
-		return domain.Reception{}, errors.New("open reception already exists for this PVZ")
+
+	publish(p.events, events.Event{Type: events.ReceptionOpened, PVZID: pvzID, ReceptionID: receptionID})
+	return p.receptionRepo.GetReceptionByID(ctx, receptionID)
+}
+
+func (p *ReceptionServiceImpl) CloseLastReception(ctx context.Context, pvzID string) (domain.Reception, error) {
+	var reception domain.Reception
+	err := withinTransaction(ctx, p.txManager, "failed to close reception", func(uow repository.UnitOfWork) error {
+		var err error
+		reception, err = uow.Receptions().GetOpenReceptionForUpdate(ctx, pvzID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrNoOpenReception
+			}
+			return internalError(ctx, "database error", err)
+		}
+
+		now := time.Now()
+		if err := uow.Receptions().CloseReception(ctx, reception.ID, now); err != nil {
+			return internalError(ctx, "failed to close reception", err)
+		}
+
+		reception.Status = "close"
+		reception.ClosedAt = &now
+		return nil
+	})
+	if err != nil {
+		return domain.Reception{}, err
 	}
 
-	receptionID, err := p.receptionRepo.CreateReception(pvzID, uuid.New)
+	publish(p.events, events.Event{Type: events.ReceptionClosed, PVZID: pvzID, ReceptionID: reception.ID})
+	return reception, nil
+}
+
+func (p *ReceptionServiceImpl) GetReceptionByID(ctx context.Context, id string) (domain.Reception, error) {
+	reception, err := p.receptionRepo.GetReceptionByID(ctx, id)
 	if err != nil {
-		return domain.Reception{}, errors.New("failed to create reception")
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Reception{}, domain.ErrReceptionNotFound
+		}
+		return domain.Reception{}, internalError(ctx, "database error", err)
 	}
 
-	return p.receptionRepo.GetReceptionByID(receptionID)
+	return reception, nil
 }
 
-func (p *ReceptionServiceImpl) CloseLastReception(pvzID string) (domain.Reception, error) {
-	reception, err := p.receptionRepo.GetOpenReception(pvzID)
+func (p *ReceptionServiceImpl) ListReceptionsByPVZ(ctx context.Context, pvzID string,
+	filter repository.ReceptionFilter, page, limit int) (repository.ReceptionListResponse, error) {
+	offset, err := pageOffset(page, limit)
 	if err != nil {
+		return repository.ReceptionListResponse{}, err
+	}
+
+	switch filter.Status {
+	case "", "in_progress", "close":
+	default:
+		return repository.ReceptionListResponse{}, errors.New("invalid reception status")
+	}
+
+	if !filter.StartDate.IsZero() && !filter.EndDate.IsZero() && filter.StartDate.After(filter.EndDate) {
+		return repository.ReceptionListResponse{}, errors.New("start date must not be after end date")
+	}
+
+	if _, err := p.pvzRepo.GetPVZByID(ctx, pvzID); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return domain.Reception{}, errors.New("no open reception found for this PVZ")
+			return repository.ReceptionListResponse{}, domain.ErrPVZNotFound
 		}
-		return domain.Reception{}, errors.New("database error")
+		return repository.ReceptionListResponse{}, internalError(ctx, "database error", err)
 	}
 
-	now := time.Now()
-	if err := p.receptionRepo.CloseReception(reception.ID, now); err != nil {
-		return domain.Reception{}, errors.New("failed to close reception")
+	items, total, err := p.receptionRepo.ListReceptionsByPVZ(ctx, pvzID, filter, limit, offset)
+	if err != nil {
+		return repository.ReceptionListResponse{}, internalError(ctx, "database error", err)
 	}
 
-	reception.Status = "close"
-	reception.ClosedAt = &now
-	return reception, nil
+	return repository.ReceptionListResponse{
+		Items:      items,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (total + limit - 1) / limit,
+	}, nil
+}
+
+func (p *ReceptionServiceImpl) ListReceptionProducts(
+	ctx context.Context, receptionID string, page, limit int) (repository.ProductListResponse, error) {
+	offset, err := pageOffset(page, limit)
+	if err != nil {
+		return repository.ProductListResponse{}, err
+	}
+
+	if _, err := p.GetReceptionByID(ctx, receptionID); err != nil {
+		return repository.ProductListResponse{}, err
+	}
+
+	items, total, err := p.productRepo.ListProductsByReception(ctx, receptionID, limit, offset)
+	if err != nil {
+		return repository.ProductListResponse{}, internalError(ctx, "database error", err)
+	}
+
+	return repository.ProductListResponse{
+		Items:      items,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (total + limit - 1) / limit,
+	}, nil
+}
+
+func (p *ReceptionServiceImpl) GetReceptionWithProducts(
+	ctx context.Context, id string) (repository.ReceptionResponse, error) {
+	reception, err := p.GetReceptionByID(ctx, id)
+	if err != nil {
+		return repository.ReceptionResponse{}, err
+	}
+
+	products, err := p.productRepo.ListProductsByReceptions(ctx, []string{id})
+	if err != nil {
+		return repository.ReceptionResponse{}, internalError(ctx, "database error", err)
+	}
+
+	items := products[id]
+	if items == nil {
+		items = []domain.Product{}
+	}
+	return repository.ReceptionResponse{Reception: reception, Products: items}, nil
+}
+
+// ListReceptionsWithProducts pages over a PVZ's receptions like ListReceptionsByPVZ
+// and attaches all products of each reception on the page.
+func (p *ReceptionServiceImpl) ListReceptionsWithProducts(ctx context.Context, pvzID string,
+	filter repository.ReceptionFilter, page, limit int) (repository.ReceptionWithProductsListResponse, error) {
+	receptions, err := p.ListReceptionsByPVZ(ctx, pvzID, filter, page, limit)
+	if err != nil {
+		return repository.ReceptionWithProductsListResponse{}, err
+	}
+
+	result := repository.ReceptionWithProductsListResponse{
+		Items:      make([]repository.ReceptionResponse, 0, len(receptions.Items)),
+		Total:      receptions.Total,
+		Page:       receptions.Page,
+		Limit:      receptions.Limit,
+		TotalPages: receptions.TotalPages,
+	}
+	if len(receptions.Items) == 0 {
+		return result, nil
+	}
+
+	ids := make([]string, 0, len(receptions.Items))
+	for _, reception := range receptions.Items {
+		ids = append(ids, reception.ID)
+	}
+
+	products, err := p.productRepo.ListProductsByReceptions(ctx, ids)
+	if err != nil {
+		return repository.ReceptionWithProductsListResponse{}, internalError(ctx, "database error", err)
+	}
+
+	for _, reception := range receptions.Items {
+		items := products[reception.ID]
+		if items == nil {
+			items = []domain.Product{}
+		}
+		result.Items = append(result.Items, repository.ReceptionResponse{Reception: reception, Products: items})
+	}
+
+	return result, nil
+}
+
+// pageOffset validates page/limit the same way as the PVZ listing and returns the row offset.
+func pageOffset(page, limit int) (int, error) {
+	if page < 1 {
+		return 0, errors.New("invalid page number")
+	}
+
+	if limit < 1 || limit > 30 {
+		return 0, errors.New("invalid limit")
+	}
+
+	return (page - 1) * limit, nil
+}
+
+// publish tolerates a nil publisher so services can be built without an event broker.
+func publish(publisher events.Publisher, event events.Event) {
+	if publisher != nil {
+		publisher.Publish(event)
+	}
 }
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"testing"
@@ -10,40 +11,51 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"pvz-service/internal/domain"
+	"pvz-service/internal/events"
+	"pvz-service/internal/repository"
 )
 
 type MockReceptionRepository struct {
 	mock.Mock
 }
 
-func (m *MockReceptionRepository) CreateReception(pvzID string, idGenerator func() uuid.UUID) (string, error) {
+func (m *MockReceptionRepository) CreateReception(ctx context.Context, pvzID string, idGenerator func() uuid.UUID) (string, error) {
 	args := m.Called(pvzID, idGenerator)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockReceptionRepository) GetReceptionByID(id string) (domain.Reception, error) {
+func (m *MockReceptionRepository) GetReceptionByID(ctx context.Context, id string) (domain.Reception, error) {
 	args := m.Called(id)
 	return args.Get(0).(domain.Reception), args.Error(1)
 }
 
-func (m *MockReceptionRepository) GetOpenReception(pvzID string) (domain.Reception, error) {
+func (m *MockReceptionRepository) GetOpenReception(ctx context.Context, pvzID string) (domain.Reception, error) {
 	args := m.Called(pvzID)
 	return args.Get(0).(domain.Reception), args.Error(1)
 }
 
-func (m *MockReceptionRepository) CloseReception(id string, closeTime time.Time) error {
+func (m *MockReceptionRepository) GetOpenReceptionForUpdate(ctx context.Context, pvzID string) (domain.Reception, error) {
+	args := m.Called(pvzID)
+	return args.Get(0).(domain.Reception), args.Error(1)
+}
+
+func (m *MockReceptionRepository) CloseReception(ctx context.Context, id string, closeTime time.Time) error {
 	args := m.Called(id, closeTime)
 	return args.Error(0)
 }
 
-func (m *MockReceptionRepository) HasOpenReception(pvzID string) (bool, error) {
-	args := m.Called(pvzID)
-	return args.Bool(0), args.Error(1)
+func (m *MockReceptionRepository) ListReceptionsByPVZ(ctx context.Context, pvzID string,
+	filter repository.ReceptionFilter, limit, offset int) ([]domain.Reception, int, error) {
+	args := m.Called(pvzID, filter, limit, offset)
+	return args.Get(0).([]domain.Reception), args.Int(1), args.Error(2)
 }
 
 func TestReceptionProcessor_CreateReception(t *testing.T) {
 	mockRepo := new(MockReceptionRepository)
-	processor := NewReceptionService(mockRepo)
+	publisher := &recordingPublisher{}
+	processor := NewReceptionService(
+		mockRepo, new(MockProductRepo), new(MockPVZRepo), &MockTxManager{uow: &MockUnitOfWork{receptionRepo: mockRepo}},
+		publisher)
 
 	t.Run("success", func(t *testing.T) {
 		pvzID := uuid.New().String()
@@ -55,49 +67,57 @@ func TestReceptionProcessor_CreateReception(t *testing.T) {
 			DateTime: time.Now(),
 		}
 
-		mockRepo.On("HasOpenReception", pvzID).Return(false, nil)
 		mockRepo.On("CreateReception", pvzID, mock.AnythingOfType("func() uuid.UUID")).Return(receptionID, nil)
 		mockRepo.On("GetReceptionByID", receptionID).Return(expectedReception, nil)
 
-		result, err := processor.CreateReception(pvzID)
+		result, err := processor.CreateReception(context.Background(), pvzID)
 		assert.NoError(t, err)
 		assert.Equal(t, expectedReception, result)
+		assert.Equal(t, []events.Event{
+			{Type: events.ReceptionOpened, PVZID: pvzID, ReceptionID: receptionID},
+		}, publisher.events)
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("has open reception", func(t *testing.T) {
 		pvzID := uuid.New().String()
-		mockRepo.On("HasOpenReception", pvzID).Return(true, nil)
+		mockRepo.On("CreateReception", pvzID, mock.AnythingOfType("func() uuid.UUID")).Return(
+			"", domain.ErrOpenReceptionExists)
 
-		_, err := processor.CreateReception(pvzID)
+		_, err := processor.CreateReception(context.Background(), pvzID)
+		assert.ErrorIs(t, err, domain.ErrOpenReceptionExists)
 		assert.EqualError(t, err, "open reception already exists for this PVZ")
 		mockRepo.AssertExpectations(t)
 	})
 
-	t.Run("repository error on has open", func(t *testing.T) {
+	t.Run("pvz not found", func(t *testing.T) {
 		pvzID := uuid.New().String()
-		mockRepo.On("HasOpenReception", pvzID).Return(false, errors.New("db error"))
+		mockRepo.On("CreateReception", pvzID, mock.AnythingOfType("func() uuid.UUID")).Return(
+			"", domain.ErrPVZNotFound)
 
-		_, err := processor.CreateReception(pvzID)
-		assert.EqualError(t, err, "database error")
+		_, err := processor.CreateReception(context.Background(), pvzID)
+		assert.ErrorIs(t, err, domain.ErrPVZNotFound)
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("repository error on create", func(t *testing.T) {
 		pvzID := uuid.New().String()
-		mockRepo.On("HasOpenReception", pvzID).Return(false, nil)
 		mockRepo.On("CreateReception", pvzID, mock.AnythingOfType("func() uuid.UUID")).Return(
 			"", errors.New("db error"))
 
-		_, err := processor.CreateReception(pvzID)
+		_, err := processor.CreateReception(context.Background(), pvzID)
 		assert.EqualError(t, err, "failed to create reception")
+		assert.ErrorIs(t, err, domain.ErrInternal)
 		mockRepo.AssertExpectations(t)
 	})
 }
 
 func TestReceptionProcessor_CloseLastReception(t *testing.T) {
 	mockRepo := new(MockReceptionRepository)
-	processor := NewReceptionService(mockRepo)
+	publisher := &recordingPublisher{}
+	processor := NewReceptionService(
+		mockRepo, new(MockProductRepo), new(MockPVZRepo), &MockTxManager{uow: &MockUnitOfWork{receptionRepo: mockRepo}},
+		publisher)
 
 	t.Run("success", func(t *testing.T) {
 		pvzID := uuid.New().String()
@@ -113,10 +133,10 @@ func TestReceptionProcessor_CloseLastReception(t *testing.T) {
 		expectedReception.Status = "close"
 		expectedReception.ClosedAt = &now
 
-		mockRepo.On("GetOpenReception", pvzID).Return(openReception, nil)
+		mockRepo.On("GetOpenReceptionForUpdate", pvzID).Return(openReception, nil)
 		mockRepo.On("CloseReception", receptionID, mock.AnythingOfType("time.Time")).Return(nil)
 
-		result, err := processor.CloseLastReception(pvzID)
+		result, err := processor.CloseLastReception(context.Background(), pvzID)
 		assert.NoError(t, err)
 		assert.Equal(t, expectedReception.Status, result.Status)
 		assert.NotNil(t, result.ClosedAt)
@@ -125,18 +145,18 @@ func TestReceptionProcessor_CloseLastReception(t *testing.T) {
 
 	t.Run("no open reception", func(t *testing.T) {
 		pvzID := uuid.New().String()
-		mockRepo.On("GetOpenReception", pvzID).Return(domain.Reception{}, sql.ErrNoRows)
+		mockRepo.On("GetOpenReceptionForUpdate", pvzID).Return(domain.Reception{}, sql.ErrNoRows)
 
-		_, err := processor.CloseLastReception(pvzID)
-		assert.EqualError(t, err, "no open reception found for this PVZ")
+		_, err := processor.CloseLastReception(context.Background(), pvzID)
+		assert.ErrorIs(t, err, domain.ErrNoOpenReception)
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("repository error on get open", func(t *testing.T) {
 		pvzID := uuid.New().String()
-		mockRepo.On("GetOpenReception", pvzID).Return(domain.Reception{}, errors.New("db error"))
+		mockRepo.On("GetOpenReceptionForUpdate", pvzID).Return(domain.Reception{}, errors.New("db error"))
 
-		_, err := processor.CloseLastReception(pvzID)
+		_, err := processor.CloseLastReception(context.Background(), pvzID)
 		assert.EqualError(t, err, "database error")
 		mockRepo.AssertExpectations(t)
 	})
@@ -151,11 +171,233 @@ func TestReceptionProcessor_CloseLastReception(t *testing.T) {
 			DateTime: time.Now(),
 		}
 
-		mockRepo.On("GetOpenReception", pvzID).Return(openReception, nil)
+		mockRepo.On("GetOpenReceptionForUpdate", pvzID).Return(openReception, nil)
 		mockRepo.On("CloseReception", receptionID, mock.AnythingOfType("time.Time")).Return(errors.New("db error"))
 
-		_, err := processor.CloseLastReception(pvzID)
+		_, err := processor.CloseLastReception(context.Background(), pvzID)
 		assert.EqualError(t, err, "failed to close reception")
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestReceptionProcessor_GetReceptionByID(t *testing.T) {
+	mockRepo := new(MockReceptionRepository)
+	processor := NewReceptionService(mockRepo, new(MockProductRepo), new(MockPVZRepo), &MockTxManager{}, nil)
+
+	t.Run("success", func(t *testing.T) {
+		expected := domain.Reception{ID: "rec1", PvzId: "pvz1", Status: "in_progress"}
+		mockRepo.On("GetReceptionByID", "rec1").Return(expected, nil).Once()
+
+		reception, err := processor.GetReceptionByID(context.Background(), "rec1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, reception)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockRepo.On("GetReceptionByID", "missing").Return(domain.Reception{}, sql.ErrNoRows).Once()
+
+		_, err := processor.GetReceptionByID(context.Background(), "missing")
+
+		assert.ErrorIs(t, err, domain.ErrReceptionNotFound)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mockRepo.On("GetReceptionByID", "broken").Return(domain.Reception{}, sql.ErrConnDone).Once()
+
+		_, err := processor.GetReceptionByID(context.Background(), "broken")
+
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+func TestReceptionProcessor_ListReceptionsByPVZ(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(MockReceptionRepository)
+		mockPVZRepo := new(MockPVZRepo)
+		processor := NewReceptionService(mockRepo, new(MockProductRepo), mockPVZRepo, &MockTxManager{}, nil)
+		filter := repository.ReceptionFilter{Status: "close"}
+
+		mockPVZRepo.On("GetPVZByID", "pvz1").Return(domain.PVZ{ID: "pvz1"}, nil)
+		mockRepo.On("ListReceptionsByPVZ", "pvz1", filter, 10, 10).
+			Return([]domain.Reception{{ID: "rec1"}}, 11, nil)
+
+		result, err := processor.ListReceptionsByPVZ(context.Background(), "pvz1", filter, 2, 10)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Items, 1)
+		assert.Equal(t, 11, result.Total)
+		assert.Equal(t, 2, result.Page)
+		assert.Equal(t, 2, result.TotalPages)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("pvz not found", func(t *testing.T) {
+		mockPVZRepo := new(MockPVZRepo)
+		processor := NewReceptionService(
+			new(MockReceptionRepository), new(MockProductRepo), mockPVZRepo, &MockTxManager{}, nil)
+		mockPVZRepo.On("GetPVZByID", "pvz1").Return(domain.PVZ{}, sql.ErrNoRows)
+
+		_, err := processor.ListReceptionsByPVZ(
+			context.Background(), "pvz1", repository.ReceptionFilter{}, 1, 10)
+
+		assert.ErrorIs(t, err, domain.ErrPVZNotFound)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		processor := NewReceptionService(
+			new(MockReceptionRepository), new(MockProductRepo), new(MockPVZRepo), &MockTxManager{}, nil)
+
+		_, err := processor.ListReceptionsByPVZ(
+			context.Background(), "pvz1", repository.ReceptionFilter{Status: "open"}, 1, 10)
+		assert.EqualError(t, err, "invalid reception status")
+
+		_, err = processor.ListReceptionsByPVZ(
+			context.Background(), "pvz1", repository.ReceptionFilter{}, 1, 31)
+		assert.EqualError(t, err, "invalid limit")
+
+		_, err = processor.ListReceptionsByPVZ(context.Background(), "pvz1", repository.ReceptionFilter{
+			StartDate: time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+		}, 1, 10)
+		assert.EqualError(t, err, "start date must not be after end date")
+	})
+}
+
+func TestReceptionProcessor_ListReceptionProducts(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(MockReceptionRepository)
+		mockProductRepo := new(MockProductRepo)
+		processor := NewReceptionService(mockRepo, mockProductRepo, new(MockPVZRepo), &MockTxManager{}, nil)
+
+		mockRepo.On("GetReceptionByID", "rec1").Return(domain.Reception{ID: "rec1"}, nil)
+		mockProductRepo.On("ListProductsByReception", "rec1", 5, 0).
+			Return([]domain.Product{{ID: "prod1"}, {ID: "prod2"}}, 2, nil)
+
+		result, err := processor.ListReceptionProducts(context.Background(), "rec1", 1, 5)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Items, 2)
+		assert.Equal(t, 1, result.TotalPages)
+		mockProductRepo.AssertExpectations(t)
+	})
+
+	t.Run("reception not found", func(t *testing.T) {
+		mockRepo := new(MockReceptionRepository)
+		mockProductRepo := new(MockProductRepo)
+		processor := NewReceptionService(mockRepo, mockProductRepo, new(MockPVZRepo), &MockTxManager{}, nil)
+
+		mockRepo.On("GetReceptionByID", "rec1").Return(domain.Reception{}, sql.ErrNoRows)
+
+		_, err := processor.ListReceptionProducts(context.Background(), "rec1", 1, 5)
+
+		assert.ErrorIs(t, err, domain.ErrReceptionNotFound)
+		mockProductRepo.AssertNotCalled(t, "ListProductsByReception", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestReceptionProcessor_GetReceptionWithProducts(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(MockReceptionRepository)
+		mockProductRepo := new(MockProductRepo)
+		processor := NewReceptionService(mockRepo, mockProductRepo, new(MockPVZRepo), &MockTxManager{}, nil)
+
+		mockRepo.On("GetReceptionByID", "rec1").Return(domain.Reception{ID: "rec1"}, nil)
+		mockProductRepo.On("ListProductsByReceptions", []string{"rec1"}).
+			Return(map[string][]domain.Product{"rec1": {{ID: "prod1"}}}, nil)
+
+		result, err := processor.GetReceptionWithProducts(context.Background(), "rec1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "rec1", result.Reception.ID)
+		assert.Equal(t, []domain.Product{{ID: "prod1"}}, result.Products)
+	})
+
+	t.Run("no products", func(t *testing.T) {
+		mockRepo := new(MockReceptionRepository)
+		mockProductRepo := new(MockProductRepo)
+		processor := NewReceptionService(mockRepo, mockProductRepo, new(MockPVZRepo), &MockTxManager{}, nil)
+
+		mockRepo.On("GetReceptionByID", "rec1").Return(domain.Reception{ID: "rec1"}, nil)
+		mockProductRepo.On("ListProductsByReceptions", []string{"rec1"}).
+			Return(map[string][]domain.Product{}, nil)
+
+		result, err := processor.GetReceptionWithProducts(context.Background(), "rec1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Product{}, result.Products)
+	})
+
+	t.Run("reception not found", func(t *testing.T) {
+		mockRepo := new(MockReceptionRepository)
+		mockProductRepo := new(MockProductRepo)
+		processor := NewReceptionService(mockRepo, mockProductRepo, new(MockPVZRepo), &MockTxManager{}, nil)
+
+		mockRepo.On("GetReceptionByID", "rec1").Return(domain.Reception{}, sql.ErrNoRows)
+
+		_, err := processor.GetReceptionWithProducts(context.Background(), "rec1")
+
+		assert.ErrorIs(t, err, domain.ErrReceptionNotFound)
+		mockProductRepo.AssertNotCalled(t, "ListProductsByReceptions", mock.Anything)
+	})
+}
+
+func TestReceptionProcessor_ListReceptionsWithProducts(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(MockReceptionRepository)
+		mockProductRepo := new(MockProductRepo)
+		mockPVZRepo := new(MockPVZRepo)
+		processor := NewReceptionService(mockRepo, mockProductRepo, mockPVZRepo, &MockTxManager{}, nil)
+		filter := repository.ReceptionFilter{}
+
+		mockPVZRepo.On("GetPVZByID", "pvz1").Return(domain.PVZ{ID: "pvz1"}, nil)
+		mockRepo.On("ListReceptionsByPVZ", "pvz1", filter, 10, 0).
+			Return([]domain.Reception{{ID: "rec1"}, {ID: "rec2"}}, 2, nil)
+		mockProductRepo.On("ListProductsByReceptions", []string{"rec1", "rec2"}).
+			Return(map[string][]domain.Product{"rec2": {{ID: "prod1"}}}, nil)
+
+		result, err := processor.ListReceptionsWithProducts(context.Background(), "pvz1", filter, 1, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Total)
+		assert.Equal(t, 1, result.TotalPages)
+		assert.Len(t, result.Items, 2)
+		assert.Equal(t, []domain.Product{}, result.Items[0].Products)
+		assert.Equal(t, []domain.Product{{ID: "prod1"}}, result.Items[1].Products)
+	})
+
+	t.Run("empty page", func(t *testing.T) {
+		mockRepo := new(MockReceptionRepository)
+		mockProductRepo := new(MockProductRepo)
+		mockPVZRepo := new(MockPVZRepo)
+		processor := NewReceptionService(mockRepo, mockProductRepo, mockPVZRepo, &MockTxManager{}, nil)
+		filter := repository.ReceptionFilter{}
+
+		mockPVZRepo.On("GetPVZByID", "pvz1").Return(domain.PVZ{ID: "pvz1"}, nil)
+		mockRepo.On("ListReceptionsByPVZ", "pvz1", filter, 10, 0).Return([]domain.Reception{}, 0, nil)
+
+		result, err := processor.ListReceptionsWithProducts(context.Background(), "pvz1", filter, 1, 10)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Items)
+		mockProductRepo.AssertNotCalled(t, "ListProductsByReceptions", mock.Anything)
+	})
+
+	t.Run("products database error", func(t *testing.T) {
+		mockRepo := new(MockReceptionRepository)
+		mockProductRepo := new(MockProductRepo)
+		mockPVZRepo := new(MockPVZRepo)
+		processor := NewReceptionService(mockRepo, mockProductRepo, mockPVZRepo, &MockTxManager{}, nil)
+		filter := repository.ReceptionFilter{}
+
+		mockPVZRepo.On("GetPVZByID", "pvz1").Return(domain.PVZ{ID: "pvz1"}, nil)
+		mockRepo.On("ListReceptionsByPVZ", "pvz1", filter, 10, 0).
+			Return([]domain.Reception{{ID: "rec1"}}, 1, nil)
+		mockProductRepo.On("ListProductsByReceptions", []string{"rec1"}).
+			Return(map[string][]domain.Product(nil), errors.New("connection reset"))
+
+		_, err := processor.ListReceptionsWithProducts(context.Background(), "pvz1", filter, 1, 10)
+
+		assert.EqualError(t, err, "database error")
+	})
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/repository"
+)
+
+// UserService manages user accounts. Admins may change any other account;
+// moderators may only enable or disable employees.
+type UserService interface {
+	ListUsers(ctx context.Context, filter repository.UserFilter, page, limit int) (repository.UserListResponse, error)
+	GetUser(ctx context.Context, id string) (domain.User, error)
+	UpdateUser(ctx context.Context, actorID, actorRole, id string, role *string, disabled *bool) (domain.User, error)
+	DeleteUser(ctx context.Context, actorID, id string) error
+}
+
+type UserServiceImpl struct {
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
+}
+
+func NewUserService(userRepo repository.UserRepository, sessionRepo repository.SessionRepository) *UserServiceImpl {
+	return &UserServiceImpl{userRepo: userRepo, sessionRepo: sessionRepo}
+}
+
+func (p *UserServiceImpl) ListUsers(
+	ctx context.Context, filter repository.UserFilter, page, limit int) (repository.UserListResponse, error) {
+	offset, err := pageOffset(page, limit)
+	if err != nil {
+		return repository.UserListResponse{}, err
+	}
+
+	if filter.Role != "" && !domain.IsValidRole(filter.Role) {
+		return repository.UserListResponse{}, errors.New("invalid role")
+	}
+
+	items, total, err := p.userRepo.ListUsers(ctx, filter, limit, offset)
+	if err != nil {
+		return repository.UserListResponse{}, internalError(ctx, "database error", err)
+	}
+
+	return repository.UserListResponse{
+		Items:      items,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (total + limit - 1) / limit,
+	}, nil
+}
+
+func (p *UserServiceImpl) GetUser(ctx context.Context, id string) (domain.User, error) {
+	user, err := p.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.User{}, domain.ErrUserNotFound
+		}
+		return domain.User{}, internalError(ctx, "database error", err)
+	}
+	return user, nil
+}
+
+func (p *UserServiceImpl) UpdateUser(
+	ctx context.Context, actorID, actorRole, id string, role *string, disabled *bool) (domain.User, error) {
+	if actorID == id {
+		return domain.User{}, domain.ErrSelfModification
+	}
+
+	if role != nil && !domain.IsValidRole(*role) {
+		return domain.User{}, errors.New("invalid role")
+	}
+
+	if actorRole != domain.RoleAdmin && role != nil {
+		return domain.User{}, domain.ErrForbiddenUserEdit
+	}
+
+	var target domain.User
+	if actorRole != domain.RoleAdmin || role != nil {
+		var err error
+		if target, err = p.GetUser(ctx, id); err != nil {
+			return domain.User{}, err
+		}
+	}
+	if actorRole != domain.RoleAdmin && target.Role != domain.RoleEmployee {
+		return domain.User{}, domain.ErrForbiddenUserEdit
+	}
+
+	user, err := p.userRepo.UpdateUser(ctx, id, role, disabled)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.User{}, domain.ErrUserNotFound
+		}
+		return domain.User{}, internalError(ctx, "failed to update user", err)
+	}
+
+	// Tokens carry the role, so sessions issued for the old role must not be refreshed.
+	if role != nil && *role != target.Role {
+		if err := p.sessionRepo.RevokeUserSessions(ctx, id, time.Now()); err != nil {
+			return domain.User{}, internalError(ctx, "database error", err)
+		}
+	}
+	return user, nil
+}
+
+func (p *UserServiceImpl) DeleteUser(ctx context.Context, actorID, id string) error {
+	if actorID == id {
+		return domain.ErrSelfModification
+	}
+
+	if err := p.userRepo.DeleteUser(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+		return internalError(ctx, "failed to delete user", err)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/domain"
+	"pvz-service/internal/repository"
+)
+
+type MockUserRepo struct {
+	mock.Mock
+}
+
+func (m *MockUserRepo) ListUsers(
+	ctx context.Context, filter repository.UserFilter, limit, offset int) ([]domain.User, int, error) {
+	args := m.Called(filter, limit, offset)
+	return args.Get(0).([]domain.User), args.Int(1), args.Error(2)
+}
+
+func (m *MockUserRepo) GetUserByID(ctx context.Context, id string) (domain.User, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func (m *MockUserRepo) UpdateUser(ctx context.Context, id string, role *string, disabled *bool) (domain.User, error) {
+	args := m.Called(id, role, disabled)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func (m *MockUserRepo) DeleteUser(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func TestUserService_ListUsers(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	processor := NewUserService(mockRepo, nil)
+
+	filter := repository.UserFilter{Role: "employee"}
+	mockRepo.On("ListUsers", filter, 10, 10).Return([]domain.User{{ID: "user1"}}, 11, nil)
+
+	result, err := processor.ListUsers(context.Background(), filter, 2, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 11, result.Total)
+	assert.Equal(t, 2, result.TotalPages)
+	assert.Len(t, result.Items, 1)
+
+	_, err = processor.ListUsers(context.Background(), repository.UserFilter{Role: "root"}, 1, 10)
+	assert.EqualError(t, err, "invalid role")
+}
+
+func TestUserService_UpdateUser(t *testing.T) {
+	moderator := "moderator"
+	disabled := true
+
+	t.Run("admin changes role", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		mockSessions := new(MockSessionRepository)
+		processor := NewUserService(mockRepo, mockSessions)
+
+		mockRepo.On("GetUserByID", "user1").Return(domain.User{ID: "user1", Role: "employee"}, nil)
+		mockRepo.On("UpdateUser", "user1", &moderator, (*bool)(nil)).
+			Return(domain.User{ID: "user1", Role: "moderator"}, nil)
+		mockSessions.On("RevokeUserSessions", "user1", mock.AnythingOfType("time.Time")).Return(nil)
+
+		user, err := processor.UpdateUser(context.Background(), "admin1", "admin", "user1", &moderator, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "moderator", user.Role)
+		mockSessions.AssertExpectations(t)
+	})
+
+	t.Run("admin sets the same role", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		mockSessions := new(MockSessionRepository)
+		processor := NewUserService(mockRepo, mockSessions)
+
+		mockRepo.On("GetUserByID", "user1").Return(domain.User{ID: "user1", Role: "moderator"}, nil)
+		mockRepo.On("UpdateUser", "user1", &moderator, (*bool)(nil)).
+			Return(domain.User{ID: "user1", Role: "moderator"}, nil)
+
+		_, err := processor.UpdateUser(context.Background(), "admin1", "admin", "user1", &moderator, nil)
+
+		assert.NoError(t, err)
+		mockSessions.AssertNotCalled(t, "RevokeUserSessions", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid role", func(t *testing.T) {
+		processor := NewUserService(new(MockUserRepo), nil)
+		role := "root"
+
+		_, err := processor.UpdateUser(context.Background(), "admin1", "admin", "user1", &role, nil)
+
+		assert.EqualError(t, err, "invalid role")
+	})
+
+	t.Run("self modification", func(t *testing.T) {
+		processor := NewUserService(new(MockUserRepo), nil)
+
+		_, err := processor.UpdateUser(context.Background(), "admin1", "admin", "admin1", nil, &disabled)
+
+		assert.ErrorIs(t, err, domain.ErrSelfModification)
+	})
+
+	t.Run("moderator disables employee", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		processor := NewUserService(mockRepo, nil)
+
+		mockRepo.On("GetUserByID", "user1").Return(domain.User{ID: "user1", Role: "employee"}, nil)
+		mockRepo.On("UpdateUser", "user1", (*string)(nil), &disabled).
+			Return(domain.User{ID: "user1", Role: "employee", Disabled: true}, nil)
+
+		user, err := processor.UpdateUser(context.Background(), "mod1", "moderator", "user1", nil, &disabled)
+
+		assert.NoError(t, err)
+		assert.True(t, user.Disabled)
+	})
+
+	t.Run("moderator cannot change roles", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		processor := NewUserService(mockRepo, nil)
+
+		_, err := processor.UpdateUser(context.Background(), "mod1", "moderator", "user1", &moderator, nil)
+
+		assert.ErrorIs(t, err, domain.ErrForbiddenUserEdit)
+		mockRepo.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("moderator cannot disable moderators", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		processor := NewUserService(mockRepo, nil)
+
+		mockRepo.On("GetUserByID", "user2").Return(domain.User{ID: "user2", Role: "moderator"}, nil)
+
+		_, err := processor.UpdateUser(context.Background(), "mod1", "moderator", "user2", nil, &disabled)
+
+		assert.ErrorIs(t, err, domain.ErrForbiddenUserEdit)
+		mockRepo.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockRepo := new(MockUserRepo)
+		processor := NewUserService(mockRepo, nil)
+
+		mockRepo.On("UpdateUser", "missing", (*string)(nil), &disabled).Return(domain.User{}, sql.ErrNoRows)
+
+		_, err := processor.UpdateUser(context.Background(), "admin1", "admin", "missing", nil, &disabled)
+
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+}
+
+func TestUserService_DeleteUser(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	processor := NewUserService(mockRepo, nil)
+
+	mockRepo.On("DeleteUser", "user1").Return(nil)
+	mockRepo.On("DeleteUser", "missing").Return(sql.ErrNoRows)
+
+	assert.NoError(t, processor.DeleteUser(context.Background(), "admin1", "user1"))
+	assert.ErrorIs(t, processor.DeleteUser(context.Background(), "admin1", "missing"), domain.ErrUserNotFound)
+	assert.ErrorIs(t, processor.DeleteUser(context.Background(), "admin1", "admin1"), domain.ErrSelfModification)
+}
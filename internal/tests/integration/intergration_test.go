@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/testcontainers/testcontainers-go"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"pvz-service/internal/handler/models"
@@ -23,6 +24,12 @@ import (
 	"pvz-service/internal/config"
 	"pvz-service/internal/db"
 	"pvz-service/internal/domain"
+	"pvz-service/internal/events"
+	"pvz-service/internal/health"
+	"pvz-service/internal/jwtkeys"
+	"pvz-service/internal/migrate"
+	"pvz-service/internal/passwordpolicy"
+	"pvz-service/migrations"
 )
 
 func TestFullPVZWorkflowWithRoles(t *testing.T) {
@@ -44,35 +51,47 @@ func TestFullPVZWorkflowWithRoles(t *testing.T) {
 	applyMigrations(t, testDB)
 
 	testCfg := config.Config{
+		Env:       config.EnvTest,
 		JWTSecret: "test-secret",
 	}
 
-	testApp := app.MakeApp(testDB, testCfg)
+	testApp := app.MakeApp(testDB, testCfg, events.NewBroker(0, 0), jwtkeys.NewHMACKeySet(testCfg.JWTSecret),
+		passwordpolicy.New(8, 2, nil), health.NewChecker(time.Second), slog.Default())
 
 	// 1. Создание нового ПВЗ (требуется роль moderator)
 	pvzID := createPVZAsModerator(t, testApp, testCfg)
 	assert.NotEmpty(t, pvzID)
 
-	// 2. Добавление приёмки (требуется роль employee)
+	// 2. Назначение сотрудника на ПВЗ (требуется роль moderator)
+	tryCreateReceptionAtUnassignedPVZ(t, testApp, testCfg, pvzID)
+	assignEmployeeToPVZ(t, testApp, testCfg, pvzID)
+
+	// 3. Добавление приёмки (требуется роль employee)
 	receptionID := createReceptionAsEmployee(t, testApp, testCfg, pvzID)
 	assert.NotEmpty(t, receptionID)
 
-	// 3. Добавление товаров (требуется роль employee)
+	// 4. Добавление товаров (требуется роль employee)
 	productIDs := addProductsAsEmployee(t, testApp, testCfg, pvzID, 50)
 	assert.Len(t, productIDs, 50)
 
-	// 4. Закрытие приёмки (требуется роль employee)
+	// 5. Закрытие приёмки (требуется роль employee)
 	closedReception := closeReceptionAsEmployee(t, testApp, testCfg, pvzID)
 	assert.Equal(t, "close", closedReception.Status)
 	assert.NotNil(t, closedReception.ClosedAt)
 
-	// 5. Попытка создания ПВЗ с ролью employee (должна завершиться ошибкой)
+	// 6. Попытка создания ПВЗ с ролью employee (должна завершиться ошибкой)
 	tryCreatePVZAsEmployee(t, testApp, testCfg)
 }
 
+// Пользователи, создаваемые в applyMigrations; токены выписываются на их идентификаторы
+var testUserIDs = map[string]string{
+	"moderator": "00000000-0000-0000-0000-00000000000a",
+	"employee":  "00000000-0000-0000-0000-00000000000e",
+}
+
 func generateTokenWithRole(role string, secret string) (string, error) {
 	claims := jwt.MapClaims{
-		"userId": "test-user",
+		"userId": testUserIDs[role],
 		"role":   role,
 		"exp":    time.Now().Add(time.Hour * 1).Unix(),
 	}
@@ -154,49 +173,27 @@ func applyMigrations(t *testing.T, db *sql.DB) {
 	_, err := db.Exec(`CREATE EXTENSION IF NOT EXISTS "pgcrypto"`)
 	assert.NoError(t, err, "Не удалось подключить расширение pgcrypto")
 
+	migrator, err := migrate.New(db, migrations.FS)
+	assert.NoError(t, err, "Не удалось загрузить миграции")
+	err = migrator.Up(context.Background())
+	assert.NoError(t, err, "Не удалось применить миграции")
+
 	_, err = db.Exec(`
-		CREATE TABLE IF NOT EXISTS users (
-			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-			email TEXT UNIQUE NOT NULL,
-			password TEXT NOT NULL,
-			role TEXT NOT NULL CHECK (role IN ('employee', 'moderator')),
-			created_at TIMESTAMP DEFAULT NOW()
-		);
-
-		CREATE TABLE IF NOT EXISTS pvz (
-			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-			city TEXT NOT NULL CHECK (city IN ('Москва', 'Санкт-Петербург', 'Казань')),
-			registration_date TIMESTAMP DEFAULT NOW()
-		);
-
-		CREATE TABLE IF NOT EXISTS receptions (
-			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-			pvz_id UUID REFERENCES pvz(id),
-			status TEXT NOT NULL CHECK (status IN ('in_progress', 'close')),
-			created_at TIMESTAMP DEFAULT NOW(),
-			closed_at TIMESTAMP
-		);
-
-		CREATE TABLE IF NOT EXISTS products (
-			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-			reception_id UUID REFERENCES receptions(id),
-			type TEXT NOT NULL CHECK (type IN ('электроника', 'одежда', 'обувь')),
-			created_at TIMESTAMP DEFAULT NOW()
-		);
-
-		INSERT INTO users (email, password, role) VALUES (
+		INSERT INTO users (id, email, password, role) VALUES (
+			'00000000-0000-0000-0000-00000000000a',
 			'moderator@test.com',
 			crypt('moderator123', gen_salt('bf')),
 			'moderator'
 		) ON CONFLICT DO NOTHING;
 
-		INSERT INTO users (email, password, role) VALUES (
+		INSERT INTO users (id, email, password, role) VALUES (
+			'00000000-0000-0000-0000-00000000000e',
 			'employee@test.com',
 			crypt('employee123', gen_salt('bf')),
 			'employee'
 		) ON CONFLICT DO NOTHING;
 	`)
-	assert.NoError(t, err, "Не удалось применить миграции")
+	assert.NoError(t, err, "Не удалось создать тестовых пользователей")
 	t.Log("Миграции успешно применены")
 }
 
@@ -226,6 +223,33 @@ func createPVZAsModerator(t *testing.T, app *fiber.App, cfg config.Config) strin
 	return pvz.ID
 }
 
+func assignEmployeeToPVZ(t *testing.T, app *fiber.App, cfg config.Config, pvzID string) {
+	token, err := generateTokenWithRole("moderator", cfg.JWTSecret)
+	assert.NoError(t, err)
+
+	t.Log("Назначение сотрудника на ПВЗ...")
+	req := httptest.NewRequest("PUT", fmt.Sprintf("/users/%s/pvz/%s", testUserIDs["employee"], pvzID), nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func tryCreateReceptionAtUnassignedPVZ(t *testing.T, app *fiber.App, cfg config.Config, pvzID string) {
+	token, err := generateTokenWithRole("employee", cfg.JWTSecret)
+	assert.NoError(t, err)
+
+	t.Log("Попытка создания приёмки в ПВЗ без назначения (должна завершиться ошибкой)...")
+	req := httptest.NewRequest("POST", "/receptions", strings.NewReader(fmt.Sprintf(`{"pvzId": "%s"}`, pvzID)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
 func createReceptionAsEmployee(t *testing.T, app *fiber.App, cfg config.Config, pvzID string) string {
 	token, err := generateTokenWithRole("employee", cfg.JWTSecret)
 	assert.NoError(t, err)
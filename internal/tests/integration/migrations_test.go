@@ -0,0 +1,71 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/migrate"
+	"pvz-service/migrations"
+)
+
+func TestMigrationsUpgradeFromBaseline(t *testing.T) {
+	t.Log("=== Проверка обновления базы, созданной исходным init.sql ===")
+
+	ctx := context.Background()
+	postgresContainer, dsn := setupTestDB(ctx, t)
+	defer postgresContainer.Terminate(ctx)
+
+	testDB := connectToTestDB(t, dsn)
+	defer testDB.Close()
+
+	migrator, err := migrate.New(testDB, migrations.FS)
+	assert.NoError(t, err)
+
+	t.Log("Применение только начальной схемы...")
+	assert.NoError(t, migrator.To(ctx, 1))
+
+	t.Log("Заполнение базы данными в исходной схеме...")
+	_, err = testDB.ExecContext(ctx, `
+		INSERT INTO users (id, email, password, role) VALUES
+			('00000000-0000-0000-0000-0000000000b1', 'old@test.com', 'hash', 'employee');
+		INSERT INTO pvz (id, city) VALUES ('00000000-0000-0000-0000-0000000000b2', 'Казань');
+		INSERT INTO receptions (id, pvz_id, status) VALUES
+			('00000000-0000-0000-0000-0000000000b3', '00000000-0000-0000-0000-0000000000b2', 'in_progress');
+		INSERT INTO products (reception_id, type) VALUES ('00000000-0000-0000-0000-0000000000b3', 'обувь');
+	`)
+	assert.NoError(t, err)
+
+	t.Log("Применение остальных миграций...")
+	assert.NoError(t, migrator.Up(ctx))
+
+	version, err := migrator.Version(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, migrator.Latest(), version)
+
+	var disabled bool
+	err = testDB.QueryRowContext(ctx, `SELECT disabled FROM users WHERE email = 'old@test.com'`).Scan(&disabled)
+	assert.NoError(t, err)
+	assert.False(t, disabled)
+
+	var attributes string
+	err = testDB.QueryRowContext(ctx, `SELECT attributes::text FROM products`).Scan(&attributes)
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", attributes)
+
+	t.Log("Проверка новых ограничений на старых данных...")
+	_, err = testDB.ExecContext(ctx, `UPDATE users SET role = 'admin' WHERE email = 'old@test.com'`)
+	assert.NoError(t, err)
+	_, err = testDB.ExecContext(ctx, `INSERT INTO receptions (pvz_id, status)
+		VALUES ('00000000-0000-0000-0000-0000000000b2', 'in_progress')`)
+	assert.Error(t, err, "вторая открытая приёмка должна отклоняться индексом")
+	_, err = testDB.ExecContext(ctx, `INSERT INTO pvz (city) VALUES ('Самара')`)
+	assert.Error(t, err, "город вне справочника должен отклоняться")
+
+	t.Log("Откат всех миграций...")
+	_, err = testDB.ExecContext(ctx, `UPDATE users SET role = 'employee' WHERE email = 'old@test.com'`)
+	assert.NoError(t, err)
+	assert.NoError(t, migrator.To(ctx, 0))
+	t.Log("Обновление и откат миграций выполнены успешно")
+}
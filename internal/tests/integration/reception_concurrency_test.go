@@ -0,0 +1,96 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/cmd/app"
+	"pvz-service/internal/config"
+	"pvz-service/internal/events"
+	"pvz-service/internal/health"
+	"pvz-service/internal/jwtkeys"
+	"pvz-service/internal/passwordpolicy"
+)
+
+func TestConcurrentReceptionCreation(t *testing.T) {
+	t.Log("=== Проверка единственной открытой приёмки при параллельных запросах ===")
+
+	ctx := context.Background()
+	postgresContainer, dsn := setupTestDB(ctx, t)
+	defer postgresContainer.Terminate(ctx)
+
+	testDB := connectToTestDB(t, dsn)
+	defer testDB.Close()
+
+	applyMigrations(t, testDB)
+
+	testCfg := config.Config{
+		Env:       config.EnvTest,
+		JWTSecret: "test-secret",
+	}
+
+	testApp := app.MakeApp(testDB, testCfg, events.NewBroker(0, 0), jwtkeys.NewHMACKeySet(testCfg.JWTSecret),
+		passwordpolicy.New(8, 2, nil), health.NewChecker(time.Second), slog.Default())
+
+	pvzID := createPVZAsModerator(t, testApp, testCfg)
+	assert.NotEmpty(t, pvzID)
+	assignEmployeeToPVZ(t, testApp, testCfg, pvzID)
+
+	token, err := generateTokenWithRole("employee", testCfg.JWTSecret)
+	assert.NoError(t, err)
+
+	const parallelRequests = 20
+	statuses := make(chan int, parallelRequests)
+	start := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < parallelRequests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			req := httptest.NewRequest("POST", "/receptions", strings.NewReader(fmt.Sprintf(`{"pvzId": "%s"}`, pvzID)))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+			<-start
+			resp, err := testApp.Test(req, -1)
+			if err != nil {
+				t.Errorf("ошибка запроса: %v", err)
+				return
+			}
+			statuses <- resp.StatusCode
+		}()
+	}
+
+	t.Logf("Отправка %d параллельных запросов на создание приёмки...", parallelRequests)
+	close(start)
+	wg.Wait()
+	close(statuses)
+
+	created := 0
+	for status := range statuses {
+		if status == http.StatusCreated {
+			created++
+			continue
+		}
+		assert.Equal(t, http.StatusBadRequest, status)
+	}
+	assert.Equal(t, 1, created, "должна быть создана ровно одна приёмка")
+
+	var openReceptions int
+	err = testDB.QueryRow(
+		"SELECT COUNT(*) FROM receptions WHERE pvz_id = $1 AND status = 'in_progress'", pvzID,
+	).Scan(&openReceptions)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, openReceptions)
+}
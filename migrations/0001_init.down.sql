@@ -0,0 +1,5 @@
+-- Откат начальной схемы, таблицы удаляются в порядке, обратном зависимостям
+DROP TABLE IF EXISTS products;
+DROP TABLE IF EXISTS receptions;
+DROP TABLE IF EXISTS pvz;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,6 @@
+-- Откат не пройдёт, если есть ПВЗ в городах вне исходного списка
+ALTER TABLE pvz DROP CONSTRAINT IF EXISTS pvz_city_fkey;
+ALTER TABLE pvz ADD CONSTRAINT pvz_city_check
+    CHECK (city IN ('Москва', 'Санкт-Петербург', 'Казань'));
+
+DROP TABLE IF EXISTS cities;
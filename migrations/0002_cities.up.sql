@@ -0,0 +1,15 @@
+-- Справочник городов
+CREATE TABLE IF NOT EXISTS cities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT UNIQUE NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+INSERT INTO cities (name) VALUES ('Москва'), ('Санкт-Петербург'), ('Казань')
+ON CONFLICT (name) DO NOTHING;
+
+-- Город ПВЗ проверяется по справочнику вместо фиксированного списка
+ALTER TABLE pvz DROP CONSTRAINT IF EXISTS pvz_city_check;
+ALTER TABLE pvz ADD CONSTRAINT pvz_city_fkey
+    FOREIGN KEY (city) REFERENCES cities(name) ON UPDATE CASCADE;
@@ -0,0 +1,7 @@
+-- Откат не пройдёт, если есть товары типов вне исходного списка; атрибуты товаров теряются
+ALTER TABLE products DROP COLUMN IF EXISTS attributes;
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_type_fkey;
+ALTER TABLE products ADD CONSTRAINT products_type_check
+    CHECK (type IN ('электроника', 'одежда', 'обувь'));
+
+DROP TABLE IF EXISTS product_types;
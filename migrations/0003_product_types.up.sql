@@ -0,0 +1,20 @@
+-- Справочник типов товаров
+CREATE TABLE IF NOT EXISTS product_types (
+    code TEXT PRIMARY KEY,
+    display_name TEXT NOT NULL,
+    required_attributes TEXT[] NOT NULL DEFAULT '{}',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+INSERT INTO product_types (code, display_name) VALUES
+    ('электроника', 'Электроника'),
+    ('одежда', 'Одежда'),
+    ('обувь', 'Обувь')
+ON CONFLICT (code) DO NOTHING;
+
+-- Тип товара проверяется по справочнику, атрибуты задаются типом
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_type_check;
+ALTER TABLE products ADD CONSTRAINT products_type_fkey
+    FOREIGN KEY (type) REFERENCES product_types(code) ON UPDATE CASCADE;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
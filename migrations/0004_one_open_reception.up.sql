@@ -0,0 +1,3 @@
+-- Не более одной открытой приёмки на ПВЗ
+CREATE UNIQUE INDEX IF NOT EXISTS receptions_one_open_per_pvz
+    ON receptions (pvz_id) WHERE status = 'in_progress';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS products_reception_id;
+DROP INDEX IF EXISTS receptions_pvz_id_created_at;
+DROP INDEX IF EXISTS pvz_city;
+DROP INDEX IF EXISTS pvz_registration_date_id;
@@ -0,0 +1,5 @@
+-- Индексы для списка ПВЗ с фильтрами и сортировкой
+CREATE INDEX IF NOT EXISTS pvz_registration_date_id ON pvz (registration_date, id);
+CREATE INDEX IF NOT EXISTS pvz_city ON pvz (city);
+CREATE INDEX IF NOT EXISTS receptions_pvz_id_created_at ON receptions (pvz_id, created_at);
+CREATE INDEX IF NOT EXISTS products_reception_id ON products (reception_id);
@@ -0,0 +1,7 @@
+-- Закрепление сотрудников за ПВЗ: сотрудник работает только с назначенными ему ПВЗ
+CREATE TABLE IF NOT EXISTS user_pvz_assignments (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    pvz_id UUID NOT NULL REFERENCES pvz(id) ON DELETE CASCADE,
+    assigned_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (user_id, pvz_id)
+);
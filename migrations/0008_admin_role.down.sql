@@ -0,0 +1,4 @@
+-- Откат не пройдёт, пока есть пользователи с ролью admin
+ALTER TABLE users DROP COLUMN IF EXISTS disabled;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('employee', 'moderator'));
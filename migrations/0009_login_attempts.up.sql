@@ -0,0 +1,7 @@
+-- Неудачные попытки входа по email и IP-адресу, ключ блокируется до locked_until
+CREATE TABLE IF NOT EXISTS login_attempts (
+    key TEXT PRIMARY KEY,
+    failures INT NOT NULL DEFAULT 0,
+    window_started_at TIMESTAMP NOT NULL,
+    locked_until TIMESTAMP
+);
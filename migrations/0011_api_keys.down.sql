@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS api_key_pvzs;
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,20 @@
+-- API-ключи машинных клиентов, хранится только хэш ключа
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL,
+    key_hash TEXT NOT NULL UNIQUE,
+    role VARCHAR(50) NOT NULL CHECK (role IN ('employee', 'moderator')),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
+
+-- ПВЗ, к которым привязан API-ключ; ключ без привязок действует для всех ПВЗ
+CREATE TABLE IF NOT EXISTS api_key_pvzs (
+    api_key_id UUID NOT NULL REFERENCES api_keys(id) ON DELETE CASCADE,
+    pvz_id UUID NOT NULL REFERENCES pvz(id) ON DELETE CASCADE,
+    PRIMARY KEY (api_key_id, pvz_id)
+);
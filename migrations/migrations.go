@@ -0,0 +1,9 @@
+// Package migrations embeds the numbered SQL migrations applied by internal/migrate.
+package migrations
+
+import "embed"
+
+// FS holds <version>_<name>.up.sql and <version>_<name>.down.sql pairs.
+//
+//go:embed *.sql
+var FS embed.FS